@@ -0,0 +1,102 @@
+package fastjson
+
+import (
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Aggregation contains numeric aggregates returned by Aggregate.
+type Aggregation struct {
+	// Count is the number of aggregated numbers.
+	Count int
+
+	// Sum is the sum of aggregated numbers.
+	Sum float64
+
+	// Min is the minimum aggregated number. It is 0 if Count is 0.
+	Min float64
+
+	// Max is the maximum aggregated number. It is 0 if Count is 0.
+	Max float64
+}
+
+// Mean returns the arithmetic mean of aggregated numbers.
+//
+// 0 is returned if no numbers were aggregated.
+func (a *Aggregation) Mean() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// Aggregate computes Count, Sum, Min and Max over numbers at the given path
+// in v in a single traversal.
+//
+// Path keys are matched as in Get, with the following wildcards:
+//
+//   - "*" matches all the object values and array items at the given level.
+//   - "**" matches zero or more levels of nesting, like in GetAll.
+//
+// For example, Aggregate(v, "items", "*", "price") aggregates "price" values
+// of all the "items" array items. Non-number values at the path are skipped.
+func Aggregate(v *Value, path ...string) Aggregation {
+	var a Aggregation
+	if v == nil {
+		return a
+	}
+	v.walkWildcardPath(path, func(v *Value) {
+		if v.t == TypeNumber {
+			a.add(fastfloat.ParseBestEffort(v.s))
+		}
+	})
+	return a
+}
+
+// walkWildcardPath calls f for each value matching the given keys path
+// with "*" and "**" wildcards. See Aggregate for details.
+func (v *Value) walkWildcardPath(keys []string, f func(v *Value)) {
+	for len(keys) > 0 && keys[0] != "*" && keys[0] != "**" {
+		v = v.Get(keys[0])
+		if v == nil {
+			return
+		}
+		keys = keys[1:]
+	}
+	if len(keys) == 0 {
+		f(v)
+		return
+	}
+
+	tail := keys[1:]
+	if keys[0] == "**" {
+		// Skip repeated "**" keys, since they match the same values.
+		for len(tail) > 0 && tail[0] == "**" {
+			keys = tail
+			tail = tail[1:]
+		}
+		v.walkWildcardPath(tail, f)
+		// Descend with the "**" key kept in order to match deeper levels.
+		tail = keys
+	}
+	switch v.t {
+	case TypeObject:
+		for _, kv := range v.o.kvs {
+			kv.v.walkWildcardPath(tail, f)
+		}
+	case TypeArray:
+		for _, vv := range v.a {
+			vv.walkWildcardPath(tail, f)
+		}
+	}
+}
+
+func (a *Aggregation) add(f float64) {
+	if a.Count == 0 || f < a.Min {
+		a.Min = f
+	}
+	if a.Count == 0 || f > a.Max {
+		a.Max = f
+	}
+	a.Sum += f
+	a.Count++
+}
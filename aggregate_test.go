@@ -0,0 +1,41 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	f := func(s string, path []string, aExpected Aggregation, meanExpected float64) {
+		t.Helper()
+		v := MustParse(s)
+		a := Aggregate(v, path...)
+		if a != aExpected {
+			t.Fatalf("unexpected aggregation for %q over %s; got %+v; want %+v", path, s, a, aExpected)
+		}
+		if mean := a.Mean(); mean != meanExpected {
+			t.Fatalf("unexpected mean for %q over %s; got %v; want %v", path, s, mean, meanExpected)
+		}
+	}
+
+	items := `{"items":[{"price":3},{"price":-1.5},{"price":"7"},{"name":"x"},{"price":4.5}]}`
+	f(items, []string{"items", "*", "price"}, Aggregation{Count: 3, Sum: 6, Min: -1.5, Max: 4.5}, 2)
+	f(items, []string{"items", "0", "price"}, Aggregation{Count: 1, Sum: 3, Min: 3, Max: 3}, 3)
+	f(items, []string{"items", "*", "missing"}, Aggregation{}, 0)
+	f(items, []string{"**", "price"}, Aggregation{Count: 3, Sum: 6, Min: -1.5, Max: 4.5}, 2)
+	f(items, []string{"**", "**", "price"}, Aggregation{Count: 3, Sum: 6, Min: -1.5, Max: 4.5}, 2)
+
+	// Object values
+	f(`{"a":{"x":1,"y":2,"z":[3]}}`, []string{"a", "*"}, Aggregation{Count: 2, Sum: 3, Min: 1, Max: 2}, 1.5)
+
+	// Recursive descent matches numbers at any depth
+	f(`{"a":1,"b":[2,{"c":3}]}`, []string{"**"}, Aggregation{Count: 3, Sum: 6, Min: 1, Max: 3}, 2)
+	f(`{"n":{"n":2}}`, []string{"**", "n"}, Aggregation{Count: 1, Sum: 2, Min: 2, Max: 2}, 2)
+
+	// Single number
+	f(`42`, nil, Aggregation{Count: 1, Sum: 42, Min: 42, Max: 42}, 42)
+	f(`"42"`, nil, Aggregation{}, 0)
+
+	if a := Aggregate(nil, "x"); a.Count != 0 {
+		t.Fatalf("unexpected aggregation for nil value: %+v", a)
+	}
+}
@@ -1,7 +1,9 @@
 package fastjson
 
 import (
+	"encoding/base64"
 	"strconv"
+	"time"
 )
 
 // Arena may be used for fast creation and re-use of Values.
@@ -76,14 +78,73 @@ func (a *Arena) NewStringBytes(b []byte) *Value {
 	return v
 }
 
+// NewStringCopy returns new string value containing a copy of s.
+//
+// Unlike NewString, s is stored unescaped, so escaping is deferred
+// until marshaling. This is faster when the returned string is mostly
+// read via StringBytes rather than marshaled.
+//
+// The returned string is valid until Reset is called on a.
+func (a *Arena) NewStringCopy(s string) *Value {
+	v := a.c.getValue()
+	v.t = TypeString
+	bLen := len(a.b)
+	a.b = append(a.b, s...)
+	v.s = b2s(a.b[bLen:])
+	return v
+}
+
+// NewTime returns new string value containing t formatted according to layout.
+//
+// See time.Time.Format for layout details.
+//
+// The returned string is valid until Reset is called on a.
+func (a *Arena) NewTime(t time.Time, layout string) *Value {
+	bLen := len(a.b)
+	a.b = t.AppendFormat(a.b, layout)
+	s := b2s(a.b[bLen:])
+	if hasSpecialChars(s) {
+		// Slow path - the layout contains chars, which must be escaped.
+		s = string(a.b[bLen:])
+		a.b = a.b[:bLen]
+		return a.NewString(s)
+	}
+	v := a.c.getValue()
+	v.t = typeRawString
+	v.s = s
+	return v
+}
+
+// NewBytesBase64 returns new string value containing base64-encoded b.
+//
+// The standard base64 encoding with padding is used.
+//
+// The returned string is valid until Reset is called on a.
+func (a *Arena) NewBytesBase64(b []byte) *Value {
+	v := a.c.getValue()
+	v.t = typeRawString
+	bLen := len(a.b)
+	n := base64.StdEncoding.EncodedLen(len(b))
+	for cap(a.b)-bLen < n {
+		a.b = append(a.b[:cap(a.b)], 0)
+	}
+	a.b = a.b[:bLen+n]
+	base64.StdEncoding.Encode(a.b[bLen:], b)
+	v.s = b2s(a.b[bLen:])
+	return v
+}
+
 // NewNumberFloat64 returns new number value containing f.
 //
+// f is formatted in the shortest form, which round-trips to f,
+// so the result is deterministic.
+//
 // The returned number is valid until Reset is called on a.
 func (a *Arena) NewNumberFloat64(f float64) *Value {
 	v := a.c.getValue()
 	v.t = TypeNumber
 	bLen := len(a.b)
-	a.b = strconv.AppendFloat(a.b, f, 'g', -1, 64)
+	a.b = appendShortestFloat64(a.b, f)
 	v.s = b2s(a.b[bLen:])
 	return v
 }
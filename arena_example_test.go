@@ -0,0 +1,35 @@
+package fastjson_test
+
+import (
+	"fmt"
+
+	"github.com/valyala/fastjson"
+)
+
+func ExampleArena() {
+	var a fastjson.Arena
+	for i := 0; i < 2; i++ {
+		// Build {"id":<i>,"name":"foo","tags":["x",1.5,true],"extra":null}
+		// from scratch without parsing.
+		o := a.NewObject()
+		o.Set("id", a.NewNumberInt(i))
+		o.Set("name", a.NewString("foo"))
+
+		tags := a.NewArray()
+		tags.SetArrayItem(0, a.NewStringBytes([]byte("x")))
+		tags.SetArrayItem(1, a.NewNumberFloat64(1.5))
+		tags.SetArrayItem(2, a.NewTrue())
+		o.Set("tags", tags)
+		o.Set("extra", a.NewNull())
+
+		fmt.Printf("%s\n", o.MarshalTo(nil))
+
+		// Re-use the memory occupied by the constructed values
+		// on the next iteration.
+		a.Reset()
+	}
+
+	// Output:
+	// {"id":0,"name":"foo","tags":["x",1.5,true],"extra":null}
+	// {"id":1,"name":"foo","tags":["x",1.5,true],"extra":null}
+}
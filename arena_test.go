@@ -75,3 +75,29 @@ func testArena(a *Arena) error {
 	}
 	return nil
 }
+
+func TestArenaNewTypedStrings(t *testing.T) {
+	var a Arena
+	tm := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	o := a.NewObject()
+	o.Set("t", a.NewTime(tm, time.RFC3339))
+	o.Set("quoted", a.NewTime(tm, `"2006"`))
+	o.Set("b64", a.NewBytesBase64([]byte("hello, world")))
+	o.Set("empty", a.NewBytesBase64(nil))
+	buf := []byte("foo\nbar")
+	o.Set("copy", a.NewStringCopy(string(buf)))
+	buf[0] = 'x'
+
+	s := o.String()
+	sExpected := `{"t":"2020-03-04T05:06:07Z","quoted":"\"2020\"","b64":"aGVsbG8sIHdvcmxk","empty":"","copy":"foo\nbar"}`
+	if s != sExpected {
+		t.Fatalf("unexpected json\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+	if sb := o.GetStringBytes("copy"); string(sb) != "foo\nbar" {
+		t.Fatalf("unexpected string copy; got %q; want %q", sb, "foo\nbar")
+	}
+	if sb := o.GetStringBytes("quoted"); string(sb) != `"2020"` {
+		t.Fatalf("unexpected time string; got %q; want %q", sb, `"2020"`)
+	}
+}
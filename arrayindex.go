@@ -0,0 +1,61 @@
+package fastjson
+
+// BuildIndex indexes arr items by the value at the given keyPath.
+//
+// Items are looked up via Get(keyPath...). String values are indexed
+// by their unescaped contents, while numbers, true and false are indexed
+// by their JSON representation, so {"id":1} is indexed by "1".
+// Items without scalar values at keyPath or with null values are skipped.
+// The first item wins for duplicate keys, like Object.Get does.
+//
+// The returned map refers to arr items, so it is valid until Parse is called
+// on the Parser returned arr items.
+func BuildIndex(arr []*Value, keyPath ...string) map[string]*Value {
+	m := make(map[string]*Value, len(arr))
+	for _, v := range arr {
+		key, ok := indexKey(v, keyPath)
+		if !ok {
+			continue
+		}
+		if _, ok := m[key]; !ok {
+			m[key] = v
+		}
+	}
+	return m
+}
+
+// BuildMultiIndex indexes arr items by the value at the given keyPath
+// and keeps all the items for duplicate keys.
+//
+// Items for every key are stored in the order of arr.
+// See BuildIndex for details on keys.
+func BuildMultiIndex(arr []*Value, keyPath ...string) map[string][]*Value {
+	m := make(map[string][]*Value)
+	for _, v := range arr {
+		key, ok := indexKey(v, keyPath)
+		if !ok {
+			continue
+		}
+		m[key] = append(m[key], v)
+	}
+	return m
+}
+
+func indexKey(v *Value, keyPath []string) (string, bool) {
+	kv := v.Get(keyPath...)
+	if kv == nil {
+		return "", false
+	}
+	switch kv.Type() {
+	case TypeString:
+		return kv.s, true
+	case TypeNumber:
+		return kv.s, true
+	case TypeTrue:
+		return "true", true
+	case TypeFalse:
+		return "false", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,43 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestBuildIndex(t *testing.T) {
+	v := MustParse(`[
+		{"id":1,"user":{"name":"a"}},
+		{"id":"2","user":{"name":"bb"}},
+		{"id":1,"user":{"name":"a"},"dup":true},
+		{"id":null},
+		{"id":{"x":1}},
+		{"user":{"name":true}},
+		5
+	]`)
+	arr := v.GetArray()
+
+	m := BuildIndex(arr, "id")
+	if len(m) != 2 {
+		t.Fatalf("unexpected index size; got %d; want 2", len(m))
+	}
+	if s := m["1"].String(); s != `{"id":1,"user":{"name":"a"}}` {
+		t.Fatalf("unexpected item for id=1; got %s", s)
+	}
+	if s := m["2"].String(); s != `{"id":"2","user":{"name":"bb"}}` {
+		t.Fatalf("unexpected item for id=2; got %s", s)
+	}
+
+	m = BuildIndex(arr, "user", "name")
+	if len(m) != 3 || m["a"] != arr[0] || m["bb"] != arr[1] || m["true"] != arr[5] {
+		t.Fatalf("unexpected index by nested key: %v", m)
+	}
+
+	mm := BuildMultiIndex(arr, "id")
+	if len(mm) != 2 || len(mm["1"]) != 2 || mm["1"][0] != arr[0] || mm["1"][1] != arr[2] || len(mm["2"]) != 1 {
+		t.Fatalf("unexpected multi index: %v", mm)
+	}
+
+	if m := BuildIndex(nil, "id"); len(m) != 0 {
+		t.Fatalf("unexpected index for nil array: %v", m)
+	}
+}
@@ -0,0 +1,204 @@
+package fastjson
+
+import (
+	"fmt"
+)
+
+// Builder builds JSON values with chained calls.
+//
+// For example, the following code builds {"a":1,"items":["x"]}:
+//
+//	b := fastjson.NewBuilder()
+//	b.Obj().Key("a").Int(1).Key("items").Arr().Str("x").End().End()
+//	v, err := b.Build()
+//
+// The first error in the chain, such as a value without a key inside
+// an object, is returned from Build and MarshalTo.
+//
+// Builder may be re-used after Reset call.
+//
+// Builder cannot be used from concurrent goroutines.
+type Builder struct {
+	a Arena
+
+	// stack contains the currently open objects and arrays.
+	stack []*Value
+
+	root *Value
+
+	key    string
+	hasKey bool
+
+	err error
+}
+
+// NewBuilder returns new Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Reset resets b, so it may be used for building a new value.
+//
+// Values previously built by b cannot be used after the Reset call.
+func (b *Builder) Reset() {
+	b.a.Reset()
+	for i := range b.stack {
+		b.stack[i] = nil
+	}
+	b.stack = b.stack[:0]
+	b.root = nil
+	b.key = ""
+	b.hasKey = false
+	b.err = nil
+}
+
+// Obj starts new object. It must be finished with End call.
+func (b *Builder) Obj() *Builder {
+	v := b.a.NewObject()
+	if b.add(v) {
+		b.stack = append(b.stack, v)
+	}
+	return b
+}
+
+// Arr starts new array. It must be finished with End call.
+func (b *Builder) Arr() *Builder {
+	v := b.a.NewArray()
+	if b.add(v) {
+		b.stack = append(b.stack, v)
+	}
+	return b
+}
+
+// End finishes the last started object or array.
+func (b *Builder) End() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.stack) == 0 {
+		b.err = fmt.Errorf("unexpected End call without opened object or array")
+		return b
+	}
+	if b.hasKey {
+		b.err = fmt.Errorf("missing value for the key %q", b.key)
+		return b
+	}
+	b.stack[len(b.stack)-1] = nil
+	b.stack = b.stack[:len(b.stack)-1]
+	return b
+}
+
+// Key sets the key for the next value in the current object.
+func (b *Builder) Key(key string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.stack) == 0 || b.stack[len(b.stack)-1].t != TypeObject {
+		b.err = fmt.Errorf("unexpected key %q outside object", key)
+		return b
+	}
+	if b.hasKey {
+		b.err = fmt.Errorf("missing value for the key %q", b.key)
+		return b
+	}
+	b.key = key
+	b.hasKey = true
+	return b
+}
+
+// Str adds string value s.
+func (b *Builder) Str(s string) *Builder {
+	b.add(b.a.NewString(s))
+	return b
+}
+
+// Int adds number value n.
+func (b *Builder) Int(n int) *Builder {
+	b.add(b.a.NewNumberInt(n))
+	return b
+}
+
+// Float64 adds number value f.
+func (b *Builder) Float64(f float64) *Builder {
+	b.add(b.a.NewNumberFloat64(f))
+	return b
+}
+
+// Bool adds true or false value.
+func (b *Builder) Bool(x bool) *Builder {
+	if x {
+		b.add(b.a.NewTrue())
+	} else {
+		b.add(b.a.NewFalse())
+	}
+	return b
+}
+
+// Null adds null value.
+func (b *Builder) Null() *Builder {
+	b.add(b.a.NewNull())
+	return b
+}
+
+// Val adds already existing value v.
+//
+// v must be unchanged during the lifetime of the built value.
+func (b *Builder) Val(v *Value) *Builder {
+	if v == nil {
+		v = valueNull
+	}
+	b.add(v)
+	return b
+}
+
+// Build returns the built value.
+//
+// The returned value is valid until Reset is called on b.
+func (b *Builder) Build() (*Value, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.stack) > 0 {
+		return nil, fmt.Errorf("%d objects or arrays must be finished with End call", len(b.stack))
+	}
+	if b.root == nil {
+		return nil, fmt.Errorf("no values were built")
+	}
+	return b.root, nil
+}
+
+// MarshalTo appends the built value to dst and returns the result.
+func (b *Builder) MarshalTo(dst []byte) ([]byte, error) {
+	v, err := b.Build()
+	if err != nil {
+		return dst, err
+	}
+	return v.MarshalTo(dst), nil
+}
+
+func (b *Builder) add(v *Value) bool {
+	if b.err != nil {
+		return false
+	}
+	if len(b.stack) == 0 {
+		if b.root != nil {
+			b.err = fmt.Errorf("unexpected second root value")
+			return false
+		}
+		b.root = v
+		return true
+	}
+	top := b.stack[len(b.stack)-1]
+	if top.t == TypeArray {
+		top.a = append(top.a, v)
+		return true
+	}
+	if !b.hasKey {
+		b.err = fmt.Errorf("missing key for the value inside object")
+		return false
+	}
+	top.o.Set(b.key, v)
+	b.key = ""
+	b.hasKey = false
+	return true
+}
@@ -0,0 +1,63 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder()
+	for i := 0; i < 3; i++ {
+		b.Obj().
+			Key("a").Int(1).
+			Key("items").Arr().Str("x").Float64(1.5).Bool(true).Bool(false).Null().Obj().End().End().
+			Key("quote\"d").Str("a\nb").
+			Key("v").Val(MustParse(`{"nested":[1]}`)).
+			End()
+		v, err := b.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sExpected := `{"a":1,"items":["x",1.5,true,false,null,{}],"quote\"d":"a\nb","v":{"nested":[1]}}`
+		if s := v.String(); s != sExpected {
+			t.Fatalf("unexpected value\ngot\n%s\nwant\n%s", s, sExpected)
+		}
+		dst, err := b.MarshalTo([]byte("x="))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(dst) != "x="+sExpected {
+			t.Fatalf("unexpected marshaled value\ngot\n%s\nwant\n%s", dst, "x="+sExpected)
+		}
+		b.Reset()
+	}
+
+	// Scalar root
+	b.Str("foo")
+	if v, err := b.Build(); err != nil || v.String() != `"foo"` {
+		t.Fatalf("unexpected result: %v, %v", v, err)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	f := func(build func(b *Builder)) {
+		t.Helper()
+		b := NewBuilder()
+		build(b)
+		if v, err := b.Build(); err == nil {
+			t.Fatalf("expecting non-nil error; got %s", v)
+		}
+		if _, err := b.MarshalTo(nil); err == nil {
+			t.Fatalf("expecting non-nil error from MarshalTo")
+		}
+	}
+	f(func(b *Builder) {})
+	f(func(b *Builder) { b.End() })
+	f(func(b *Builder) { b.Obj() })
+	f(func(b *Builder) { b.Obj().Int(1).End() })
+	f(func(b *Builder) { b.Obj().Key("a").End() })
+	f(func(b *Builder) { b.Obj().Key("a").Key("b").Int(1).End() })
+	f(func(b *Builder) { b.Arr().Key("a").Int(1).End() })
+	f(func(b *Builder) { b.Key("a") })
+	f(func(b *Builder) { b.Int(1).Int(2) })
+	f(func(b *Builder) { b.Arr().End().End() })
+}
@@ -0,0 +1,609 @@
+package fastjson
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// CBOROptions contains options for CBOR (RFC 8949) encoding and decoding.
+//
+// The zero CBOROptions decodes byte strings as standard base64
+// and round-trips big integers via CBOR bignums.
+type CBOROptions struct {
+	// ByteStrings controls how CBOR byte strings are decoded into strings.
+	//
+	// Expected conversion tags 21, 22 and 23 override ByteStrings
+	// for the tagged data items.
+	ByteStrings CBORByteStrings
+
+	// BigNumbers controls how integers not fitting CBOR major types 0 and 1
+	// are encoded and how CBOR bignums (tags 2 and 3) are decoded.
+	BigNumbers CBORBigNumbers
+}
+
+// CBORByteStrings is the representation of CBOR byte strings in Values.
+type CBORByteStrings int
+
+const (
+	// CBORByteStringsBase64 decodes byte strings into standard base64 strings with padding.
+	CBORByteStringsBase64 CBORByteStrings = iota
+
+	// CBORByteStringsBase64URL decodes byte strings into base64url strings without padding.
+	CBORByteStringsBase64URL
+
+	// CBORByteStringsHex decodes byte strings into lowercase hex strings.
+	CBORByteStringsHex
+
+	// CBORByteStringsReject rejects byte strings with error.
+	CBORByteStringsReject
+)
+
+// CBORBigNumbers is the representation of integers exceeding 64 bits.
+type CBORBigNumbers int
+
+const (
+	// CBORBigNumbersBignum encodes big integers as CBOR bignums
+	// and decodes bignums into exact JSON numbers.
+	CBORBigNumbersBignum CBORBigNumbers = iota
+
+	// CBORBigNumbersString encodes big integers as CBOR text strings
+	// and decodes bignums into strings containing decimal integers.
+	// This protects consumers, which cannot handle big numbers.
+	CBORBigNumbersString
+
+	// CBORBigNumbersFloat encodes big integers as CBOR floats and decodes
+	// bignums into float64 numbers. The precision may be lost.
+	CBORBigNumbersFloat
+)
+
+const (
+	cborUint     = 0 << 5
+	cborNegInt   = 1 << 5
+	cborBytes    = 2 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+	cborTag      = 6 << 5
+	cborSimple   = 7 << 5
+	cborBreak    = 0xff
+	cborIndefArg = 31
+)
+
+// MarshalCBORTo appends CBOR representation of v to dst and returns the result.
+//
+// The zero CBOROptions are used. See CBOROptions.MarshalTo for details.
+func (v *Value) MarshalCBORTo(dst []byte) ([]byte, error) {
+	var co CBOROptions
+	return co.MarshalTo(dst, v)
+}
+
+// MarshalTo appends CBOR representation of v to dst according to co
+// and returns the result.
+//
+// Integer numbers are encoded as CBOR integers, while the rest of numbers
+// are encoded as the shortest CBOR float, which preserves the float64 value.
+// Strings and object keys are encoded as CBOR text strings.
+// Definite lengths are used for arrays, maps and strings.
+//
+// An error is returned if v contains numbers encoded as floats, which
+// cannot be represented as finite float64 such as 1e400, since they
+// have no JSON representation.
+func (co *CBOROptions) MarshalTo(dst []byte, v *Value) ([]byte, error) {
+	var err error
+	switch v.Type() {
+	case TypeObject:
+		o := &v.o
+		o.unescapeKeys()
+		dst = appendCBORHead(dst, cborMap, uint64(len(o.kvs)))
+		for _, kv := range o.kvs {
+			dst = appendCBORHead(dst, cborText, uint64(len(kv.k)))
+			dst = append(dst, kv.k...)
+			if dst, err = co.MarshalTo(dst, kv.v); err != nil {
+				return dst, err
+			}
+		}
+		return dst, nil
+	case TypeArray:
+		dst = appendCBORHead(dst, cborArray, uint64(len(v.a)))
+		for _, vv := range v.a {
+			if dst, err = co.MarshalTo(dst, vv); err != nil {
+				return dst, err
+			}
+		}
+		return dst, nil
+	case TypeString:
+		dst = appendCBORHead(dst, cborText, uint64(len(v.s)))
+		return append(dst, v.s...), nil
+	case TypeNumber:
+		return co.appendNumber(dst, v.s)
+	case TypeTrue:
+		return append(dst, cborSimple|21), nil
+	case TypeFalse:
+		return append(dst, cborSimple|20), nil
+	case TypeNull:
+		return append(dst, cborSimple|22), nil
+	default:
+		panic(fmt.Errorf("BUG: unexpected Value type: %d", v.t))
+	}
+}
+
+func (co *CBOROptions) appendNumber(dst []byte, s string) ([]byte, error) {
+	if strings.ContainsAny(s, ".eE") {
+		return appendCBORNumberFloat(dst, s)
+	}
+	if n, err := fastfloat.ParseInt64(s); err == nil {
+		if n < 0 {
+			return appendCBORHead(dst, cborNegInt, uint64(-1-n)), nil
+		}
+		return appendCBORHead(dst, cborUint, uint64(n)), nil
+	}
+	if n, err := fastfloat.ParseUint64(s); err == nil {
+		return appendCBORHead(dst, cborUint, n), nil
+	}
+	var bi big.Int
+	if _, ok := bi.SetString(s, 10); !ok {
+		return appendCBORNumberFloat(dst, s)
+	}
+	neg := bi.Sign() < 0
+	if neg {
+		// Negative CBOR integers hold -1-n.
+		bi.Neg(&bi)
+		bi.Sub(&bi, big.NewInt(1))
+		if bi.IsUint64() {
+			return appendCBORHead(dst, cborNegInt, bi.Uint64()), nil
+		}
+	}
+	switch co.BigNumbers {
+	case CBORBigNumbersString:
+		dst = appendCBORHead(dst, cborText, uint64(len(s)))
+		return append(dst, s...), nil
+	case CBORBigNumbersFloat:
+		return appendCBORNumberFloat(dst, s)
+	default:
+		tag := uint64(2)
+		if neg {
+			tag = 3
+		}
+		b := bi.Bytes()
+		dst = appendCBORHead(dst, cborTag, tag)
+		dst = appendCBORHead(dst, cborBytes, uint64(len(b)))
+		return append(dst, b...), nil
+	}
+}
+
+// appendCBORNumberFloat appends the number s to dst as CBOR float.
+func appendCBORNumberFloat(dst []byte, s string) ([]byte, error) {
+	f := fastfloat.ParseBestEffort(s)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, fmt.Errorf("cannot marshal number %q to CBOR: it cannot be represented as finite float64", s)
+	}
+	return appendCBORFloat(dst, f), nil
+}
+
+// appendCBORHead appends the head of data item with the given major type
+// and argument n to dst.
+func appendCBORHead(dst []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, major|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(dst, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		dst = append(dst, major|27)
+		return appendUint64BE(dst, n)
+	}
+}
+
+// appendCBORFloat appends f to dst using the shortest float encoding,
+// which preserves f.
+func appendCBORFloat(dst []byte, f float64) []byte {
+	if h, ok := float64ToFloat16(f); ok {
+		return append(dst, cborSimple|25, byte(h>>8), byte(h))
+	}
+	if f32 := float32(f); float64(f32) == f || math.IsNaN(f) {
+		n := math.Float32bits(f32)
+		return append(dst, cborSimple|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	dst = append(dst, cborSimple|27)
+	return appendUint64BE(dst, math.Float64bits(f))
+}
+
+// float64ToFloat16 returns IEEE 754 half-precision bits for f
+// if f may be represented exactly as half-precision float.
+func float64ToFloat16(f float64) (uint16, bool) {
+	f32 := float32(f)
+	if float64(f32) != f && !math.IsNaN(f) {
+		return 0, false
+	}
+	bits := math.Float32bits(f32)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+	switch {
+	case exp == 0xff:
+		if mant == 0 {
+			return sign | 0x7c00, true
+		}
+		return 0x7e00, true
+	case exp == 0 && mant == 0:
+		return sign, true
+	case exp == 0:
+		// float32 subnormals are too small for float16.
+		return 0, false
+	}
+	e := exp - 127
+	switch {
+	case e >= -14 && e <= 15:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(e+15)<<10 | uint16(mant>>13), true
+	case e >= -24 && e < -14:
+		m := mant | 0x800000
+		shift := uint(-e - 1)
+		if m&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(m>>shift), true
+	default:
+		return 0, false
+	}
+}
+
+// float16ToFloat64 converts IEEE 754 half-precision bits h to float64.
+func float16ToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// ParseCBOR parses b containing a single CBOR data item.
+//
+// The zero CBOROptions are used.
+// The function is slower than the Parser.ParseCBOR for re-used Parser.
+func ParseCBOR(b []byte) (*Value, error) {
+	var p Parser
+	return p.ParseCBOR(b, nil)
+}
+
+// ParseCBOR parses b containing a single CBOR data item according to co.
+//
+// The zero CBOROptions are used if co is nil.
+//
+// Map keys must be text strings or integers. Integer keys are converted
+// to decimal strings. The undefined value is converted to null.
+// Unknown tags are ignored, so only the tagged data items are returned.
+// Non-finite floats, simple values other than false, true, null and
+// undefined, and trailing bytes after the data item result in error.
+//
+// The returned value is valid until the next call to Parse*.
+func (p *Parser) ParseCBOR(b []byte, co *CBOROptions) (*Value, error) {
+	if co == nil {
+		co = &CBOROptions{}
+	}
+	p.b = append(p.b[:0], b...)
+	p.c.reset()
+
+	d := cborDecoder{
+		c:          &p.c,
+		bigNumbers: co.BigNumbers,
+	}
+	s := b2s(p.b)
+	v, tail, err := d.parseValue(s, 0, co.ByteStrings)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CBOR at offset %d: %s", len(s)-len(tail), err)
+	}
+	if len(tail) > 0 {
+		return nil, fmt.Errorf("unexpected tail after CBOR data item at offset %d: %d bytes", len(s)-len(tail), len(tail))
+	}
+	return v, nil
+}
+
+type cborDecoder struct {
+	c          *cache
+	bigNumbers CBORBigNumbers
+}
+
+// readCBORHead reads the head of the data item from s.
+//
+// indefinite is set for indefinite-length items.
+func readCBORHead(s string) (major byte, n uint64, indefinite bool, tail string, err error) {
+	if len(s) == 0 {
+		return 0, 0, false, s, fmt.Errorf("unexpected end of data")
+	}
+	major = s[0] & 0xe0
+	arg := s[0] & 0x1f
+	s = s[1:]
+	switch {
+	case arg < 24:
+		return major, uint64(arg), false, s, nil
+	case arg <= 27:
+		size := 1 << (arg - 24)
+		if len(s) < size {
+			return 0, 0, false, s, fmt.Errorf("unexpected end of data item head")
+		}
+		return major, readMsgpackUint(s[:size]), false, s[size:], nil
+	case arg == cborIndefArg:
+		switch major {
+		case cborBytes, cborText, cborArray, cborMap:
+			return major, 0, true, s, nil
+		case cborSimple:
+			return 0, 0, false, s, fmt.Errorf("unexpected break")
+		}
+	}
+	return 0, 0, false, s, fmt.Errorf("unexpected additional information %d for major type %d", arg, major>>5)
+}
+
+func (d *cborDecoder) parseValue(s string, depth int, bs CBORByteStrings) (*Value, string, error) {
+	depth++
+	if depth > MaxDepth {
+		return nil, s, fmt.Errorf("too big depth for the nested CBOR; it exceeds %d", MaxDepth)
+	}
+	major, n, indefinite, tail, err := readCBORHead(s)
+	if err != nil {
+		return nil, s, err
+	}
+	switch major {
+	case cborUint:
+		return d.newNumber(strconv.FormatUint(n, 10)), tail, nil
+	case cborNegInt:
+		if n == math.MaxUint64 {
+			return d.newNumber("-18446744073709551616"), tail, nil
+		}
+		if n <= math.MaxInt64 {
+			return d.newNumber(strconv.FormatInt(-1-int64(n), 10)), tail, nil
+		}
+		return d.newNumber("-" + strconv.FormatUint(n+1, 10)), tail, nil
+	case cborBytes:
+		b, tail, err := readCBORString(tail, cborBytes, n, indefinite)
+		if err != nil {
+			return nil, tail, err
+		}
+		return d.newBytes(b, bs, tail)
+	case cborText:
+		b, tail, err := readCBORString(tail, cborText, n, indefinite)
+		if err != nil {
+			return nil, tail, err
+		}
+		v := d.c.getValue()
+		v.t = TypeString
+		v.s = b
+		return v, tail, nil
+	case cborArray:
+		return d.parseArray(tail, n, indefinite, depth, bs)
+	case cborMap:
+		return d.parseMap(tail, n, indefinite, depth, bs)
+	case cborTag:
+		return d.parseTag(tail, n, depth, bs)
+	default:
+		return d.parseSimple(s[0]&0x1f, n, s, tail)
+	}
+}
+
+// readCBORString reads byte or text string with length n from s.
+//
+// Chunks of indefinite-length strings are concatenated.
+func readCBORString(s string, major byte, n uint64, indefinite bool) (string, string, error) {
+	if !indefinite {
+		if n > uint64(len(s)) {
+			return "", s, fmt.Errorf("unexpected end of string with length %d", n)
+		}
+		return s[:n], s[n:], nil
+	}
+	var b []byte
+	for {
+		if len(s) == 0 {
+			return "", s, fmt.Errorf("missing break for indefinite-length string")
+		}
+		if s[0] == cborBreak {
+			return string(b), s[1:], nil
+		}
+		chunkMajor, n, chunkIndefinite, tail, err := readCBORHead(s)
+		if err != nil {
+			return "", s, err
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return "", s, fmt.Errorf("unexpected chunk in indefinite-length string; it must be definite-length string of the same type")
+		}
+		if n > uint64(len(tail)) {
+			return "", tail, fmt.Errorf("unexpected end of string chunk with length %d", n)
+		}
+		b = append(b, tail[:n]...)
+		s = tail[n:]
+	}
+}
+
+func (d *cborDecoder) newBytes(b string, bs CBORByteStrings, tail string) (*Value, string, error) {
+	var s string
+	switch bs {
+	case CBORByteStringsBase64URL:
+		s = base64.RawURLEncoding.EncodeToString([]byte(b))
+	case CBORByteStringsHex:
+		s = hex.EncodeToString([]byte(b))
+	case CBORByteStringsReject:
+		return nil, tail, fmt.Errorf("byte strings aren't allowed")
+	default:
+		s = base64.StdEncoding.EncodeToString([]byte(b))
+	}
+	v := d.c.getValue()
+	v.t = TypeString
+	v.s = s
+	return v, tail, nil
+}
+
+func (d *cborDecoder) parseArray(s string, n uint64, indefinite bool, depth int, bs CBORByteStrings) (*Value, string, error) {
+	if !indefinite && n > uint64(len(s)) {
+		return nil, s, fmt.Errorf("too big array length %d; it exceeds the remaining %d bytes", n, len(s))
+	}
+	a := d.c.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	for i := 0; indefinite || uint64(i) < n; i++ {
+		if indefinite {
+			if len(s) == 0 {
+				return nil, s, fmt.Errorf("missing break for indefinite-length array")
+			}
+			if s[0] == cborBreak {
+				return a, s[1:], nil
+			}
+		}
+		v, tail, err := d.parseValue(s, depth, bs)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse array item #%d: %s", i, err)
+		}
+		a.a = append(a.a, v)
+		s = tail
+	}
+	return a, s, nil
+}
+
+func (d *cborDecoder) parseMap(s string, n uint64, indefinite bool, depth int, bs CBORByteStrings) (*Value, string, error) {
+	if !indefinite && n > uint64(len(s)/2) {
+		return nil, s, fmt.Errorf("too big map length %d; it exceeds the remaining %d bytes", n, len(s))
+	}
+	o := d.c.getValue()
+	o.t = TypeObject
+	o.o.reset()
+	o.o.keysUnescaped = true
+	for i := 0; indefinite || uint64(i) < n; i++ {
+		if indefinite {
+			if len(s) == 0 {
+				return nil, s, fmt.Errorf("missing break for indefinite-length map")
+			}
+			if s[0] == cborBreak {
+				return o, s[1:], nil
+			}
+		}
+		if len(s) == 0 {
+			return nil, s, fmt.Errorf("missing map key #%d", i)
+		}
+		if major := s[0] & 0xe0; major != cborText && major != cborUint && major != cborNegInt {
+			return nil, s, fmt.Errorf("unsupported map key #%d with major type %d; it must be text string or integer", i, major>>5)
+		}
+		k, tail, err := d.parseValue(s, depth, bs)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse map key #%d: %s", i, err)
+		}
+		s = tail
+		v, tail, err := d.parseValue(s, depth, bs)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse map value for key %q: %s", k.s, err)
+		}
+		s = tail
+		kv := o.o.getKV()
+		kv.k = k.s
+		kv.v = v
+	}
+	return o, s, nil
+}
+
+func (d *cborDecoder) parseTag(s string, tag uint64, depth int, bs CBORByteStrings) (*Value, string, error) {
+	switch tag {
+	case 2, 3:
+		major, n, indefinite, tail, err := readCBORHead(s)
+		if err != nil {
+			return nil, s, err
+		}
+		if major != cborBytes {
+			return nil, s, fmt.Errorf("unexpected content for bignum tag %d; it must be byte string", tag)
+		}
+		b, tail, err := readCBORString(tail, cborBytes, n, indefinite)
+		if err != nil {
+			return nil, tail, err
+		}
+		return d.newBignum(b, tag == 3, tail)
+	case 21:
+		bs = CBORByteStringsBase64URL
+	case 22:
+		bs = CBORByteStringsBase64
+	case 23:
+		bs = CBORByteStringsHex
+	}
+	return d.parseValue(s, depth, bs)
+}
+
+func (d *cborDecoder) newBignum(b string, neg bool, tail string) (*Value, string, error) {
+	var bi big.Int
+	bi.SetBytes([]byte(b))
+	if neg {
+		bi.Add(&bi, big.NewInt(1))
+		bi.Neg(&bi)
+	}
+	switch d.bigNumbers {
+	case CBORBigNumbersString:
+		v := d.c.getValue()
+		v.t = TypeString
+		v.s = bi.String()
+		return v, tail, nil
+	case CBORBigNumbersFloat:
+		f, _ := new(big.Float).SetInt(&bi).Float64()
+		if math.IsInf(f, 0) {
+			return nil, tail, fmt.Errorf("too big bignum for float64")
+		}
+		return d.newNumber(strconv.FormatFloat(f, 'g', -1, 64)), tail, nil
+	default:
+		return d.newNumber(bi.String()), tail, nil
+	}
+}
+
+// parseSimple parses major type 7 item starting at s with the additional
+// information arg and the argument n.
+func (d *cborDecoder) parseSimple(arg byte, n uint64, s, tail string) (*Value, string, error) {
+	var f float64
+	bitSize := 32
+	switch arg {
+	case 20:
+		return valueFalse, tail, nil
+	case 21:
+		return valueTrue, tail, nil
+	case 22, 23:
+		return valueNull, tail, nil
+	case 25:
+		f = float16ToFloat64(uint16(n))
+	case 26:
+		f = float64(math.Float32frombits(uint32(n)))
+	case 27:
+		f = math.Float64frombits(n)
+		bitSize = 64
+	default:
+		return nil, s, fmt.Errorf("unsupported simple value %d", arg)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, s, fmt.Errorf("unsupported non-finite float %v", f)
+	}
+	return d.newNumber(strconv.FormatFloat(f, 'g', -1, bitSize)), tail, nil
+}
+
+func (d *cborDecoder) newNumber(s string) *Value {
+	v := d.c.getValue()
+	v.t = TypeNumber
+	v.s = s
+	return v
+}
@@ -0,0 +1,243 @@
+package fastjson
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMarshalCBORTo(t *testing.T) {
+	f := func(s, hexExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		b, err := v.MarshalCBORTo(nil)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", s, err)
+		}
+		result := hex.EncodeToString(b)
+		if result != hexExpected {
+			t.Fatalf("unexpected CBOR for %s\ngot\n%s\nwant\n%s", s, result, hexExpected)
+		}
+	}
+
+	// Test vectors from RFC 8949, Appendix A.
+	f(`0`, `00`)
+	f(`23`, `17`)
+	f(`24`, `1818`)
+	f(`100`, `1864`)
+	f(`1000`, `1903e8`)
+	f(`1000000`, `1a000f4240`)
+	f(`1000000000000`, `1b000000e8d4a51000`)
+	f(`18446744073709551615`, `1bffffffffffffffff`)
+	f(`18446744073709551616`, `c249010000000000000000`)
+	f(`-18446744073709551616`, `3bffffffffffffffff`)
+	f(`-18446744073709551617`, `c349010000000000000000`)
+	f(`-1`, `20`)
+	f(`-100`, `3863`)
+	f(`-1000`, `3903e7`)
+	f(`0.0`, `f90000`)
+	f(`-0.0`, `f98000`)
+	f(`1.0`, `f93c00`)
+	f(`1.1`, `fb3ff199999999999a`)
+	f(`1.5`, `f93e00`)
+	f(`65504.0`, `f97bff`)
+	f(`100000.0`, `fa47c35000`)
+	f(`3.4028234663852886e+38`, `fa7f7fffff`)
+	f(`1.0e+300`, `fb7e37e43c8800759c`)
+	f(`5.960464477539063e-8`, `f90001`)
+	f(`0.00006103515625`, `f90400`)
+	f(`-4.0`, `f9c400`)
+	f(`-4.1`, `fbc010666666666666`)
+	f(`false`, `f4`)
+	f(`true`, `f5`)
+	f(`null`, `f6`)
+	f(`""`, `60`)
+	f(`"IETF"`, `6449455446`)
+	f(`"\"\\"`, `62225c`)
+	f(`"ü"`, `62c3bc`)
+	f(`[]`, `80`)
+	f(`[1,[2,3],[4,5]]`, `8301820203820405`)
+	f(`[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25]`, `98190102030405060708090a0b0c0d0e0f101112131415161718181819`)
+	f(`{}`, `a0`)
+	f(`{"a":1,"b":[2,3]}`, `a26161016162820203`)
+	f(`["a",{"b":"c"}]`, `826161a161626163`)
+
+	// Big numbers options
+	v := MustParse(`[18446744073709551616,-18446744073709551617]`)
+	co := &CBOROptions{
+		BigNumbers: CBORBigNumbersString,
+	}
+	b, err := co.MarshalTo(nil, v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result := hex.EncodeToString(b); result != `82743138343436373434303733373039353531363136752d3138343436373434303733373039353531363137` {
+		t.Fatalf("unexpected CBOR for big numbers as strings: %s", result)
+	}
+	co.BigNumbers = CBORBigNumbersFloat
+	b, err = co.MarshalTo(nil, v)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result := hex.EncodeToString(b); result != `82fa5f800000fadf800000` {
+		t.Fatalf("unexpected CBOR for big numbers as floats: %s", result)
+	}
+}
+
+func TestMarshalCBORToError(t *testing.T) {
+	f := func(s string, co *CBOROptions) {
+		t.Helper()
+		if _, err := co.MarshalTo(nil, MustParse(s)); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+	}
+
+	// Non-finite numbers
+	f(`1e400`, &CBOROptions{})
+	f(`-1e400`, &CBOROptions{})
+	f(`NaN`, &CBOROptions{})
+	f(`[1,{"a":1.5e309}]`, &CBOROptions{})
+	f(`1`+strings.Repeat("0", 400), &CBOROptions{BigNumbers: CBORBigNumbersFloat})
+
+	// Big integers are encoded as bignums by default.
+	if _, err := MustParse(`1` + strings.Repeat("0", 400)).MarshalCBORTo(nil); err != nil {
+		t.Fatalf("unexpected error for big integer: %s", err)
+	}
+}
+
+func TestParseCBOR(t *testing.T) {
+	f := func(hexStr string, co *CBOROptions, resultExpected string) {
+		t.Helper()
+		b, err := hex.DecodeString(hexStr)
+		if err != nil {
+			t.Fatalf("cannot decode hex: %s", err)
+		}
+		var p Parser
+		v, err := p.ParseCBOR(b, co)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", hexStr, err)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s\ngot\n%s\nwant\n%s", hexStr, result, resultExpected)
+		}
+	}
+
+	// Test vectors from RFC 8949, Appendix A.
+	f(`00`, nil, `0`)
+	f(`1bffffffffffffffff`, nil, `18446744073709551615`)
+	f(`c249010000000000000000`, nil, `18446744073709551616`)
+	f(`3bffffffffffffffff`, nil, `-18446744073709551616`)
+	f(`3b7fffffffffffffff`, nil, `-9223372036854775808`)
+	f(`3b8000000000000000`, nil, `-9223372036854775809`)
+	f(`c349010000000000000000`, nil, `-18446744073709551617`)
+	f(`29`, nil, `-10`)
+	f(`f90000`, nil, `0`)
+	f(`f93e00`, nil, `1.5`)
+	f(`f97bff`, nil, `65504`)
+	f(`f90001`, nil, `5.9604645e-08`)
+	f(`fa47c35000`, nil, `100000`)
+	f(`fb3ff199999999999a`, nil, `1.1`)
+	f(`f4`, nil, `false`)
+	f(`f5`, nil, `true`)
+	f(`f6`, nil, `null`)
+	f(`f7`, nil, `null`)
+	f(`c074323031332d30332d32315432303a30343a30305a`, nil, `"2013-03-21T20:04:00Z"`)
+	f(`c11a514b67b0`, nil, `1363896240`)
+	f(`d74401020304`, nil, `"01020304"`)
+	f(`d818456449455446`, nil, `"ZElFVEY="`)
+	f(`d82076687474703a2f2f7777772e6578616d706c652e636f6d`, nil, `"http://www.example.com"`)
+	f(`62225c`, nil, `"\"\\"`)
+	f(`a201020304`, nil, `{"1":2,"3":4}`)
+	f(`a26161016162820203`, nil, `{"a":1,"b":[2,3]}`)
+	f(`5f42010243030405ff`, nil, `"AQIDBAU="`)
+	f(`7f657374726561646d696e67ff`, nil, `"streaming"`)
+	f(`9fff`, nil, `[]`)
+	f(`9f018202039f0405ffff`, nil, `[1,[2,3],[4,5]]`)
+	f(`83018202039f0405ff`, nil, `[1,[2,3],[4,5]]`)
+	f(`bf61610161629f0203ffff`, nil, `{"a":1,"b":[2,3]}`)
+	f(`bf6346756ef563416d7421ff`, nil, `{"Fun":true,"Amt":-2}`)
+
+	// Byte strings options
+	f(`4403fbff00`, &CBOROptions{ByteStrings: CBORByteStringsBase64URL}, `"A_v_AA"`)
+	f(`4403fbff00`, &CBOROptions{ByteStrings: CBORByteStringsHex}, `"03fbff00"`)
+	f(`d5824403fbff00d64101`, &CBOROptions{ByteStrings: CBORByteStringsReject}, `["A_v_AA","AQ=="]`)
+
+	// Big numbers options
+	f(`c249010000000000000000`, &CBOROptions{BigNumbers: CBORBigNumbersString}, `"18446744073709551616"`)
+	f(`c349010000000000000000`, &CBOROptions{BigNumbers: CBORBigNumbersFloat}, `-1.8446744073709552e+19`)
+
+	// Round trip
+	s := `{"id":12345678901,"name":"foo \"bar\"","big":-123456789012345678901234567890,"tags":["a","b"],"n":null,"f":-1.25e-10,"o":{"x":[{},[]]}}`
+	b, err := MustParse(s).MarshalCBORTo(nil)
+	if err != nil {
+		t.Fatalf("cannot marshal CBOR: %s", err)
+	}
+	v, err := ParseCBOR(b)
+	if err != nil {
+		t.Fatalf("cannot parse CBOR: %s", err)
+	}
+	if result := v.String(); result != s {
+		t.Fatalf("unexpected round trip result\ngot\n%s\nwant\n%s", result, s)
+	}
+}
+
+func TestParseCBORError(t *testing.T) {
+	f := func(hexStr string, co *CBOROptions) {
+		t.Helper()
+		b, err := hex.DecodeString(hexStr)
+		if err != nil {
+			t.Fatalf("cannot decode hex: %s", err)
+		}
+		var p Parser
+		v, err := p.ParseCBOR(b, co)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %s; got %s", hexStr, v)
+		}
+	}
+
+	f(``, nil)
+	f(`1c`, nil)
+	f(`f6f6`, nil)
+	f(`19ff`, nil)
+	f(`62ff`, nil)
+	f(`ff`, nil)
+	f(`1f`, nil)
+	f(`5f41`, nil)
+	f(`5f6161ff`, nil)
+	f(`5f5f4100ffff`, nil)
+	f(`9f01`, nil)
+	f(`bf6161`, nil)
+	f(`9affffffff`, nil)
+	f(`bb00000000ffffffff`, nil)
+	f(`82f6`, nil)
+	f(`a1f6f6`, nil)
+	f(`a1f93c0001`, nil)
+	f(`a14101f6`, nil)
+	f(`f97c00`, nil)
+	f(`f97e00`, nil)
+	f(`fa7f800000`, nil)
+	f(`f0`, nil)
+	f(`f820`, nil)
+	f(`c201`, nil)
+	f(`4101`, &CBOROptions{ByteStrings: CBORByteStringsReject})
+	f(`c25881`+strings.Repeat(`ff`, 129), &CBOROptions{BigNumbers: CBORBigNumbersFloat})
+	f(strings.Repeat(`81`, MaxDepth+1)+`f6`, nil)
+	f(strings.Repeat(`c6`, MaxDepth+1)+`f6`, nil)
+}
+
+func TestFloat16(t *testing.T) {
+	for h := 0; h < 1<<16; h++ {
+		if h&0x7c00 == 0x7c00 {
+			continue
+		}
+		f := float16ToFloat64(uint16(h))
+		hh, ok := float64ToFloat16(f)
+		if !ok {
+			t.Fatalf("cannot convert %v back to float16 for 0x%04x", f, h)
+		}
+		if int(hh) != h {
+			t.Fatalf("unexpected float16 for %v; got 0x%04x; want 0x%04x", f, hh, h)
+		}
+	}
+}
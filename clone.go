@@ -0,0 +1,129 @@
+package fastjson
+
+// CloneForGoroutine returns a deep copy of v, which may be used
+// independently of v from another goroutine.
+//
+// All the lazily initialized state such as unescaped strings and object
+// keys is resolved in the returned copy, so read-only access to it
+// (Get*, Visit, MarshalTo, etc.) never mutates it. This makes the copy
+// safe for concurrent reading from multiple goroutines.
+//
+// CloneForGoroutine doesn't modify v, so it may be called concurrently
+// on the same v from multiple goroutines as long as v isn't modified.
+//
+// The returned copy doesn't refer to the memory of the Parser returned v,
+// so it remains valid after the next Parse call.
+func (v *Value) CloneForGoroutine() *Value {
+	if v == nil {
+		return nil
+	}
+	var a Arena
+	values, bytes := v.cloneSize()
+	a.c.vs = make([]Value, 0, values)
+	a.b = make([]byte, 0, bytes)
+	return a.deepCopy(v)
+}
+
+// CopyTo returns a deep copy of v allocated in a.
+//
+// The returned copy doesn't refer to the memory of the Parser or Arena
+// owning v, so it may be inserted into a document owned by a after
+// the v owner is reset or reused. The copy remains valid until a.Reset call.
+//
+// CopyTo doesn't modify v.
+func (v *Value) CopyTo(a *Arena) *Value {
+	if v == nil {
+		return nil
+	}
+	return a.deepCopy(v)
+}
+
+// cloneSize returns the number of Values and the number of bytes
+// required for a deep copy of v.
+func (v *Value) cloneSize() (int, int) {
+	switch v.t {
+	case TypeObject:
+		values, bytes := 1, 0
+		for _, kv := range v.o.kvs {
+			n, m := kv.v.cloneSize()
+			values += n
+			bytes += m + len(kv.k)
+		}
+		return values, bytes
+	case TypeArray:
+		values, bytes := 1, 0
+		for _, vv := range v.a {
+			n, m := vv.cloneSize()
+			values += n
+			bytes += m
+		}
+		return values, bytes
+	case TypeString, typeRawString, TypeNumber:
+		return 1, len(v.s)
+	default:
+		return 0, 0
+	}
+}
+
+// deepCopy returns a deep copy of v allocated in a.
+//
+// Strings and object keys are unescaped in the copy. v isn't modified.
+func (a *Arena) deepCopy(v *Value) *Value {
+	switch v.t {
+	case TypeObject:
+		vv := a.NewObject()
+		for _, kv := range v.o.kvs {
+			k, loneSurrogates := a.copyString(kv.k, !v.o.keysUnescaped)
+			if loneSurrogates {
+				vv.o.keysLoneSurrogates = true
+			}
+			vc := a.deepCopy(kv.v)
+			kvc := vv.o.getKV()
+			kvc.k = k
+			kvc.v = vc
+		}
+		vv.o.keysUnescaped = true
+		if v.o.keysLoneSurrogates {
+			vv.o.keysLoneSurrogates = true
+		}
+		return vv
+	case TypeArray:
+		vv := a.NewArray()
+		for _, item := range v.a {
+			vv.a = append(vv.a, a.deepCopy(item))
+		}
+		return vv
+	case TypeString, typeRawString:
+		vv := a.c.getValue()
+		vv.t = TypeString
+		s, loneSurrogates := a.copyString(v.s, v.t == typeRawString)
+		vv.s = s
+		vv.loneSurrogates = loneSurrogates || v.loneSurrogates
+		return vv
+	case TypeNumber:
+		vv := a.c.getValue()
+		vv.t = TypeNumber
+		vv.s, _ = a.copyString(v.s, false)
+		return vv
+	default:
+		// true, false and null are immutable singletons.
+		return v
+	}
+}
+
+// copyString copies s to a and unescapes the copy if needed.
+//
+// It also returns true if the unescaped s contained escape sequences
+// for lone UTF-16 surrogates.
+func (a *Arena) copyString(s string, unescape bool) (string, bool) {
+	bLen := len(a.b)
+	a.b = append(a.b, s...)
+	cs := b2s(a.b[bLen:])
+	loneSurrogates := false
+	if unescape {
+		// Unescaping is performed in place, so it modifies only the copy.
+		cs, loneSurrogates = unescapeStringSurrogates(cs)
+		a.b = a.b[:bLen+len(cs)]
+	}
+	return cs, loneSurrogates
+}
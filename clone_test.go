@@ -0,0 +1,111 @@
+package fastjson
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValueCloneForGoroutine(t *testing.T) {
+	var p Parser
+	s := `{"fo\no":"bar","arr":[1,"x\ty",{"z":null}],"t":true,"n":-1.5e3}`
+	v, err := p.Parse(s)
+	if err != nil {
+		t.Fatalf("cannot parse %s: %s", s, err)
+	}
+	c := v.CloneForGoroutine()
+
+	// The source value mustn't be modified by cloning.
+	if v.GetObject().kvs[0].v.t != typeRawString {
+		t.Fatalf("the source value has been modified during cloning")
+	}
+
+	// The clone must survive the next Parse call on the original parser.
+	if _, err := p.Parse(`{"foo":"overwritten","arr":[]}`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	str := c.String()
+	strExpected := `{"fo\no":"bar","arr":[1,"x\ty",{"z":null}],"t":true,"n":-1.5e3}`
+	if str != strExpected {
+		t.Fatalf("unexpected clone\ngot\n%s\nwant\n%s", str, strExpected)
+	}
+	if sb := c.GetStringBytes("fo\no"); string(sb) != "bar" {
+		t.Fatalf("unexpected string; got %q; want %q", sb, "bar")
+	}
+	if c.CloneForGoroutine().String() != strExpected {
+		t.Fatalf("unexpected clone of the clone")
+	}
+
+	var vNil *Value
+	if vNil.CloneForGoroutine() != nil {
+		t.Fatalf("expecting nil clone for nil value")
+	}
+}
+
+func TestValueCopyTo(t *testing.T) {
+	var p1, p2 Parser
+	src, err := p1.Parse(`{"na\tme":"x\ny","items":[1,{"a":true}]}`)
+	if err != nil {
+		t.Fatalf("cannot parse source: %s", err)
+	}
+	dst, err := p2.Parse(`{"dst":1}`)
+	if err != nil {
+		t.Fatalf("cannot parse destination: %s", err)
+	}
+
+	var a Arena
+	dst.Set("copy", src.CopyTo(&a))
+
+	// The copy must survive the next Parse call on the source parser.
+	if _, err := p1.Parse(`{"overwritten":[1,2,3,4,5,6,7,8,9]}`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	str := dst.String()
+	strExpected := `{"dst":1,"copy":{"na\tme":"x\ny","items":[1,{"a":true}]}}`
+	if str != strExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", str, strExpected)
+	}
+	if sb := dst.GetStringBytes("copy", "na\tme"); string(sb) != "x\ny" {
+		t.Fatalf("unexpected string; got %q; want %q", sb, "x\ny")
+	}
+
+	var vNil *Value
+	if vNil.CopyTo(&a) != nil {
+		t.Fatalf("expecting nil copy for nil value")
+	}
+}
+
+func TestValueCloneForGoroutineConcurrent(t *testing.T) {
+	v := MustParse(`{"a\\nb":["x\"y",{"c":[1,2,3]}],"d":"A"}`)
+	const concurrency = 8
+	ch := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			c := v.CloneForGoroutine()
+			var err error
+			for j := 0; j < 100; j++ {
+				if s := c.GetStringBytes("d"); string(s) != "A" {
+					err = fmt.Errorf("unexpected string; got %q; want %q", s, "A")
+					break
+				}
+				c.GetObject().Visit(func(key []byte, v *Value) {})
+				if s := c.String(); s != `{"a\\nb":["x\"y",{"c":[1,2,3]}],"d":"A"}` {
+					err = fmt.Errorf("unexpected clone: %s", s)
+					break
+				}
+			}
+			ch <- err
+		}()
+	}
+	for i := 0; i < concurrency; i++ {
+		select {
+		case err := <-ch:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout")
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package fastjson
+
+import (
+	"fmt"
+)
+
+// Compact appends src with insignificant whitespace removed to dst
+// and returns the result.
+//
+// src is validated in the same pass, so an error is returned for invalid
+// JSON and for JSON nested deeper than MaxDepth. Compact doesn't allocate
+// Values, so it is faster than Parse followed by MarshalTo.
+func Compact(dst, src []byte) ([]byte, error) {
+	var f formatter
+	return f.format(dst, src)
+}
+
+// Indent appends pretty printed src to dst and returns the result.
+//
+// Every nested object member and array item is emitted on a new line
+// starting with prefix followed by indent copies according to the nesting
+// level in the same way as json.Indent does. Unlike json.Indent,
+// the output is compact if both prefix and indent are empty.
+//
+// src is validated in the same pass, so an error is returned for invalid
+// JSON and for JSON nested deeper than MaxDepth. Indent doesn't allocate
+// Values, so it is faster than Parse followed by MarshalIndentTo.
+func Indent(dst, src []byte, prefix, indent string) ([]byte, error) {
+	f := formatter{
+		prefix: prefix,
+		indent: indent,
+	}
+	return f.format(dst, src)
+}
+
+// formatter reformats raw JSON without building Values.
+//
+// The output is compact if both prefix and indent are empty.
+type formatter struct {
+	prefix string
+	indent string
+}
+
+func (f *formatter) format(dst, src []byte) ([]byte, error) {
+	dstLen := len(dst)
+	s := skipWS(b2s(src))
+	dst, tail, err := f.formatValue(dst, s, 0)
+	if err != nil {
+		return dst[:dstLen], fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(tail))
+	}
+	tail = skipWS(tail)
+	if len(tail) > 0 {
+		return dst[:dstLen], fmt.Errorf("unexpected tail: %q", startEndString(tail))
+	}
+	return dst, nil
+}
+
+func (f *formatter) formatValue(dst []byte, s string, depth int) ([]byte, string, error) {
+	if len(s) > 0 && (s[0] == '{' || s[0] == '[') && depth >= MaxDepth {
+		return dst, s, fmt.Errorf("too big depth for the nested JSON; it exceeds %d", MaxDepth)
+	}
+	if len(s) > 0 && s[0] == '{' {
+		dst, tail, err := f.formatObject(append(dst, '{'), s[1:], depth)
+		if err != nil {
+			return dst, tail, fmt.Errorf("cannot parse object: %s", err)
+		}
+		return dst, tail, nil
+	}
+	if len(s) > 0 && s[0] == '[' {
+		dst, tail, err := f.formatArray(append(dst, '['), s[1:], depth)
+		if err != nil {
+			return dst, tail, fmt.Errorf("cannot parse array: %s", err)
+		}
+		return dst, tail, nil
+	}
+	// Scalar values contain no insignificant whitespace.
+	tail, err := validateValue(s)
+	if err != nil {
+		return dst, tail, err
+	}
+	dst = append(dst, s[:len(s)-len(tail)]...)
+	return dst, tail, nil
+}
+
+func (f *formatter) formatArray(dst []byte, s string, depth int) ([]byte, string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return dst, s, fmt.Errorf("missing ']'")
+	}
+	if s[0] == ']' {
+		return append(dst, ']'), s[1:], nil
+	}
+
+	for {
+		var err error
+
+		s = skipWS(s)
+		dst = f.appendNewline(dst, depth+1)
+		dst, s, err = f.formatValue(dst, s, depth+1)
+		if err != nil {
+			return dst, s, fmt.Errorf("cannot parse array value: %s", err)
+		}
+
+		s = skipWS(s)
+		if len(s) == 0 {
+			return dst, s, fmt.Errorf("unexpected end of array")
+		}
+		if s[0] == ',' {
+			dst = append(dst, ',')
+			s = s[1:]
+			continue
+		}
+		if s[0] == ']' {
+			dst = f.appendNewline(dst, depth)
+			return append(dst, ']'), s[1:], nil
+		}
+		return dst, s, fmt.Errorf("missing ',' after array value")
+	}
+}
+
+func (f *formatter) formatObject(dst []byte, s string, depth int) ([]byte, string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return dst, s, fmt.Errorf("missing '}'")
+	}
+	if s[0] == '}' {
+		return append(dst, '}'), s[1:], nil
+	}
+
+	for {
+		var err error
+
+		// Parse key.
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != '"' {
+			return dst, s, fmt.Errorf(`cannot find opening '"" for object key`)
+		}
+		dst = f.appendNewline(dst, depth+1)
+		dst, s, err = f.formatValue(dst, s, depth+1)
+		if err != nil {
+			return dst, s, fmt.Errorf("cannot parse object key: %s", err)
+		}
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != ':' {
+			return dst, s, fmt.Errorf("missing ':' after object key")
+		}
+		dst = append(dst, ':')
+		if f.isIndented() {
+			dst = append(dst, ' ')
+		}
+		s = s[1:]
+
+		// Parse value
+		s = skipWS(s)
+		dst, s, err = f.formatValue(dst, s, depth+1)
+		if err != nil {
+			return dst, s, fmt.Errorf("cannot parse object value: %s", err)
+		}
+		s = skipWS(s)
+		if len(s) == 0 {
+			return dst, s, fmt.Errorf("unexpected end of object")
+		}
+		if s[0] == ',' {
+			dst = append(dst, ',')
+			s = s[1:]
+			continue
+		}
+		if s[0] == '}' {
+			dst = f.appendNewline(dst, depth)
+			return append(dst, '}'), s[1:], nil
+		}
+		return dst, s, fmt.Errorf("missing ',' after object value")
+	}
+}
+
+func (f *formatter) isIndented() bool {
+	return len(f.prefix) > 0 || len(f.indent) > 0
+}
+
+// appendNewline appends newline followed by the prefix and indentation
+// for the given depth to dst if pretty printing is enabled.
+func (f *formatter) appendNewline(dst []byte, depth int) []byte {
+	if !f.isIndented() {
+		return dst
+	}
+	dst = append(dst, '\n')
+	dst = append(dst, f.prefix...)
+	for i := 0; i < depth; i++ {
+		dst = append(dst, f.indent...)
+	}
+	return dst
+}
@@ -0,0 +1,147 @@
+package fastjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCompactSuccess(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		result, err := Compact([]byte("prefix"), []byte(s))
+		if err != nil {
+			t.Fatalf("unexpected error when compacting %q: %s", s, err)
+		}
+		if string(result) != "prefix"+resultExpected {
+			t.Fatalf("unexpected result for %q\ngot\n%s\nwant\n%s", s, result, "prefix"+resultExpected)
+		}
+
+		// Verify the result with encoding/json.
+		var bb bytes.Buffer
+		if err := json.Compact(&bb, []byte(s)); err != nil {
+			t.Fatalf("encoding/json cannot compact %q: %s", s, err)
+		}
+		if bb.String() != resultExpected {
+			t.Fatalf("unexpected encoding/json result for %q\ngot\n%s\nwant\n%s", s, bb.String(), resultExpected)
+		}
+	}
+
+	f(`1`, `1`)
+	f(" \t\n -1.5e3 \r\n", `-1.5e3`)
+	f(` "a b\" \n" `, `"a b\" \n"`)
+	f(` { } `, `{}`)
+	f(` [ ] `, `[]`)
+	f(`{ "a" : [ 1 , true , null , { "b c" : "d e" } ] , "f" : false }`, `{"a":[1,true,null,{"b c":"d e"}],"f":false}`)
+	f("[\n  [\n    [ ]\n  ]\n]", `[[[]]]`)
+	f(`{"a\"b" : "ሴ x"}`, `{"a\"b":"ሴ x"}`)
+	nested := strings.Repeat(`{"a":[`, MaxDepth/2) + "1" + strings.Repeat("]}", MaxDepth/2)
+	f(nested, nested)
+}
+
+func TestCompactFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		dst := []byte("prefix")
+		result, err := Compact(dst, []byte(s))
+		if err == nil {
+			t.Fatalf("expecting non-nil error when compacting %q; got %q", s, result)
+		}
+		if string(result) != "prefix" {
+			t.Fatalf("dst must be left unchanged on error; got %q", result)
+		}
+	}
+
+	f(``)
+	f(`   `)
+	f(`{`)
+	f(`[1,]`)
+	f(`[1 2]`)
+	f(`{"a" 1}`)
+	f(`{"a":1,}`)
+	f(`{a:1}`)
+	f(`"foo`)
+	f(`"a` + "\n" + `b"`)
+	f(`tru`)
+	f(`01`)
+	f(`[1] x`)
+	f(`{"a":[1,2}`)
+	f("[" + strings.Repeat(`{"a":[`, MaxDepth/2) + "1" + strings.Repeat("]}", MaxDepth/2) + "]")
+	f(strings.Repeat("[", 1e6))
+}
+
+func TestIndentSuccess(t *testing.T) {
+	f := func(s, prefix, indent string) {
+		t.Helper()
+		result, err := Indent([]byte("prefix"), []byte(s), prefix, indent)
+		if err != nil {
+			t.Fatalf("unexpected error when indenting %q: %s", s, err)
+		}
+
+		var bb bytes.Buffer
+		if err := json.Indent(&bb, []byte(s), prefix, indent); err != nil {
+			t.Fatalf("encoding/json cannot indent %q: %s", s, err)
+		}
+		resultExpected := "prefix" + bb.String()
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result for Indent(%q, %q) on %q\ngot\n%s\nwant\n%s", prefix, indent, s, result, resultExpected)
+		}
+	}
+
+	for _, s := range []string{
+		`1`,
+		`"a b"`,
+		`{ }`,
+		`[ ]`,
+		`{"a":1}`,
+		`[1, "x" ,null,true,false]`,
+		`{ "a" : {"b":[1,{"c":[ ]},{ }],"d":"e\nf"},"g":[[[]]],"h\t":null}`,
+	} {
+		f(s, "", "  ")
+		f(s, "", "\t")
+		f(s, "> ", "  ")
+		f(s, "#", "")
+	}
+
+	// Indent must be equivalent to MarshalIndentTo.
+	s := `{"a":{"b":[1,{"c":[]},{}],"d":"e\nf"},"g":[[[]]]}`
+	result, err := Indent(nil, []byte(s), "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resultExpected := MustParse(s).MarshalIndentTo(nil, "", "  ")
+	if string(result) != string(resultExpected) {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+}
+
+func TestIndentFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		result, err := Indent([]byte("prefix"), []byte(s), "", "  ")
+		if err == nil {
+			t.Fatalf("expecting non-nil error when indenting %q; got %q", s, result)
+		}
+		if string(result) != "prefix" {
+			t.Fatalf("dst must be left unchanged on error; got %q", result)
+		}
+	}
+
+	f(``)
+	f(`{"a":[1,]}`)
+	f(`{"a" 1}`)
+	f(`[1] x`)
+	f(strings.Repeat("[", MaxDepth+1) + strings.Repeat("]", MaxDepth+1))
+}
+
+func TestIndentEmpty(t *testing.T) {
+	s := `{ "a" : [ 1 ] }`
+	result, err := Indent(nil, []byte(s), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(result) != `{"a":[1]}` {
+		t.Fatalf("unexpected result; got %s; want %s", result, `{"a":[1]}`)
+	}
+}
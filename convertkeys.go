@@ -0,0 +1,80 @@
+package fastjson
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ConvertKeys replaces all the object keys in v with the keys returned by conv.
+//
+// Objects are traversed recursively at any depth, including objects inside
+// arrays. See SnakeToCamel and CamelToSnake for ready-to-use converters.
+func (v *Value) ConvertKeys(conv func(key string) string) {
+	if v == nil {
+		return
+	}
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		v.o.idx = nil
+		for i := range v.o.kvs {
+			kv := &v.o.kvs[i]
+			kv.k = conv(kv.k)
+			kv.v.ConvertKeys(conv)
+		}
+	case TypeArray:
+		for _, item := range v.a {
+			item.ConvertKeys(conv)
+		}
+	}
+}
+
+// SnakeToCamel converts snake_case key to camelCase, for example
+// "user_id" to "userId".
+//
+// Leading and trailing underscores are preserved.
+func SnakeToCamel(key string) string {
+	b := make([]byte, 0, len(key))
+	upper := false
+	for i, r := range key {
+		if r == '_' && len(b) > 0 && i+1 < len(key) && key[i+1] != '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b = appendRune(b, r)
+	}
+	return string(b)
+}
+
+// CamelToSnake converts camelCase key to snake_case, for example
+// "userId" to "user_id" and "HTTPServer" to "http_server".
+func CamelToSnake(key string) string {
+	b := make([]byte, 0, len(key)+4)
+	prev := rune(-1)
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if prev >= 0 && prev != '_' {
+				next, _ := utf8.DecodeRuneInString(key[i+utf8.RuneLen(r):])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && unicode.IsLower(next)) {
+					b = append(b, '_')
+				}
+			}
+			prev = r
+			r = unicode.ToLower(r)
+		} else {
+			prev = r
+		}
+		b = appendRune(b, r)
+	}
+	return string(b)
+}
+
+func appendRune(dst []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(dst, buf[:n]...)
+}
@@ -0,0 +1,73 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValueConvertKeys(t *testing.T) {
+	v := MustParse(`{"user_id":1,"user_info":{"first_name":"x","tags":[{"tag_name":"y"}]},"a\nb":2}`)
+	v.GetObject().BuildIndex()
+	v.ConvertKeys(SnakeToCamel)
+	result := v.String()
+	resultExpected := `{"userId":1,"userInfo":{"firstName":"x","tags":[{"tagName":"y"}]},"a\nb":2}`
+	if result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+	if n := v.GetInt("userId"); n != 1 {
+		t.Fatalf("unexpected value for the converted key; got %d; want 1", n)
+	}
+
+	v.ConvertKeys(strings.ToUpper)
+	result = v.String()
+	resultExpected = `{"USERID":1,"USERINFO":{"FIRSTNAME":"x","TAGS":[{"TAGNAME":"y"}]},"A\nB":2}`
+	if result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	var vNil *Value
+	vNil.ConvertKeys(strings.ToUpper)
+}
+
+func TestSnakeToCamel(t *testing.T) {
+	f := func(key, resultExpected string) {
+		t.Helper()
+		result := SnakeToCamel(key)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for SnakeToCamel(%q); got %q; want %q", key, result, resultExpected)
+		}
+	}
+
+	f("", "")
+	f("user", "user")
+	f("user_id", "userId")
+	f("user_first_name", "userFirstName")
+	f("_id", "_id")
+	f("id_", "id_")
+	f("a__b", "a_B")
+	f("http_2_server", "http2Server")
+	f("привет_мир", "приветМир")
+	f("userId", "userId")
+}
+
+func TestCamelToSnake(t *testing.T) {
+	f := func(key, resultExpected string) {
+		t.Helper()
+		result := CamelToSnake(key)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for CamelToSnake(%q); got %q; want %q", key, result, resultExpected)
+		}
+	}
+
+	f("", "")
+	f("user", "user")
+	f("userId", "user_id")
+	f("UserId", "user_id")
+	f("userFirstName", "user_first_name")
+	f("HTTPServer", "http_server")
+	f("userID", "user_id")
+	f("http2Server", "http2_server")
+	f("user_id", "user_id")
+	f("user_Id", "user_id")
+	f("приветМир", "привет_мир")
+}
@@ -0,0 +1,98 @@
+package fastjson
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// InitCompressedReader initializes sc for reading JSON values from r,
+// which may contain compressed data.
+//
+// The compression format is detected by the magic bytes at the start of r.
+// Gzip is supported out of the box, while other formats such as zstd
+// may be registered via RegisterDecompressor. Uncompressed data is read as is.
+// The data is decompressed on the fly, so InitCompressedReader may be used
+// for scanning huge compressed streams.
+//
+// See InitReader for details.
+func (sc *Scanner) InitCompressedReader(r io.Reader) error {
+	br := bufio.NewReader(r)
+	zr, err := newDecompressingReader(br)
+	if err != nil {
+		sc.Init("")
+		return err
+	}
+	sc.InitReader(zr)
+	return nil
+}
+
+// RegisterDecompressor registers newReader for decompressing data starting
+// with the given magic bytes in Scanner.InitCompressedReader.
+//
+// For example, zstd decompressor may be registered with the following code:
+//
+//	fastjson.RegisterDecompressor([]byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.Reader, error) {
+//		return zstd.NewReader(r)
+//	})
+//
+// RegisterDecompressor is usually called during program initialization.
+// Decompressors registered later take precedence over the previously
+// registered ones with the same magic bytes.
+func RegisterDecompressor(magic []byte, newReader func(r io.Reader) (io.Reader, error)) {
+	if len(magic) == 0 {
+		panic(fmt.Errorf("BUG: magic bytes cannot be empty"))
+	}
+	decompressorsLock.Lock()
+	decompressors = append([]decompressor{{
+		magic:     append([]byte{}, magic...),
+		newReader: newReader,
+	}}, decompressors...)
+	decompressorsLock.Unlock()
+}
+
+type decompressor struct {
+	magic     []byte
+	newReader func(r io.Reader) (io.Reader, error)
+}
+
+var (
+	decompressorsLock sync.RWMutex
+	decompressors     = []decompressor{
+		{
+			magic: []byte{0x1f, 0x8b},
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+	}
+)
+
+func newDecompressingReader(br *bufio.Reader) (io.Reader, error) {
+	decompressorsLock.RLock()
+	ds := decompressors
+	decompressorsLock.RUnlock()
+
+	maxMagicLen := 0
+	for _, d := range ds {
+		if len(d.magic) > maxMagicLen {
+			maxMagicLen = len(d.magic)
+		}
+	}
+	// Peek returns the available bytes together with an error for short data.
+	prefix, _ := br.Peek(maxMagicLen)
+	for _, d := range ds {
+		if !bytes.HasPrefix(prefix, d.magic) {
+			continue
+		}
+		zr, err := d.newReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize decompressor for data with magic bytes %x: %s", d.magic, err)
+		}
+		return zr, nil
+	}
+	return br, nil
+}
@@ -0,0 +1,92 @@
+package fastjson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestScannerInitCompressedReader(t *testing.T) {
+	f := func(data []byte, resultExpected string) {
+		t.Helper()
+		var sc Scanner
+		if err := sc.InitCompressedReader(bytes.NewReader(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var bb bytes.Buffer
+		for sc.Next() {
+			fmt.Fprintf(&bb, "%s;", sc.Value())
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result := bb.String(); result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	s := "{\"a\":1}\n[2]\n\"foo\"\n"
+	resultExpected := `{"a":1};[2];"foo";`
+
+	// Uncompressed data
+	f([]byte(s), resultExpected)
+	f([]byte("1"), "1;")
+	f(nil, "")
+
+	// Gzipped data
+	var bb bytes.Buffer
+	zw := gzip.NewWriter(&bb)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatalf("cannot compress data: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("cannot compress data: %s", err)
+	}
+	f(bb.Bytes(), resultExpected)
+
+	// Custom decompressor
+	RegisterDecompressor([]byte("UPPER:"), func(r io.Reader) (io.Reader, error) {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(strings.ToLower(string(data[len("UPPER:"):]))), nil
+	})
+	f([]byte(`UPPER:{"A":TRUE} ["B"]`), `{"a":true};["b"];`)
+}
+
+func TestScannerInitCompressedReaderError(t *testing.T) {
+	var sc Scanner
+
+	// Invalid gzip header
+	if err := sc.InitCompressedReader(bytes.NewReader([]byte{0x1f, 0x8b, 0x00})); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if sc.Next() {
+		t.Fatalf("Next must return false after failed initialization")
+	}
+
+	// Corrupted gzip body
+	var bb bytes.Buffer
+	zw := gzip.NewWriter(&bb)
+	if _, err := zw.Write([]byte(`{"a":1} {"b":2}`)); err != nil {
+		t.Fatalf("cannot compress data: %s", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("cannot compress data: %s", err)
+	}
+	data := bb.Bytes()
+	data = data[:len(data)-4]
+	if err := sc.InitCompressedReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for sc.Next() {
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error for corrupted data")
+	}
+}
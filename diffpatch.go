@@ -0,0 +1,109 @@
+package fastjson
+
+import (
+	"strconv"
+)
+
+// DiffOptions contains options for DiffPatch.
+//
+// The zero value is equivalent to the default options used by DiffPatch.
+type DiffOptions struct {
+	// EmitTests enables emitting `test` operations before every `remove`
+	// and `replace` operation. The emitted tests verify the original values,
+	// so the patch fails to apply if the target has been concurrently modified.
+	EmitTests bool
+}
+
+// DiffPatch returns JSON Patch from RFC 6902, which transforms a into b.
+//
+// The returned patch may be applied with ApplyPatch.
+// The returned patch refers to values from a and b, so they must be
+// unchanged during the patch lifetime.
+func DiffPatch(a, b *Value) *Value {
+	var opts DiffOptions
+	return opts.DiffPatch(a, b)
+}
+
+// DiffPatch returns JSON Patch from RFC 6902, which transforms a into b
+// according to opts.
+//
+// See DiffPatch for details.
+func (opts *DiffOptions) DiffPatch(a, b *Value) *Value {
+	d := &differ{
+		opts: opts,
+	}
+	d.ops = d.a.NewArray()
+	d.diff(nil, a, b)
+	return d.ops
+}
+
+type differ struct {
+	opts *DiffOptions
+	a    Arena
+	ops  *Value
+}
+
+func (d *differ) diff(keys []string, a, b *Value) {
+	if equalExact(a, b) {
+		return
+	}
+	if a == nil {
+		d.addOp("add", keys, b)
+		return
+	}
+	if b == nil {
+		d.addTest(keys, a)
+		d.addOp("remove", keys, nil)
+		return
+	}
+	t := a.Type()
+	if t == TypeObject && b.Type() == TypeObject {
+		a.o.unescapeKeys()
+		for _, kv := range a.o.kvs {
+			if b.o.Get(kv.k) == nil {
+				d.diff(append(keys, kv.k), kv.v, nil)
+			}
+		}
+		b.o.unescapeKeys()
+		for _, kv := range b.o.kvs {
+			d.diff(append(keys, kv.k), a.o.Get(kv.k), kv.v)
+		}
+		return
+	}
+	if t == TypeArray && b.Type() == TypeArray {
+		n := len(a.a)
+		if len(b.a) < n {
+			n = len(b.a)
+		}
+		for i := 0; i < n; i++ {
+			d.diff(append(keys, strconv.Itoa(i)), a.a[i], b.a[i])
+		}
+		// Remove superfluous items starting from the tail, so indexes
+		// of the remaining items remain valid.
+		for i := len(a.a) - 1; i >= n; i-- {
+			d.diff(append(keys, strconv.Itoa(i)), a.a[i], nil)
+		}
+		for i := n; i < len(b.a); i++ {
+			d.diff(append(keys, strconv.Itoa(i)), nil, b.a[i])
+		}
+		return
+	}
+	d.addTest(keys, a)
+	d.addOp("replace", keys, b)
+}
+
+func (d *differ) addTest(keys []string, v *Value) {
+	if d.opts.EmitTests {
+		d.addOp("test", keys, v)
+	}
+}
+
+func (d *differ) addOp(op string, keys []string, v *Value) {
+	o := d.a.NewObject()
+	o.Set("op", d.a.NewString(op))
+	o.Set("path", d.a.NewString(FormatPointer(keys...)))
+	if v != nil {
+		o.Set("value", v)
+	}
+	d.ops.Append(o)
+}
@@ -0,0 +1,68 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestDiffPatch(t *testing.T) {
+	f := func(a, b, patchExpected string) {
+		t.Helper()
+		patch := DiffPatch(MustParse(a), MustParse(b))
+		if s := patch.String(); s != patchExpected {
+			t.Fatalf("unexpected patch for DiffPatch(%s, %s)\ngot\n%s\nwant\n%s", a, b, s, patchExpected)
+		}
+
+		// Applying the patch must produce b.
+		result, err := ApplyPatch(MustParse(a), patch)
+		if err != nil {
+			t.Fatalf("cannot apply patch %s to %s: %s", patch, a, err)
+		}
+		if !equalExact(result, MustParse(b)) {
+			t.Fatalf("unexpected result after applying the patch %s to %s\ngot\n%s\nwant\n%s", patch, a, result, b)
+		}
+	}
+
+	f(`{"a":1}`, `{"a":1}`, `[]`)
+	f(`{"a":1,"b":2}`, `{"b":2,"a":1}`, `[]`)
+	f(`{"a":1}`, `{"a":2}`, `[{"op":"replace","path":"/a","value":2}]`)
+	f(`{"a":1,"b":2}`, `{"b":2}`, `[{"op":"remove","path":"/a"}]`)
+	f(`{"a":1}`, `{"a":1,"b":{"c":3}}`, `[{"op":"add","path":"/b","value":{"c":3}}]`)
+	f(`{"a":{"b":1,"c":2}}`, `{"a":{"b":1,"c":3}}`, `[{"op":"replace","path":"/a/c","value":3}]`)
+	f(`{"a/b":{"~":1}}`, `{"a/b":{"~":2}}`, `[{"op":"replace","path":"/a~1b/~0","value":2}]`)
+	f(`{"a":{"b":1}}`, `{"a":[1]}`, `[{"op":"replace","path":"/a","value":[1]}]`)
+	f(`{"a":1}`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`)
+	f(`{"a":null}`, `{"a":false}`, `[{"op":"replace","path":"/a","value":false}]`)
+
+	// Arrays
+	f(`[1,2,3]`, `[1,5,3]`, `[{"op":"replace","path":"/1","value":5}]`)
+	f(`[1,2,3]`, `[1]`, `[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`)
+	f(`[1]`, `[1,2,3]`, `[{"op":"add","path":"/1","value":2},{"op":"add","path":"/2","value":3}]`)
+	f(`{"a":[{"b":1}]}`, `{"a":[{"b":2}]}`, `[{"op":"replace","path":"/a/0/b","value":2}]`)
+}
+
+func TestDiffOptionsEmitTests(t *testing.T) {
+	opts := &DiffOptions{
+		EmitTests: true,
+	}
+	a := `{"a":1,"b":[1,2],"c":"x"}`
+	b := `{"a":2,"b":[1]}`
+	patch := opts.DiffPatch(MustParse(a), MustParse(b))
+	patchExpected := `[{"op":"test","path":"/c","value":"x"},{"op":"remove","path":"/c"},` +
+		`{"op":"test","path":"/a","value":1},{"op":"replace","path":"/a","value":2},` +
+		`{"op":"test","path":"/b/1","value":2},{"op":"remove","path":"/b/1"}]`
+	if s := patch.String(); s != patchExpected {
+		t.Fatalf("unexpected patch\ngot\n%s\nwant\n%s", s, patchExpected)
+	}
+	result, err := ApplyPatch(MustParse(a), patch)
+	if err != nil {
+		t.Fatalf("cannot apply patch: %s", err)
+	}
+	if !equalExact(result, MustParse(b)) {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, b)
+	}
+
+	// The patch must fail on concurrently modified document.
+	if _, err := ApplyPatch(MustParse(`{"a":3,"b":[1,2],"c":"x"}`), patch); err == nil {
+		t.Fatalf("expecting non-nil error when applying the patch to modified document")
+	}
+}
@@ -0,0 +1,62 @@
+package fastjson
+
+import (
+	"strconv"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Distinct returns unique values found at the given path in v.
+//
+// The path may contain "*" and "**" wildcards as in Aggregate. Values are
+// compared semantically: numbers are compared by their numeric values,
+// so 1 and 1.0 are equal, and object keys order is ignored. The first
+// found value is returned for every set of equal values, in the order
+// the values are found.
+//
+// The returned values are valid until Parse is called on the Parser returned v.
+func Distinct(v *Value, path ...string) []*Value {
+	var vs []*Value
+	if v == nil {
+		return vs
+	}
+
+	// Values are bucketed by a cheap key, so only values in the same bucket
+	// must be compared with equalValues.
+	buckets := make(map[string][]*Value)
+	var buf []byte
+	v.walkWildcardPath(path, func(v *Value) {
+		buf = appendDistinctKey(buf[:0], v)
+		bucket := buckets[string(buf)]
+		for _, x := range bucket {
+			if equalValues(x, v, numbersEqual) {
+				return
+			}
+		}
+		buckets[string(buf)] = append(bucket, v)
+		vs = append(vs, v)
+	})
+	return vs
+}
+
+// appendDistinctKey appends to dst a key, which is equal for equal values.
+func appendDistinctKey(dst []byte, v *Value) []byte {
+	t := v.Type()
+	dst = append(dst, byte(t))
+	switch t {
+	case TypeString:
+		dst = append(dst, v.s...)
+	case TypeNumber:
+		f, err := fastfloat.Parse(v.s)
+		if err != nil {
+			// numbersEqual returns false for unparsable numbers.
+			return append(dst, v.s...)
+		}
+		dst = appendShortestFloat64(dst, f)
+	case TypeArray:
+		dst = strconv.AppendInt(dst, int64(len(v.a)), 10)
+	case TypeObject:
+		dst = strconv.AppendInt(dst, int64(v.o.Len()), 10)
+	}
+	return dst
+}
@@ -0,0 +1,37 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDistinct(t *testing.T) {
+	f := func(s string, path []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		var a []string
+		for _, vv := range Distinct(v, path...) {
+			a = append(a, vv.String())
+		}
+		result := strings.Join(a, ",")
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q over %s\ngot\n%s\nwant\n%s", path, s, result, resultExpected)
+		}
+	}
+
+	items := `{"items":[{"c":"red"},{"c":"blue"},{"c":"red"},{"c":1},{"c":1.0},{"c":"1"},{},{"c":null},{"c":null}]}`
+	f(items, []string{"items", "*", "c"}, `"red","blue",1,"1",null`)
+	f(items, []string{"**", "c"}, `"red","blue",1,"1",null`)
+	f(items, []string{"items", "0", "c"}, `"red"`)
+	f(items, []string{"missing"}, ``)
+
+	// Containers are compared semantically
+	f(`[{"a":1,"b":[1,2]},{"b":[1,2],"a":1e0},{"a":1,"b":[2,1]},[1],[1.0],[1,1]]`, []string{"*"}, `{"a":1,"b":[1,2]},{"a":1,"b":[2,1]},[1],[1,1]`)
+
+	// Escaped strings
+	f(`["ab", "\u0061b", "a\\b"]`, []string{"*"}, `"ab","a\\b"`)
+
+	if vs := Distinct(nil, "*"); len(vs) != 0 {
+		t.Fatalf("unexpected values for nil value: %d", len(vs))
+	}
+}
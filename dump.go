@@ -0,0 +1,160 @@
+package fastjson
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DumpOptions contains options for Value.DumpColored.
+type DumpOptions struct {
+	// Indent is used for indenting nested values.
+	//
+	// Two spaces are used if Indent is empty.
+	Indent string
+
+	// MaxDepth is the maximum nesting depth of the dumped values.
+	//
+	// Deeper objects and arrays are dumped as {…} and […] with the number
+	// of the skipped items. There is no limit if MaxDepth is zero.
+	MaxDepth int
+
+	// MaxStringLen is the maximum number of runes dumped per string.
+	//
+	// Longer strings are truncated and suffixed with the number
+	// of the skipped bytes. There is no limit if MaxStringLen is zero.
+	MaxStringLen int
+
+	// NoColor disables ANSI color escape sequences in the output.
+	NoColor bool
+}
+
+// ANSI color escape sequences used by DumpColored.
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[34;1m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[36m"
+	colorBool   = "\x1b[33m"
+	colorNull   = "\x1b[90m"
+	colorMeta   = "\x1b[35m"
+)
+
+// DumpColored writes indented and syntax-highlighted v to w
+// for interactive debugging in terminals.
+//
+// The output isn't valid JSON if it contains truncated values
+// or ANSI color escape sequences.
+func (v *Value) DumpColored(w io.Writer, opts DumpOptions) error {
+	d := &dumper{
+		opts: &opts,
+	}
+	if len(d.opts.Indent) == 0 {
+		d.opts.Indent = "  "
+	}
+	d.dumpValue(v, 0)
+	d.b = append(d.b, '\n')
+	_, err := w.Write(d.b)
+	return err
+}
+
+type dumper struct {
+	opts *DumpOptions
+	b    []byte
+}
+
+func (d *dumper) dumpValue(v *Value, depth int) {
+	if v == nil {
+		d.appendColored(colorNull, "null")
+		return
+	}
+	switch v.Type() {
+	case TypeObject:
+		if v.o.Len() == 0 {
+			d.b = append(d.b, "{}"...)
+			return
+		}
+		if d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth {
+			d.appendColored(colorMeta, "{…"+strconv.Itoa(v.o.Len())+" keys}")
+			return
+		}
+		d.b = append(d.b, '{')
+		v.o.unescapeKeys()
+		for i, kv := range v.o.kvs {
+			d.appendNewline(depth + 1)
+			d.appendString(colorKey, kv.k)
+			d.b = append(d.b, ": "...)
+			d.dumpValue(kv.v, depth+1)
+			if i != len(v.o.kvs)-1 {
+				d.b = append(d.b, ',')
+			}
+		}
+		d.appendNewline(depth)
+		d.b = append(d.b, '}')
+	case TypeArray:
+		if len(v.a) == 0 {
+			d.b = append(d.b, "[]"...)
+			return
+		}
+		if d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth {
+			d.appendColored(colorMeta, "[…"+strconv.Itoa(len(v.a))+" items]")
+			return
+		}
+		d.b = append(d.b, '[')
+		for i, item := range v.a {
+			d.appendNewline(depth + 1)
+			d.dumpValue(item, depth+1)
+			if i != len(v.a)-1 {
+				d.b = append(d.b, ',')
+			}
+		}
+		d.appendNewline(depth)
+		d.b = append(d.b, ']')
+	case TypeString:
+		d.appendString(colorString, v.s)
+	case TypeNumber:
+		d.appendColored(colorNumber, v.s)
+	case TypeTrue:
+		d.appendColored(colorBool, "true")
+	case TypeFalse:
+		d.appendColored(colorBool, "false")
+	default:
+		d.appendColored(colorNull, "null")
+	}
+}
+
+func (d *dumper) appendString(color, s string) {
+	d.appendColor(color)
+	n := len(s)
+	if d.opts.MaxStringLen > 0 {
+		n = 0
+		for runes := 0; n < len(s) && runes < d.opts.MaxStringLen; runes++ {
+			_, size := utf8.DecodeRuneInString(s[n:])
+			n += size
+		}
+	}
+	d.b = escapeString(d.b, s[:n])
+	d.appendColor(colorReset)
+	if n < len(s) {
+		d.appendColored(colorMeta, "…+"+strconv.Itoa(len(s)-n)+" bytes")
+	}
+}
+
+func (d *dumper) appendColored(color, s string) {
+	d.appendColor(color)
+	d.b = append(d.b, s...)
+	d.appendColor(colorReset)
+}
+
+func (d *dumper) appendColor(color string) {
+	if !d.opts.NoColor {
+		d.b = append(d.b, color...)
+	}
+}
+
+func (d *dumper) appendNewline(depth int) {
+	d.b = append(d.b, '\n')
+	for i := 0; i < depth; i++ {
+		d.b = append(d.b, d.opts.Indent...)
+	}
+}
@@ -0,0 +1,75 @@
+package fastjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValueDumpColored(t *testing.T) {
+	f := func(s string, opts DumpOptions, resultExpected string) {
+		t.Helper()
+		var bb bytes.Buffer
+		if err := MustParse(s).DumpColored(&bb, opts); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		result := bb.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected dump for %s\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	noColor := DumpOptions{
+		NoColor: true,
+	}
+	f(`1`, noColor, "1\n")
+	f(`{}`, noColor, "{}\n")
+	f(`{"a":[1,"x\ny",true,false,null,[],{}]}`, noColor, `{
+  "a": [
+    1,
+    "x\ny",
+    true,
+    false,
+    null,
+    [],
+    {}
+  ]
+}
+`)
+	f(`{"a":{"b":[1,2]},"c":[{"d":1}],"e":"abcdef","ж":"жжжж"}`, DumpOptions{
+		Indent:       "\t",
+		MaxDepth:     1,
+		MaxStringLen: 3,
+		NoColor:      true,
+	}, "{\n\t\"a\": {…1 keys},\n\t\"c\": […1 items],\n\t\"e\": \"abc\"…+3 bytes,\n\t\"ж\": \"жжж\"…+2 bytes\n}\n")
+
+	// Colored output
+	f(`{"a":[1,"x",true,null]}`, DumpOptions{}, strings.Join([]string{
+		"{",
+		`  ` + colorKey + `"a"` + colorReset + `: [`,
+		`    ` + colorNumber + `1` + colorReset + `,`,
+		`    ` + colorString + `"x"` + colorReset + `,`,
+		`    ` + colorBool + `true` + colorReset + `,`,
+		`    ` + colorNull + `null` + colorReset,
+		`  ]`,
+		"}",
+		"",
+	}, "\n"))
+}
+
+func TestValueDumpColoredNil(t *testing.T) {
+	var bb bytes.Buffer
+	var vNil *Value
+	if err := vNil.DumpColored(&bb, DumpOptions{NoColor: true}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != "null\n" {
+		t.Fatalf("unexpected dump for nil value; got %q; want %q", s, "null\n")
+	}
+}
+
+func TestValueDumpColoredWriteError(t *testing.T) {
+	if err := MustParse(`1`).DumpColored(&failingWriter{}, DumpOptions{}); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
@@ -0,0 +1,75 @@
+package fastjson
+
+import (
+	"io"
+	"sync"
+)
+
+// Encoder writes marshaled Values to io.Writer.
+//
+// Encoder uses internal pooled buffers for marshaling, so encoding
+// doesn't allocate memory in steady state.
+//
+// Encoder cannot be used from concurrently running goroutines.
+type Encoder struct {
+	// Options are used for marshaling Values.
+	Options MarshalOptions
+
+	w io.Writer
+}
+
+// NewEncoder returns new Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: w,
+	}
+}
+
+// Encode writes marshaled v to the underlying writer.
+func (e *Encoder) Encode(v *Value) error {
+	return e.encode(v, false)
+}
+
+// EncodeNewline writes marshaled v followed by '\n' to the underlying writer.
+//
+// This is useful for emitting JSON lines.
+func (e *Encoder) EncodeNewline(v *Value) error {
+	return e.encode(v, true)
+}
+
+func (e *Encoder) encode(v *Value, newline bool) error {
+	bb := getEncoderBuffer()
+	bb.b = e.Options.MarshalTo(bb.b[:0], v)
+	if newline {
+		bb.b = append(bb.b, '\n')
+	}
+	_, err := e.w.Write(bb.b)
+	putEncoderBuffer(bb)
+	return err
+}
+
+type encoderBuffer struct {
+	b []byte
+}
+
+// maxPooledEncoderBufferSize is the maximum capacity of buffers
+// returned to encoderBufferPool, so occasional huge Values don't pin
+// huge buffers in the pool.
+const maxPooledEncoderBufferSize = 1 << 20
+
+var encoderBufferPool sync.Pool
+
+func getEncoderBuffer() *encoderBuffer {
+	v := encoderBufferPool.Get()
+	if v == nil {
+		return &encoderBuffer{}
+	}
+	return v.(*encoderBuffer)
+}
+
+func putEncoderBuffer(bb *encoderBuffer) {
+	if cap(bb.b) > maxPooledEncoderBufferSize {
+		return
+	}
+	encoderBufferPool.Put(bb)
+}
@@ -0,0 +1,43 @@
+package fastjson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var bb bytes.Buffer
+	e := NewEncoder(&bb)
+	if err := e.Encode(MustParse(`{"b":1, "a":[ 2 ]}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := e.EncodeNewline(MustParse(` "x" `)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	e.Options.SortKeys = true
+	if err := e.EncodeNewline(MustParse(`{"b":1,"a":2}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s := bb.String()
+	sExpected := `{"b":1,"a":[2]}"x"` + "\n" + `{"a":2,"b":1}` + "\n"
+	if s != sExpected {
+		t.Fatalf("unexpected output\ngot\n%q\nwant\n%q", s, sExpected)
+	}
+}
+
+func TestEncoderWriteError(t *testing.T) {
+	e := NewEncoder(&failingWriter{})
+	if err := e.Encode(MustParse(`1`)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if err := e.EncodeNewline(MustParse(`1`)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+type failingWriter struct{}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
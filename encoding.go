@@ -0,0 +1,313 @@
+package fastjson
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// It returns JSON representation of v.
+func (v *Value) MarshalText() ([]byte, error) {
+	return v.MarshalTo(nil), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// It returns JSON representation of v, so encoding/json emits v as is
+// instead of a quoted string returned by MarshalText.
+func (v *Value) MarshalJSON() ([]byte, error) {
+	return v.MarshalTo(nil), nil
+}
+
+// binaryMagic is the prefix of the binary form returned by Value.MarshalBinary.
+//
+// The last byte is the format version.
+const binaryMagic = "FJB\x01"
+
+// Tags for values in the binary form.
+const (
+	binaryNull = iota
+	binaryTrue
+	binaryFalse
+	binaryNumber
+	binaryString
+	binaryArray
+	binaryObject
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// It returns compact binary form of v, which may be restored
+// via UnmarshalBinary much faster than re-parsing JSON text, since strings
+// are stored unescaped and the memory for the restored values is allocated
+// in a few chunks. This is useful for caching parsed documents
+// in external caches such as memcached or Redis.
+//
+// The binary form is internal to fastjson, so it mustn't be used
+// for data exchange with other software.
+func (v *Value) MarshalBinary() ([]byte, error) {
+	var bc binaryCounts
+	bc.count(v)
+	dst := append([]byte{}, binaryMagic...)
+	dst = appendUvarint(dst, uint64(bc.values))
+	dst = appendUvarint(dst, uint64(bc.items))
+	dst = appendUvarint(dst, uint64(bc.kvs))
+	return appendBinaryValue(dst, v), nil
+}
+
+// binaryCounts contains the numbers of allocations needed for restoring
+// the binary form.
+type binaryCounts struct {
+	// values is the number of values except of null, true and false.
+	values int
+
+	// items is the total number of array items.
+	items int
+
+	// kvs is the total number of object members.
+	kvs int
+}
+
+func (bc *binaryCounts) count(v *Value) {
+	switch v.Type() {
+	case TypeObject:
+		bc.values++
+		bc.kvs += len(v.o.kvs)
+		for _, kv := range v.o.kvs {
+			bc.count(kv.v)
+		}
+	case TypeArray:
+		bc.values++
+		bc.items += len(v.a)
+		for _, vv := range v.a {
+			bc.count(vv)
+		}
+	case TypeString, TypeNumber:
+		bc.values++
+	}
+}
+
+func appendBinaryValue(dst []byte, v *Value) []byte {
+	switch v.Type() {
+	case TypeObject:
+		o := &v.o
+		o.unescapeKeys()
+		dst = append(dst, binaryObject)
+		dst = appendUvarint(dst, uint64(len(o.kvs)))
+		for _, kv := range o.kvs {
+			dst = appendUvarint(dst, uint64(len(kv.k)))
+			dst = append(dst, kv.k...)
+			dst = appendBinaryValue(dst, kv.v)
+		}
+		return dst
+	case TypeArray:
+		dst = append(dst, binaryArray)
+		dst = appendUvarint(dst, uint64(len(v.a)))
+		for _, vv := range v.a {
+			dst = appendBinaryValue(dst, vv)
+		}
+		return dst
+	case TypeString:
+		dst = append(dst, binaryString)
+		dst = appendUvarint(dst, uint64(len(v.s)))
+		return append(dst, v.s...)
+	case TypeNumber:
+		dst = append(dst, binaryNumber)
+		dst = appendUvarint(dst, uint64(len(v.s)))
+		return append(dst, v.s...)
+	case TypeTrue:
+		return append(dst, binaryTrue)
+	case TypeFalse:
+		return append(dst, binaryFalse)
+	case TypeNull:
+		return append(dst, binaryNull)
+	default:
+		panic(fmt.Errorf("BUG: unexpected Value type: %d", v.t))
+	}
+}
+
+func appendUvarint(dst []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(buf[:], n)
+	return append(dst, buf[:size]...)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+//
+// It restores v from data returned by MarshalBinary. data is copied,
+// so it may be modified after returning from UnmarshalBinary.
+func (v *Value) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic) || string(data[:len(binaryMagic)]) != binaryMagic {
+		return fmt.Errorf("cannot unmarshal binary value: missing %q prefix", binaryMagic)
+	}
+	bd := binaryDecoder{
+		b: append([]byte{}, data[len(binaryMagic):]...),
+	}
+	vv, err := bd.decode()
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal binary value at offset %d: %s", len(binaryMagic)+bd.n, err)
+	}
+	*v = *vv
+	return nil
+}
+
+type binaryDecoder struct {
+	b []byte
+
+	// n is the current offset in b.
+	n int
+
+	vs  []Value
+	a   []*Value
+	kvs []kv
+}
+
+func (bd *binaryDecoder) decode() (*Value, error) {
+	var counts [3]int
+	for i := range counts {
+		n, err := bd.readLength()
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = n
+	}
+	bd.vs = make([]Value, counts[0])
+	bd.a = make([]*Value, counts[1])
+	bd.kvs = make([]kv, counts[2])
+
+	v, err := bd.decodeValue(0)
+	if err != nil {
+		return nil, err
+	}
+	if bd.n < len(bd.b) {
+		return nil, fmt.Errorf("unexpected tail: %d bytes", len(bd.b)-bd.n)
+	}
+	if len(bd.vs) > 0 || len(bd.a) > 0 || len(bd.kvs) > 0 {
+		return nil, fmt.Errorf("the number of decoded values mismatches the header")
+	}
+	return v, nil
+}
+
+// readLength reads uvarint, which cannot exceed the number of the remaining bytes.
+//
+// Every counted item occupies at least a byte, so this protects from huge
+// allocations on corrupted data.
+func (bd *binaryDecoder) readLength() (int, error) {
+	n, size := binary.Uvarint(bd.b[bd.n:])
+	if size <= 0 {
+		return 0, fmt.Errorf("cannot read uvarint")
+	}
+	bd.n += size
+	if n > uint64(len(bd.b)-bd.n) {
+		return 0, fmt.Errorf("too big length %d; it exceeds the remaining %d bytes", n, len(bd.b)-bd.n)
+	}
+	return int(n), nil
+}
+
+func (bd *binaryDecoder) readString() (string, error) {
+	n, err := bd.readLength()
+	if err != nil {
+		return "", err
+	}
+	s := b2s(bd.b[bd.n : bd.n+n])
+	bd.n += n
+	return s, nil
+}
+
+func (bd *binaryDecoder) newValue(t Type) (*Value, error) {
+	if len(bd.vs) == 0 {
+		return nil, fmt.Errorf("the number of values exceeds the header")
+	}
+	v := &bd.vs[0]
+	bd.vs = bd.vs[1:]
+	v.t = t
+	return v, nil
+}
+
+func (bd *binaryDecoder) decodeValue(depth int) (*Value, error) {
+	depth++
+	if depth > MaxDepth {
+		return nil, fmt.Errorf("too big depth for the nested value; it exceeds %d", MaxDepth)
+	}
+	if bd.n >= len(bd.b) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	tag := bd.b[bd.n]
+	bd.n++
+	switch tag {
+	case binaryNull:
+		return valueNull, nil
+	case binaryTrue:
+		return valueTrue, nil
+	case binaryFalse:
+		return valueFalse, nil
+	case binaryNumber, binaryString:
+		s, err := bd.readString()
+		if err != nil {
+			return nil, err
+		}
+		t := TypeString
+		if tag == binaryNumber {
+			if tail, err := validateNumber(s); err != nil || tail != "" {
+				return nil, fmt.Errorf("invalid number %q", s)
+			}
+			t = TypeNumber
+		}
+		v, err := bd.newValue(t)
+		if err != nil {
+			return nil, err
+		}
+		v.s = s
+		return v, nil
+	case binaryArray:
+		n, err := bd.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if n > len(bd.a) {
+			return nil, fmt.Errorf("the number of array items exceeds the header")
+		}
+		v, err := bd.newValue(TypeArray)
+		if err != nil {
+			return nil, err
+		}
+		// Limit the capacity, so appending items to v doesn't overwrite items
+		// of other arrays.
+		v.a = bd.a[:n:n]
+		bd.a = bd.a[n:]
+		for i := range v.a {
+			if v.a[i], err = bd.decodeValue(depth); err != nil {
+				return nil, fmt.Errorf("cannot decode array item #%d: %s", i, err)
+			}
+		}
+		return v, nil
+	case binaryObject:
+		n, err := bd.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if n > len(bd.kvs) {
+			return nil, fmt.Errorf("the number of object members exceeds the header")
+		}
+		v, err := bd.newValue(TypeObject)
+		if err != nil {
+			return nil, err
+		}
+		v.o.kvs = bd.kvs[:n:n]
+		v.o.keysUnescaped = true
+		bd.kvs = bd.kvs[n:]
+		for i := range v.o.kvs {
+			kv := &v.o.kvs[i]
+			if kv.k, err = bd.readString(); err != nil {
+				return nil, fmt.Errorf("cannot decode object key #%d: %s", i, err)
+			}
+			if kv.v, err = bd.decodeValue(depth); err != nil {
+				return nil, fmt.Errorf("cannot decode object value for key %q: %s", kv.k, err)
+			}
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected value tag %d", tag)
+	}
+}
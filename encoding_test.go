@@ -0,0 +1,129 @@
+package fastjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler     = (*Value)(nil)
+	_ encoding.BinaryMarshaler   = (*Value)(nil)
+	_ encoding.BinaryUnmarshaler = (*Value)(nil)
+	_ json.Marshaler             = (*Value)(nil)
+)
+
+func TestValueMarshalText(t *testing.T) {
+	v := MustParse(`{"a": [1, "x\n"], "b": null}`)
+	b, err := v.MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != `{"a":[1,"x\n"],"b":null}` {
+		t.Fatalf("unexpected text: %s", b)
+	}
+
+	// encoding/json must emit the value as is.
+	x := struct {
+		V *Value `json:"v"`
+	}{
+		V: v,
+	}
+	b, err = json.Marshal(&x)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(b) != `{"v":{"a":[1,"x\n"],"b":null}}` {
+		t.Fatalf("unexpected encoding/json output: %s", b)
+	}
+}
+
+func TestValueMarshalBinary(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		b, err := MustParse(s).MarshalBinary()
+		if err != nil {
+			t.Fatalf("cannot marshal %s: %s", s, err)
+		}
+		var v Value
+		if err := v.UnmarshalBinary(b); err != nil {
+			t.Fatalf("cannot unmarshal %s: %s", s, err)
+		}
+
+		// The restored value mustn't refer to b.
+		for i := range b {
+			b[i] = 0
+		}
+		sExpected := MustParse(s).String()
+		if result := v.String(); result != sExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, sExpected)
+		}
+	}
+
+	f(`null`)
+	f(`true`)
+	f(`false`)
+	f(`-1.5e10`)
+	f(`"foo\"barሴ\n"`)
+	f(`[]`)
+	f(`{}`)
+	f(`[1, null, true, false, "x", [], {}, [[{"a":[]}]]]`)
+	f(`{"a\nb": {"c": [1, 2, {"d": "e"}]}, "f": null, "": ""}`)
+	f(`{"big":"` + strings.Repeat("x", 1000) + `","items":[` + strings.Repeat(`{"id":1,"tags":["a","b"]},`, 100) + `{}]}`)
+
+	// The restored value must be modifiable.
+	b, err := MustParse(`{"a":[1],"b":[2],"c":{"d":3}}`).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var v Value
+	if err := v.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	v.Get("a").SetArrayItem(1, MustParse(`"x"`))
+	v.Get("c").Set("e", MustParse(`4`))
+	v.Set("f", MustParse(`5`))
+	if s := v.String(); s != `{"a":[1,"x"],"b":[2],"c":{"d":3,"e":4},"f":5}` {
+		t.Fatalf("unexpected value after modification: %s", s)
+	}
+}
+
+func TestValueUnmarshalBinaryError(t *testing.T) {
+	f := func(b []byte) {
+		t.Helper()
+		var v Value
+		if err := v.UnmarshalBinary(b); err == nil {
+			t.Fatalf("expecting non-nil error for %q; got %s", b, &v)
+		}
+	}
+
+	f(nil)
+	f([]byte(`{"a":1}`))
+	f([]byte("FJB\x02\x00\x00\x00\x00"))
+
+	b, err := MustParse(`{"a":[1,"x",{"b":null}],"c":true}`).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Truncated data
+	for i := 0; i < len(b); i++ {
+		f(b[:i])
+	}
+	// Trailing data
+	f(append(b[:len(b):len(b)], 0))
+
+	// Mismatched counts
+	f([]byte(binaryMagic + "\x00\x00\x00\x03\x01"))
+	f([]byte(binaryMagic + "\x02\x00\x00\x03\x011"))
+	f([]byte(binaryMagic + "\x01\x00\x00\x05\x01\x00"))
+	f([]byte(binaryMagic + "\x01\x00\x00\x06\x01\x00\x00"))
+
+	// Invalid data
+	f([]byte(binaryMagic + "\x00\x00\x00\x07"))
+	f([]byte(binaryMagic + "\x01\x00\x00\x03\x02ab"))
+	f([]byte(binaryMagic + "\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff"))
+	f([]byte(binaryMagic + "\x7f\x00\x00\x00"))
+	f([]byte(binaryMagic + "\x00\x00\x00\x04\x7f"))
+	f(append([]byte(binaryMagic+"\x90\x03\x90\x03\x00"), []byte(strings.Repeat("\x05\x01", MaxDepth+1)+"\x00")...))
+}
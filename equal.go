@@ -0,0 +1,138 @@
+package fastjson
+
+import (
+	"math"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// EqualApprox returns true if a and b contain equivalent JSON values.
+//
+// Numbers are considered equal if they differ by no more than absTol
+// or by no more than relTol relative to the bigger magnitude of the two.
+// Object keys order is ignored. Members with duplicate keys are matched
+// by their order of appearance, so the n-th member with the given key in a
+// is compared to the n-th member with the same key in b. Other values
+// are compared exactly.
+//
+// EqualApprox is intended for comparing documents produced by systems
+// with distinct float formatting and rounding.
+func EqualApprox(a, b *Value, relTol, absTol float64) bool {
+	return equalValues(a, b, func(x, y string) bool {
+		return approxNumbersEqual(x, y, relTol, absTol)
+	})
+}
+
+// equalExact returns true if a and b contain the same JSON values
+// with identical number representations. Object keys order is ignored.
+func equalExact(a, b *Value) bool {
+	return equalValues(a, b, func(x, y string) bool {
+		return false
+	})
+}
+
+func equalValues(a, b *Value, numbersEqual func(x, y string) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	t := a.Type()
+	if t != b.Type() {
+		return false
+	}
+	switch t {
+	case TypeObject:
+		if a.o.Len() != b.o.Len() {
+			return false
+		}
+		a.o.unescapeKeys()
+		b.o.unescapeKeys()
+		sameOrder := true
+		for i, kv := range a.o.kvs {
+			// Fast path: keys in both objects follow the same order so far,
+			// so the members at the same position are matching occurrences.
+			sameOrder = sameOrder && b.o.kvs[i].k == kv.k
+			var bv *Value
+			if sameOrder {
+				bv = b.o.kvs[i].v
+			} else {
+				bv = nthMember(b.o.kvs, kv.k, countMembers(a.o.kvs[:i], kv.k))
+			}
+			if !equalValues(kv.v, bv, numbersEqual) {
+				return false
+			}
+		}
+		return true
+	case TypeArray:
+		if len(a.a) != len(b.a) {
+			return false
+		}
+		for i, v := range a.a {
+			if !equalValues(v, b.a[i], numbersEqual) {
+				return false
+			}
+		}
+		return true
+	case TypeString:
+		return a.s == b.s
+	case TypeNumber:
+		return a.s == b.s || numbersEqual(a.s, b.s)
+	default:
+		// true, false and null have no contents.
+		return true
+	}
+}
+
+// countMembers returns the number of members with the given key in kvs.
+func countMembers(kvs []kv, key string) int {
+	n := 0
+	for _, kv := range kvs {
+		if kv.k == key {
+			n++
+		}
+	}
+	return n
+}
+
+// nthMember returns the value of the n-th member with the given key in kvs.
+//
+// nil is returned if kvs contains less than n+1 members with the key.
+func nthMember(kvs []kv, key string, n int) *Value {
+	for _, kv := range kvs {
+		if kv.k == key {
+			if n == 0 {
+				return kv.v
+			}
+			n--
+		}
+	}
+	return nil
+}
+
+func numbersEqual(x, y string) bool {
+	return approxNumbersEqual(x, y, 0, 0)
+}
+
+func approxNumbersEqual(x, y string, relTol, absTol float64) bool {
+	fx, err := fastfloat.Parse(x)
+	if err != nil {
+		return false
+	}
+	fy, err := fastfloat.Parse(y)
+	if err != nil {
+		return false
+	}
+	if fx == fy {
+		return true
+	}
+	if math.IsNaN(fx) || math.IsNaN(fy) {
+		return math.IsNaN(fx) && math.IsNaN(fy)
+	}
+	if math.IsInf(fx, 0) || math.IsInf(fy, 0) {
+		return false
+	}
+	d := math.Abs(fx - fy)
+	if d <= absTol {
+		return true
+	}
+	return d <= relTol*math.Max(math.Abs(fx), math.Abs(fy))
+}
@@ -0,0 +1,61 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestEqualApprox(t *testing.T) {
+	f := func(a, b string, relTol, absTol float64, resultExpected bool) {
+		t.Helper()
+		va := MustParse(a)
+		vb := MustParse(b)
+		result := EqualApprox(va, vb, relTol, absTol)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for EqualApprox(%s, %s, %v, %v); got %v; want %v", a, b, relTol, absTol, result, resultExpected)
+		}
+	}
+
+	f(`null`, `null`, 0, 0, true)
+	f(`true`, `false`, 0, 0, false)
+	f(`"foo"`, `"foo"`, 0, 0, true)
+	f(`"foo"`, `"bar"`, 0, 0, false)
+	f(`1`, `1.0`, 0, 0, true)
+	f(`1e2`, `100`, 0, 0, true)
+	f(`0.1`, `0.10000000001`, 0, 0, false)
+	f(`0.1`, `0.10000000001`, 1e-9, 0, true)
+	f(`0.1`, `0.10000000001`, 0, 1e-9, true)
+	f(`1000`, `1001`, 1e-3, 0, true)
+	f(`1000`, `1002`, 1e-3, 0, false)
+	f(`NaN`, `nan`, 0, 0, true)
+	f(`Inf`, `1e308`, 1, 1, false)
+	f(`1`, `"1"`, 0, 0, false)
+	f(`[1,2,3]`, `[1.0,2.0,3.0]`, 0, 0, true)
+	f(`[1,2,3]`, `[1,2]`, 0, 0, false)
+	f(`{"a":1,"b":[2.5]}`, `{"b":[2.50000001],"a":1}`, 1e-6, 0, true)
+	f(`{"a":1,"b":2}`, `{"a":1,"c":2}`, 0, 0, false)
+	f(`{"a":1}`, `{"a":1,"b":2}`, 0, 0, false)
+
+	// Duplicate keys are matched by their order of appearance
+	f(`{"a":1,"b":3,"a":2}`, `{"b":3,"a":1,"a":2}`, 0, 0, true)
+	f(`{"a":1,"a":2}`, `{"a":2,"a":1}`, 0, 0, false)
+	f(`{"a":1,"a":1}`, `{"a":1,"b":1}`, 0, 0, false)
+	f(`{"a":1,"b":1}`, `{"a":1,"a":1}`, 0, 0, false)
+
+	// Reflexivity
+	for _, s := range []string{`{"a":1,"a":2}`, `{"x":{"a":[1],"a":{"b":2,"b":"c"}},"y":null}`, `[{"a":1,"a":2}]`} {
+		v := MustParse(s)
+		if !EqualApprox(v, v, 0, 0) {
+			t.Fatalf("%s must be equal to itself", s)
+		}
+		if !EqualApprox(v, MustParse(s), 0, 0) {
+			t.Fatalf("%s must be equal to its copy", s)
+		}
+	}
+
+	if !EqualApprox(nil, nil, 0, 0) {
+		t.Fatalf("nil values must be equal")
+	}
+	if EqualApprox(MustParse(`null`), nil, 0, 0) {
+		t.Fatalf("nil value mustn't be equal to null")
+	}
+}
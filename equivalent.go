@@ -0,0 +1,111 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Equivalent returns true if a and b contain semantically equivalent JSON.
+//
+// Object keys order, whitespace and number formatting are ignored,
+// so `{"a":1.0,"b":[1e2]}` is equivalent to `{ "b": [100], "a": 1 }`.
+// Numbers are compared exactly by their decimal values, so big integers
+// exceeding float64 precision are compared correctly.
+//
+// An error is returned if a or b contains invalid JSON.
+func Equivalent(a, b []byte) (bool, error) {
+	pa := handyPool.Get()
+	defer handyPool.Put(pa)
+	va, err := pa.ParseBytes(a)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse a: %s", err)
+	}
+
+	pb := handyPool.Get()
+	defer handyPool.Put(pb)
+	vb, err := pb.ParseBytes(b)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse b: %s", err)
+	}
+	return equalValues(va, vb, decimalNumbersEqual), nil
+}
+
+// decimalNumbersEqual returns true if x and y represent the same decimal number.
+func decimalNumbersEqual(x, y string) bool {
+	if x == y {
+		return true
+	}
+	nx, ok := parseNormalDecimal(x)
+	if !ok {
+		return false
+	}
+	ny, ok := parseNormalDecimal(y)
+	if !ok {
+		return false
+	}
+	return nx == ny
+}
+
+// normalDecimal is a normalized decimal number: (-1)^neg * digits * 10^exp.
+//
+// digits has no leading and trailing zeros. Zero has empty digits.
+type normalDecimal struct {
+	neg    bool
+	digits string
+	exp    int64
+}
+
+// maxNormalDecimalExponentLen limits the number of exponent digits, so the exponent
+// fits int64 without overflow.
+const maxNormalDecimalExponentLen = 15
+
+// parseNormalDecimal parses JSON number s into normalized decimal.
+//
+// false is returned if s isn't a valid JSON number.
+func parseNormalDecimal(s string) (normalDecimal, bool) {
+	var d normalDecimal
+	if _, err := validateNumber(s); err != nil {
+		return d, false
+	}
+	if s[0] == '-' {
+		d.neg = true
+		s = s[1:]
+	}
+	n := strings.IndexAny(s, "eE")
+	mantissa := s
+	if n >= 0 {
+		mantissa = s[:n]
+		e := s[n+1:]
+		expNeg := false
+		if e[0] == '-' || e[0] == '+' {
+			expNeg = e[0] == '-'
+			e = e[1:]
+		}
+		e = strings.TrimLeft(e, "0")
+		if len(e) > maxNormalDecimalExponentLen {
+			return d, false
+		}
+		for i := 0; i < len(e); i++ {
+			d.exp = d.exp*10 + int64(e[i]-'0')
+		}
+		if expNeg {
+			d.exp = -d.exp
+		}
+	}
+
+	intPart, fracPart := mantissa, ""
+	if n := strings.IndexByte(mantissa, '.'); n >= 0 {
+		intPart, fracPart = mantissa[:n], mantissa[n+1:]
+	}
+	d.exp -= int64(len(fracPart))
+	digits := intPart + fracPart
+	digits = strings.TrimLeft(digits, "0")
+	trimmed := strings.TrimRight(digits, "0")
+	d.exp += int64(len(digits) - len(trimmed))
+	d.digits = trimmed
+	if len(d.digits) == 0 {
+		// -0 equals 0.
+		return normalDecimal{}, true
+	}
+	return d, true
+}
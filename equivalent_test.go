@@ -0,0 +1,90 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestEquivalent(t *testing.T) {
+	f := func(a, b string, resultExpected bool) {
+		t.Helper()
+		result, err := Equivalent([]byte(a), []byte(b))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s vs %s; got %v; want %v", a, b, result, resultExpected)
+		}
+		result, err = Equivalent([]byte(b), []byte(a))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s vs %s; got %v; want %v", b, a, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1.0,"b":[1e2]}`, ` { "b" : [ 100 ], "a" : 1 } `, true)
+	f(`{"a":1,"b":2}`, `{"a":1}`, false)
+	f(`{"a":1}`, `{"b":1}`, false)
+	f(`[1,2]`, `[2,1]`, false)
+	f(`"ab"`, `"ab"`, true)
+	f(`"ab"`, `"abc"`, false)
+	f(`null`, `false`, false)
+	f(`true`, `true`, true)
+
+	// Duplicate keys
+	f(`{"a":1,"a":2}`, `{"a":1,"a":2}`, true)
+	f(`{"a":1,"b":[3],"a":2.0}`, `{"b":[3],"a":1,"a":2}`, true)
+	f(`{"a":1,"a":2}`, `{"a":2,"a":1}`, false)
+	f(`{"a":1,"a":2}`, `{"a":1,"b":2}`, false)
+
+	// Numbers
+	f(`0`, `-0.0e10`, true)
+	f(`1.5`, `15e-1`, true)
+	f(`1.5`, `0.015E+2`, true)
+	f(`100`, `1E2`, true)
+	f(`1200`, `12e+002`, true)
+	f(`-1`, `1`, false)
+	f(`0.1`, `0.10000000000000001`, false)
+	f(`12345678901234567890`, `12345678901234567891`, false)
+	f(`12345678901234567890`, `1.234567890123456789e19`, true)
+	f(`1e1000000000`, `1e1000000000`, true)
+	f(`1e1000000000`, `10e999999999`, true)
+	f(`1e1000000000`, `1e1000000001`, false)
+
+	// Reflexivity
+	for _, s := range []string{`{"a":1,"a":2}`, `[{"x":{"b":1,"b":[]}},{"x":1,"x":1}]`, `1e1000000000`} {
+		if ok, err := Equivalent([]byte(s), []byte(s)); err != nil || !ok {
+			t.Fatalf("%s must be equivalent to itself; got %v, %v", s, ok, err)
+		}
+	}
+
+	// Invalid JSON
+	for _, s := range []string{``, `{`, `[1,]`} {
+		if _, err := Equivalent([]byte(s), []byte(`1`)); err == nil {
+			t.Fatalf("expecting non-nil error for a=%q", s)
+		}
+		if _, err := Equivalent([]byte(`1`), []byte(s)); err == nil {
+			t.Fatalf("expecting non-nil error for b=%q", s)
+		}
+	}
+}
+
+func TestParseNormalDecimal(t *testing.T) {
+	f := func(s string, dExpected normalDecimal, okExpected bool) {
+		t.Helper()
+		d, ok := parseNormalDecimal(s)
+		if ok != okExpected || d != dExpected {
+			t.Fatalf("unexpected result for %q; got %+v, %v; want %+v, %v", s, d, ok, dExpected, okExpected)
+		}
+	}
+
+	f(`0`, normalDecimal{}, true)
+	f(`-0.000`, normalDecimal{}, true)
+	f(`120`, normalDecimal{digits: "12", exp: 1}, true)
+	f(`-0.0120e-3`, normalDecimal{neg: true, digits: "12", exp: -6}, true)
+	f(`1e0000000000000000000001`, normalDecimal{digits: "1", exp: 1}, true)
+	f(`1e1234567890123456`, normalDecimal{}, false)
+	f(`1e`, normalDecimal{}, false)
+	f(`nan`, normalDecimal{}, false)
+}
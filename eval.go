@@ -0,0 +1,955 @@
+package fastjson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Expr is a compiled jq-like expression.
+//
+// Expr may be obtained via CompileExpr. It may be evaluated concurrently
+// from multiple goroutines on distinct values.
+type Expr struct {
+	expr string
+	f    exprFunc
+}
+
+// exprFunc evaluates an expression for v and returns the produced values.
+type exprFunc func(v *Value) ([]*Value, error)
+
+// CompileExpr compiles jq-like expression.
+//
+// The following subset of jq language is supported:
+//
+//   - `.` returns the input value.
+//   - `.foo`, `."foo bar"`, `.[2]`, `.[-1]` and `.["foo"]` return object
+//     members and array items. Missing members and items are returned as null.
+//   - `.[]` returns all the array items or object values.
+//   - `f | g` passes values produced by f to g.
+//   - `f, g` returns values produced by f followed by values produced by g.
+//   - `[f]` collects values produced by f into an array.
+//   - `{a: f, "b": g, c}` constructs an object. `{c}` is a shorthand for `{c: .c}`.
+//   - String, number, true, false and null literals.
+//   - Arithmetic operators +, -, *, / and %. + concatenates strings and arrays
+//     and merges objects.
+//   - Comparison operators ==, !=, <, <=, > and >=. Values are ordered
+//     as null < false < true < number < string < array < object.
+//   - Boolean operators `and`, `or` and `not`. Only false and null are falsy.
+//   - Functions map(f), select(f), length and keys.
+func CompileExpr(expr string) (*Expr, error) {
+	p := exprParser{
+		s: expr,
+	}
+	if err := p.next(); err != nil {
+		return nil, fmt.Errorf("cannot parse expression %q: %s", expr, err)
+	}
+	f, err := p.parsePipe()
+	if err == nil && p.kind != exprTokenEOF {
+		err = fmt.Errorf("unexpected %q", p.text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse expression %q: %s", expr, err)
+	}
+	return &Expr{
+		expr: expr,
+		f:    f,
+	}, nil
+}
+
+// MustCompileExpr compiles jq-like expression.
+//
+// The function panics if expr cannot be compiled.
+func MustCompileExpr(expr string) *Expr {
+	e, err := CompileExpr(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source of e.
+func (e *Expr) String() string {
+	return e.expr
+}
+
+// Eval evaluates e for v and returns the produced values.
+//
+// The returned values may refer to v, so they are valid until Parse
+// is called on the Parser returned v. v isn't modified.
+func (e *Expr) Eval(v *Value) ([]*Value, error) {
+	if v == nil {
+		v = valueNull
+	}
+	vs, err := e.f(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot evaluate expression %q: %s", e.expr, err)
+	}
+	return vs, nil
+}
+
+// Eval evaluates jq-like expression for v and returns the produced values.
+//
+// See CompileExpr for the supported expressions. Use CompileExpr
+// for evaluating the same expression multiple times.
+func Eval(v *Value, expr string) ([]*Value, error) {
+	e, err := CompileExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(v)
+}
+
+const (
+	exprTokenEOF = iota
+	exprTokenDot
+	exprTokenField
+	exprTokenIdent
+	exprTokenString
+	exprTokenNumber
+	exprTokenPunct
+)
+
+// exprParser is a recursive descent parser for jq-like expressions.
+type exprParser struct {
+	// s is the unparsed tail of the expression.
+	s string
+
+	// kind is the kind of the current token.
+	kind int
+
+	// text is the current token text. It contains unescaped string
+	// for string tokens and fields.
+	text string
+
+	// depth is the nesting depth of the currently parsed primary expression.
+	depth int
+}
+
+// exprOps contains punctuation tokens. Longer tokens must go first.
+var exprOps = []string{"==", "!=", "<=", ">=", "|", ",", "(", ")", "[", "]", "{", "}", ":", "+", "-", "*", "/", "%", "<", ">"}
+
+// next reads the next token.
+func (p *exprParser) next() error {
+	s := skipWS(p.s)
+	if len(s) == 0 {
+		p.s = s
+		p.kind = exprTokenEOF
+		p.text = ""
+		return nil
+	}
+	switch {
+	case s[0] == '.':
+		s = s[1:]
+		if len(s) > 0 && isExprIdentChar(s[0], true) {
+			n := exprIdentLen(s)
+			p.kind = exprTokenField
+			p.text = s[:n]
+			p.s = s[n:]
+			return nil
+		}
+		if len(s) > 0 && s[0] == '"' {
+			str, tail, err := parseExprString(s)
+			if err != nil {
+				return err
+			}
+			p.kind = exprTokenField
+			p.text = str
+			p.s = tail
+			return nil
+		}
+		p.kind = exprTokenDot
+		p.text = "."
+		p.s = s
+		return nil
+	case s[0] == '"':
+		str, tail, err := parseExprString(s)
+		if err != nil {
+			return err
+		}
+		p.kind = exprTokenString
+		p.text = str
+		p.s = tail
+		return nil
+	case s[0] >= '0' && s[0] <= '9':
+		n := 0
+		for n < len(s) && (s[n] >= '0' && s[n] <= '9' || s[n] == '.' || s[n] == 'e' || s[n] == 'E' ||
+			(s[n] == '-' || s[n] == '+') && (s[n-1] == 'e' || s[n-1] == 'E')) {
+			n++
+		}
+		if _, err := validateNumber(s[:n]); err != nil {
+			return fmt.Errorf("cannot parse number %q: %s", s[:n], err)
+		}
+		p.kind = exprTokenNumber
+		p.text = s[:n]
+		p.s = s[n:]
+		return nil
+	case isExprIdentChar(s[0], true):
+		n := exprIdentLen(s)
+		p.kind = exprTokenIdent
+		p.text = s[:n]
+		p.s = s[n:]
+		return nil
+	}
+	for _, op := range exprOps {
+		if strings.HasPrefix(s, op) {
+			p.kind = exprTokenPunct
+			p.text = op
+			p.s = s[len(op):]
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected char %q", s[:1])
+}
+
+func isExprIdentChar(c byte, first bool) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || !first && c >= '0' && c <= '9'
+}
+
+func exprIdentLen(s string) int {
+	n := 1
+	for n < len(s) && isExprIdentChar(s[n], false) {
+		n++
+	}
+	return n
+}
+
+// parseExprString parses JSON string at the start of s and returns
+// the unescaped string with the tail after it.
+func parseExprString(s string) (string, string, error) {
+	_, tail, err := validateString(s[1:])
+	if err != nil {
+		return "", s, fmt.Errorf("cannot parse string: %s", err)
+	}
+	raw := s[1 : len(s)-len(tail)-1]
+	b := append([]byte(nil), raw...)
+	return unescapeStringBestEffort(b2s(b)), tail, nil
+}
+
+func (p *exprParser) isPunct(text string) bool {
+	return p.kind == exprTokenPunct && p.text == text
+}
+
+func (p *exprParser) expectPunct(text string) error {
+	if !p.isPunct(text) {
+		if p.kind == exprTokenEOF {
+			return fmt.Errorf("missing %q", text)
+		}
+		return fmt.Errorf("expecting %q; got %q", text, p.text)
+	}
+	return p.next()
+}
+
+func (p *exprParser) parsePipe() (exprFunc, error) {
+	f, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("|") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		f = exprPipe(f, g)
+	}
+	return f, nil
+}
+
+func (p *exprParser) parseComma() (exprFunc, error) {
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct(",") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		f = exprComma(f, g)
+	}
+	return f, nil
+}
+
+func (p *exprParser) parseOr() (exprFunc, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.kind == exprTokenIdent && p.text == "or" {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		f = exprBool(f, g, true)
+	}
+	return f, nil
+}
+
+func (p *exprParser) parseAnd() (exprFunc, error) {
+	f, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.kind == exprTokenIdent && p.text == "and" {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		f = exprBool(f, g, false)
+	}
+	return f, nil
+}
+
+func (p *exprParser) parseCompare() (exprFunc, error) {
+	f, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if p.kind != exprTokenPunct {
+		return f, nil
+	}
+	op := p.text
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return f, nil
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	g, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return exprBinary(f, g, func(a, b *Value) (*Value, error) {
+		return exprCompare(op, a, b), nil
+	}), nil
+}
+
+func (p *exprParser) parseAdditive() (exprFunc, error) {
+	f, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		f = exprBinary(f, g, func(a, b *Value) (*Value, error) {
+			return exprArith(op, a, b)
+		})
+	}
+	return f, nil
+}
+
+func (p *exprParser) parseMultiplicative() (exprFunc, error) {
+	f, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		op := p.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		g, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		f = exprBinary(f, g, func(a, b *Value) (*Value, error) {
+			return exprArith(op, a, b)
+		})
+	}
+	return f, nil
+}
+
+func (p *exprParser) parsePostfix() (exprFunc, error) {
+	f, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.kind == exprTokenField:
+			f = exprPipe(f, exprField(p.text))
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		case p.kind == exprTokenDot && strings.HasPrefix(skipWS(p.s), "["):
+			// `.[...]` after a term, e.g. `.foo.[0]`.
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		case p.isPunct("["):
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.isPunct("]") {
+				f = exprPipe(f, exprIterate)
+			} else {
+				idx, err := p.parsePipe()
+				if err != nil {
+					return nil, err
+				}
+				if !p.isPunct("]") {
+					return nil, p.expectPunct("]")
+				}
+				f = exprIndex(f, idx)
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		default:
+			return f, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprFunc, error) {
+	p.depth++
+	defer func() {
+		p.depth--
+	}()
+	if p.depth > MaxDepth {
+		return nil, fmt.Errorf("too big depth for the nested expression; it exceeds %d", MaxDepth)
+	}
+	switch p.kind {
+	case exprTokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	case exprTokenDot:
+		return exprIdentity, p.next()
+	case exprTokenField:
+		f := exprField(p.text)
+		return f, p.next()
+	case exprTokenNumber:
+		v := &Value{
+			t: TypeNumber,
+			s: p.text,
+		}
+		return exprConst(v), p.next()
+	case exprTokenString:
+		return exprConst(newExprString(p.text)), p.next()
+	case exprTokenIdent:
+		return p.parseIdent()
+	}
+
+	switch p.text {
+	case "(":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expectPunct(")")
+	case "[":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.isPunct("]") {
+			return exprCollect(nil), p.next()
+		}
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return exprCollect(f), p.expectPunct("]")
+	case "{":
+		return p.parseObject()
+	case "-":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		f, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return exprBinary(exprConst(&Value{t: TypeNumber, s: "0"}), f, func(a, b *Value) (*Value, error) {
+			return exprArith("-", a, b)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", p.text)
+	}
+}
+
+func (p *exprParser) parseIdent() (exprFunc, error) {
+	name := p.text
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "true":
+		return exprConst(valueTrue), nil
+	case "false":
+		return exprConst(valueFalse), nil
+	case "null":
+		return exprConst(valueNull), nil
+	case "not":
+		return exprNot, nil
+	case "length":
+		return exprLength, nil
+	case "keys":
+		return exprKeys, nil
+	case "map", "select":
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		f, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		if name == "map" {
+			return exprCollect(exprPipe(exprIterate, f)), nil
+		}
+		return exprSelect(f), nil
+	default:
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+}
+
+func (p *exprParser) parseObject() (exprFunc, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	var fs []exprFunc
+	for !p.isPunct("}") {
+		if len(keys) > 0 {
+			if err := p.expectPunct(","); err != nil {
+				return nil, err
+			}
+		}
+		if p.kind != exprTokenIdent && p.kind != exprTokenString {
+			return nil, fmt.Errorf("expecting object key; got %q", p.text)
+		}
+		key := p.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		f := exprField(key)
+		if p.isPunct(":") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			var err error
+			f, err = p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+		fs = append(fs, f)
+	}
+	return exprObject(keys, fs), p.next()
+}
+
+func exprIdentity(v *Value) ([]*Value, error) {
+	return []*Value{v}, nil
+}
+
+func exprConst(c *Value) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		return []*Value{c}, nil
+	}
+}
+
+func exprPipe(f, g exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		vs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		var result []*Value
+		for _, x := range vs {
+			ys, err := g(x)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, ys...)
+		}
+		return result, nil
+	}
+}
+
+func exprComma(f, g exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		xs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		ys, err := g(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(xs, ys...), nil
+	}
+}
+
+func exprField(key string) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		switch v.Type() {
+		case TypeNull:
+			return []*Value{valueNull}, nil
+		case TypeObject:
+			x := v.o.Get(key)
+			if x == nil {
+				x = valueNull
+			}
+			return []*Value{x}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with %q", v.Type(), key)
+		}
+	}
+}
+
+func exprIndex(f, idx exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		xs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		is, err := idx(v)
+		if err != nil {
+			return nil, err
+		}
+		var result []*Value
+		for _, x := range xs {
+			for _, i := range is {
+				y, err := exprIndexValue(x, i)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, y)
+			}
+		}
+		return result, nil
+	}
+}
+
+func exprIndexValue(x, i *Value) (*Value, error) {
+	switch {
+	case i.Type() == TypeString:
+		vs, err := exprField(string(i.GetStringBytes()))(x)
+		if err != nil {
+			return nil, err
+		}
+		return vs[0], nil
+	case i.Type() == TypeNumber && x.Type() == TypeNull:
+		return valueNull, nil
+	case i.Type() == TypeNumber && x.Type() == TypeArray:
+		n := int(math.Floor(fastfloat.ParseBestEffort(i.s)))
+		if n < 0 {
+			n += len(x.a)
+		}
+		if n < 0 || n >= len(x.a) {
+			return valueNull, nil
+		}
+		return x.a[n], nil
+	default:
+		return nil, fmt.Errorf("cannot index %s with %s", x.Type(), i.Type())
+	}
+}
+
+func exprIterate(v *Value) ([]*Value, error) {
+	switch v.Type() {
+	case TypeArray:
+		return append([]*Value(nil), v.a...), nil
+	case TypeObject:
+		result := make([]*Value, 0, len(v.o.kvs))
+		for _, kv := range v.o.kvs {
+			result = append(result, kv.v)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", v.Type())
+	}
+}
+
+func exprCollect(f exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		a := &Value{
+			t: TypeArray,
+		}
+		if f != nil {
+			vs, err := f(v)
+			if err != nil {
+				return nil, err
+			}
+			a.a = vs
+		}
+		return []*Value{a}, nil
+	}
+}
+
+func exprObject(keys []string, fs []exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		// Every combination of the produced member values results in an object.
+		objs := []*Value{{t: TypeObject}}
+		for i, f := range fs {
+			vs, err := f(v)
+			if err != nil {
+				return nil, err
+			}
+			var next []*Value
+			for _, o := range objs {
+				for _, x := range vs {
+					no := &Value{
+						t: TypeObject,
+					}
+					no.o.kvs = append(no.o.kvs, o.o.kvs...)
+					no.o.keysUnescaped = true
+					no.o.Set(keys[i], x)
+					next = append(next, no)
+				}
+			}
+			objs = next
+		}
+		return objs, nil
+	}
+}
+
+func exprSelect(f exprFunc) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		cs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		var result []*Value
+		for _, c := range cs {
+			if isExprTruthy(c) {
+				result = append(result, v)
+			}
+		}
+		return result, nil
+	}
+}
+
+func exprNot(v *Value) ([]*Value, error) {
+	return []*Value{newExprBool(!isExprTruthy(v))}, nil
+}
+
+func exprLength(v *Value) ([]*Value, error) {
+	var n float64
+	switch v.Type() {
+	case TypeNull:
+		n = 0
+	case TypeString:
+		n = float64(utf8.RuneCountInString(v.s))
+	case TypeArray:
+		n = float64(len(v.a))
+	case TypeObject:
+		n = float64(len(v.o.kvs))
+	case TypeNumber:
+		n = math.Abs(fastfloat.ParseBestEffort(v.s))
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, fmt.Errorf("%s has no finite length", v)
+		}
+	default:
+		return nil, fmt.Errorf("%s has no length", v.Type())
+	}
+	return []*Value{newExprNumber(n)}, nil
+}
+
+func exprKeys(v *Value) ([]*Value, error) {
+	a := &Value{
+		t: TypeArray,
+	}
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		keys := make([]string, 0, len(v.o.kvs))
+		for _, kv := range v.o.kvs {
+			keys = append(keys, kv.k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			a.a = append(a.a, newExprString(key))
+		}
+	case TypeArray:
+		for i := range v.a {
+			a.a = append(a.a, newExprNumber(float64(i)))
+		}
+	default:
+		return nil, fmt.Errorf("%s has no keys", v.Type())
+	}
+	return []*Value{a}, nil
+}
+
+func exprBool(f, g exprFunc, isOr bool) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		xs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		var result []*Value
+		for _, x := range xs {
+			if isExprTruthy(x) == isOr {
+				// Short circuit.
+				result = append(result, newExprBool(isOr))
+				continue
+			}
+			ys, err := g(v)
+			if err != nil {
+				return nil, err
+			}
+			for _, y := range ys {
+				result = append(result, newExprBool(isExprTruthy(y)))
+			}
+		}
+		return result, nil
+	}
+}
+
+func exprBinary(f, g exprFunc, op func(a, b *Value) (*Value, error)) exprFunc {
+	return func(v *Value) ([]*Value, error) {
+		xs, err := f(v)
+		if err != nil {
+			return nil, err
+		}
+		ys, err := g(v)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]*Value, 0, len(xs)*len(ys))
+		for _, x := range xs {
+			for _, y := range ys {
+				z, err := op(x, y)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, z)
+			}
+		}
+		return result, nil
+	}
+}
+
+func exprCompare(op string, a, b *Value) *Value {
+	if op == "==" || op == "!=" {
+		return newExprBool(equalValues(a, b, numbersEqual) == (op == "=="))
+	}
+	n := compareValues(a, b)
+	switch op {
+	case "<":
+		return newExprBool(n < 0)
+	case "<=":
+		return newExprBool(n <= 0)
+	case ">":
+		return newExprBool(n > 0)
+	default:
+		return newExprBool(n >= 0)
+	}
+}
+
+func exprArith(op string, a, b *Value) (*Value, error) {
+	ta, tb := a.Type(), b.Type()
+	if op == "+" {
+		switch {
+		case ta == TypeNull:
+			return b, nil
+		case tb == TypeNull:
+			return a, nil
+		case ta == TypeString && tb == TypeString:
+			return newExprString(a.s + b.s), nil
+		case ta == TypeArray && tb == TypeArray:
+			c := &Value{
+				t: TypeArray,
+			}
+			c.a = append(append(c.a, a.a...), b.a...)
+			return c, nil
+		case ta == TypeObject && tb == TypeObject:
+			c := &Value{
+				t: TypeObject,
+			}
+			a.o.unescapeKeys()
+			b.o.unescapeKeys()
+			c.o.kvs = append(c.o.kvs, a.o.kvs...)
+			c.o.keysUnescaped = true
+			for _, kv := range b.o.kvs {
+				c.o.Set(kv.k, kv.v)
+			}
+			return c, nil
+		}
+	}
+	if ta != TypeNumber || tb != TypeNumber {
+		return nil, fmt.Errorf("%s and %s cannot be used with %q", ta, tb, op)
+	}
+	x := fastfloat.ParseBestEffort(a.s)
+	y := fastfloat.ParseBestEffort(b.s)
+	var z float64
+	switch op {
+	case "+":
+		z = x + y
+	case "-":
+		z = x - y
+	case "*":
+		z = x * y
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("%s and %s cannot be divided because the divisor is zero", a, b)
+		}
+		z = x / y
+	case "%":
+		n := int64(y)
+		if n == 0 {
+			return nil, fmt.Errorf("%s and %s cannot be divided because the divisor is zero", a, b)
+		}
+		z = float64(int64(x) % n)
+	}
+	if math.IsInf(z, 0) || math.IsNaN(z) {
+		return nil, fmt.Errorf("%s and %s cannot be used with %q because the result isn't a finite number", a, b, op)
+	}
+	return newExprNumber(z), nil
+}
+
+func isExprTruthy(v *Value) bool {
+	t := v.Type()
+	return t != TypeNull && t != TypeFalse
+}
+
+func newExprBool(b bool) *Value {
+	if b {
+		return valueTrue
+	}
+	return valueFalse
+}
+
+func newExprNumber(f float64) *Value {
+	return &Value{
+		t: TypeNumber,
+		s: strconv.FormatFloat(f, 'g', -1, 64),
+	}
+}
+
+func newExprString(s string) *Value {
+	return &Value{
+		t: TypeString,
+		s: s,
+	}
+}
@@ -0,0 +1,164 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	f := func(s, expr, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		vs, err := Eval(v, expr)
+		if err != nil {
+			t.Fatalf("unexpected error when evaluating %q: %s", expr, err)
+		}
+		var a []string
+		for _, x := range vs {
+			a = append(a, x.String())
+		}
+		result := strings.Join(a, ";")
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q over %s\ngot\n%s\nwant\n%s", expr, s, result, resultExpected)
+		}
+	}
+
+	// Field access
+	f(`{"a":{"b":[1,2,3]}}`, `.`, `{"a":{"b":[1,2,3]}}`)
+	f(`{"a":{"b":[1,2,3]}}`, `.a.b`, `[1,2,3]`)
+	f(`{"a":{"b":[1,2,3]}}`, `.a.b[1]`, `2`)
+	f(`{"a":{"b":[1,2,3]}}`, `.a.b[-1]`, `3`)
+	f(`{"a":{"b":[1,2,3]}}`, `.a.b[5]`, `null`)
+	f(`{"a":{"b":[1,2,3]}}`, `.a.b[]`, `1;2;3`)
+	f(`{"a":{"b":[1,2,3]}}`, `.missing.x`, `null`)
+	f(`{"a b":1,"c\"d":2}`, `."a b", .["c\"d"]`, `1;2`)
+	f(`[[1,2],[3]]`, `.[][0]`, `1;3`)
+	f(`{"x":1,"y":2}`, `.[]`, `1;2`)
+	f(`{"n":1,"a":[10,20]}`, `.a[.n]`, `20`)
+
+	// Pipes and comma
+	f(`{"items":[{"id":1},{"id":2}]}`, `.items[] | .id`, `1;2`)
+	f(`{"a":1,"b":2}`, `.a, .b`, `1;2`)
+	f(`{"a":1,"b":2}`, `[.a, .b] | length`, `2`)
+
+	// Arithmetic
+	f(`{"a":7,"b":2}`, `.a + .b * 3`, `13`)
+	f(`{"a":7,"b":2}`, `(.a + .b) * 3`, `27`)
+	f(`{"a":7,"b":2}`, `.a / .b, .a % .b, .a - .b - 1`, `3.5;1;4`)
+	f(`{"a":7}`, `-.a`, `-7`)
+	f(`{"a":"foo","b":"bar"}`, `.a + "-" + .b`, `"foo-bar"`)
+	f(`{"a":[1],"b":[2,3]}`, `.a + .b`, `[1,2,3]`)
+	f(`{"a":{"x":1,"y":2},"b":{"y":3}}`, `.a + .b`, `{"x":1,"y":3}`)
+	f(`{"a":1}`, `.a + null, null + .a`, `1;1`)
+
+	// Comparisons and boolean operators
+	f(`{"a":1,"b":"x"}`, `.a == 1, .a != 1, .a < 2, .a >= 2, .b > .a`, `true;false;true;false;true`)
+	f(`{"a":1.0}`, `.a == 1`, `true`)
+	f(`{"a":[1,{"b":2}]}`, `.a == [1, {b: 2}]`, `true`)
+	f(`{"a":true,"b":null}`, `.a and .b, .a or .b, (.b | not), (0 | not)`, `false;true;true;false`)
+
+	// Functions
+	f(`[1,2,3]`, `map(. * 10)`, `[10,20,30]`)
+	f(`[1,5,2,8]`, `map(select(. > 2))`, `[5,8]`)
+	f(`[{"n":"a","age":30},{"n":"b","age":10}]`, `.[] | select(.age >= 18) | .n`, `"a"`)
+	f(`{"b":1,"a":2}`, `keys`, `["a","b"]`)
+	f(`["x","y"]`, `keys`, `[0,1]`)
+	f(`{"s":"привет","o":{"a":1},"n":-3,"z":null}`, `.s, .o, .n, .z | length`, `6;1;3;0`)
+
+	// Construction
+	f(`{"id":1,"user":{"name":"x"}}`, `{id, name: .user.name, "k": [.id, 2]}`, `{"id":1,"name":"x","k":[1,2]}`)
+	f(`{"a":[1,2]}`, `{x: .a[]}`, `{"x":1};{"x":2}`)
+	f(`null`, `[], {}, [1, "a", true, false, null]`, `[];{};[1,"a",true,false,null]`)
+
+	// Deeply nested expressions
+	nested := strings.Repeat("[", MaxDepth-1) + "1" + strings.Repeat("]", MaxDepth-1)
+	f(`null`, nested, nested)
+}
+
+func TestEvalError(t *testing.T) {
+	f := func(s, expr string) {
+		t.Helper()
+		v := MustParse(s)
+		vs, err := Eval(v, expr)
+		if err == nil {
+			t.Fatalf("expecting non-nil error when evaluating %q over %s; got %d values", expr, s, len(vs))
+		}
+	}
+
+	// Compile errors
+	f(`{}`, ``)
+	f(`{}`, `.a |`)
+	f(`{}`, `(.a`)
+	f(`{}`, `[.a`)
+	f(`{}`, `.a ]`)
+	f(`{}`, `{a: 1`)
+	f(`{}`, `{1: 2}`)
+	f(`{}`, `foo(.)`)
+	f(`{}`, `map .a`)
+	f(`{}`, `.a == .b == .c`)
+	f(`{}`, `."a`)
+	f(`{}`, `"\x"`)
+	f(`{}`, `1e`)
+	f(`{}`, `.a ; .b`)
+
+	// Too deep expressions
+	f(`{}`, strings.Repeat("[", MaxDepth+1)+"1"+strings.Repeat("]", MaxDepth+1))
+	f(`{}`, strings.Repeat("(", 1e6))
+	f(`{}`, strings.Repeat("[", 1e6))
+	f(`{}`, strings.Repeat("-", 1e6)+"1")
+	f(`{}`, strings.Repeat("{a:", 1e6))
+	f(`{}`, strings.Repeat("map(", 1e6))
+
+	// Runtime errors
+	f(`{"a":1}`, `.a.b`)
+	f(`{"a":1}`, `.a[]`)
+	f(`[1]`, `.x`)
+	f(`{"a":[1]}`, `.a[true]`)
+	f(`{"a":1}`, `.a / 0`)
+	f(`{"a":1}`, `.a % 0`)
+	f(`{"a":1}`, `.a + "x"`)
+	f(`{"a":"x"}`, `.a * 2`)
+	f(`{"a":true}`, `.a | length`)
+	f(`{"a":1}`, `.a | keys`)
+	f(`1`, `map(.)`)
+
+	// Non-finite numbers
+	f(`{}`, `1e308 * 10`)
+	f(`{}`, `(1e308 * 10) - (1e308 * 10)`)
+	f(`{}`, `-1e308 - 1e308`)
+	f(`{"a":1e400}`, `.a + 1`)
+	f(`{"a":1e400}`, `.a | length`)
+}
+
+func TestExprConcurrent(t *testing.T) {
+	e := MustCompileExpr(`.items | map(select(.n > 1) | .n * 2)`)
+	if s := e.String(); s != `.items | map(select(.n > 1) | .n * 2)` {
+		t.Fatalf("unexpected String(); got %q", s)
+	}
+	ch := make(chan error, 4)
+	for i := 0; i < cap(ch); i++ {
+		go func() {
+			v := MustParse(`{"items":[{"n":1},{"n":2},{"n":3}]}`)
+			vs, err := e.Eval(v)
+			if err == nil && (len(vs) != 1 || vs[0].String() != `[4,6]`) {
+				err = fmt.Errorf("unexpected result: %d values", len(vs))
+			}
+			ch <- err
+		}()
+	}
+	for i := 0; i < cap(ch); i++ {
+		if err := <-ch; err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// Eval must not modify the input.
+	v := MustParse(`{"a":{"x":1},"b":{"y":2}}`)
+	if _, err := Eval(v, `.a + .b, [.a] + [.b]`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := v.String(); s != `{"a":{"x":1},"b":{"y":2}}` {
+		t.Fatalf("unexpected input modification; got %s", s)
+	}
+}
@@ -551,3 +551,123 @@ func Parse(s string) (float64, error) {
 
 var inf = math.Inf(1)
 var nan = math.NaN()
+
+// ParseFloat32BestEffort parses float32 number s.
+//
+// It is equivalent to strconv.ParseFloat(s, 32), but is faster.
+// It is also faster and more precise than float32(ParseBestEffort(s)),
+// since it avoids double rounding.
+//
+// 0 is returned if the number cannot be parsed.
+// See also ParseFloat32, which returns parse error if the number cannot be parsed.
+func ParseFloat32BestEffort(s string) float32 {
+	f, err := ParseFloat32(s)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// ParseFloat32 parses float32 number s.
+//
+// It is equivalent to strconv.ParseFloat(s, 32), but is faster.
+//
+// See also ParseFloat32BestEffort.
+func ParseFloat32(s string) (float32, error) {
+	if f, ok := parseFloat32Fast(s); ok {
+		return f, nil
+	}
+	// Slow path - fall back to standard parsing.
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil && !math.IsInf(f, 0) {
+		return 0, fmt.Errorf("cannot parse float32 from %q: %s", s, err)
+	}
+	return float32(f), nil
+}
+
+// parseFloat32Fast parses s if its mantissa and exponent are small enough
+// for exact float32 conversion.
+//
+// See https://www.exploringbinary.com/fast-path-decimal-to-floating-point-conversion/
+func parseFloat32Fast(s string) (float32, bool) {
+	i := 0
+	minus := len(s) > 0 && s[0] == '-'
+	if minus {
+		i++
+	}
+	d := uint32(0)
+	digits := 0
+	exp := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		d = d*10 + uint32(s[i]-'0')
+		digits++
+		i++
+		if digits > 7 {
+			return 0, false
+		}
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		k := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			d = d*10 + uint32(s[i]-'0')
+			digits++
+			i++
+			if digits > 7 {
+				return 0, false
+			}
+		}
+		exp -= i - k
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		expMinus := false
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			expMinus = s[i] == '-'
+			i++
+		}
+		e := 0
+		j := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			e = e*10 + int(s[i]-'0')
+			i++
+			if e > 100 {
+				return 0, false
+			}
+		}
+		if i == j {
+			return 0, false
+		}
+		if expMinus {
+			e = -e
+		}
+		exp += e
+	}
+	if i < len(s) {
+		return 0, false
+	}
+	// Both d and 10^exp are exactly representable as float32 here,
+	// so a single multiplication or division gives correctly rounded result.
+	f := float32(d)
+	if exp > 0 {
+		if exp >= len(float32pow10) {
+			return 0, false
+		}
+		f *= float32pow10[exp]
+	} else if exp < 0 {
+		if -exp >= len(float32pow10) {
+			return 0, false
+		}
+		f /= float32pow10[-exp]
+	}
+	if minus {
+		f = -f
+	}
+	return f, true
+}
+
+// Exact powers of 10 for float32.
+var float32pow10 = [...]float32{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10}
@@ -507,3 +507,77 @@ func TestParseFuzz(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFloat32(t *testing.T) {
+	f := func(s string, expectedNum float32) {
+		t.Helper()
+
+		num, err := ParseFloat32(s)
+		if err != nil {
+			t.Fatalf("unexpected error in ParseFloat32(%q): %s", s, err)
+		}
+		if math.IsNaN(float64(expectedNum)) {
+			if !math.IsNaN(float64(num)) {
+				t.Fatalf("unexpected number parsed from %q; got %v; want %v", s, num, expectedNum)
+			}
+			return
+		}
+		if num != expectedNum {
+			t.Fatalf("unexpected number parsed from %q; got %v; want %v", s, num, expectedNum)
+		}
+		if numBestEffort := ParseFloat32BestEffort(s); numBestEffort != expectedNum {
+			t.Fatalf("unexpected number parsed by ParseFloat32BestEffort(%q); got %v; want %v", s, numBestEffort, expectedNum)
+		}
+	}
+
+	f("0", 0)
+	f("-0", 0)
+	f("123", 123)
+	f("-1.5", -1.5)
+	f("0.1", 0.1)
+	f("1234.567", 1234.567)
+	f("1e10", 1e10)
+	f("1.5e-3", 1.5e-3)
+	f("123456789", 123456789)
+	f("3.4028235e38", math.MaxFloat32)
+	f("1e39", float32(math.Inf(1)))
+	f("-inf", float32(math.Inf(-1)))
+	f("nan", float32(math.NaN()))
+
+	// double rounding via float64 gives wrong result for this number
+	f("1.00000017881393432617187499", 1.0000001)
+
+	fErr := func(s string) {
+		t.Helper()
+		if _, err := ParseFloat32(s); err == nil {
+			t.Fatalf("expecting non-nil error for %q", s)
+		}
+		if num := ParseFloat32BestEffort(s); num != 0 {
+			t.Fatalf("expecting zero from ParseFloat32BestEffort(%q); got %v", s, num)
+		}
+	}
+	fErr("")
+	fErr("foo")
+	fErr("1.2.3")
+	fErr("1e")
+	fErr("-")
+}
+
+func TestParseFloat32Fuzz(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 100000; i++ {
+		f := r.Float32() * float32(r.Intn(1e6))
+		s := strconv.FormatFloat(float64(f), 'g', -1, 32)
+		numExpected, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", s, err)
+		}
+		num, err := ParseFloat32(s)
+		if err != nil {
+			t.Fatalf("unexpected error in ParseFloat32(%q): %s", s, err)
+		}
+		if num != float32(numExpected) {
+			t.Fatalf("unexpected number parsed from %q; got %g; want %g", s, num, numExpected)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package fastjson
+
+// FindAll returns all the values for the given key found at any depth in v.
+//
+// Values are returned in depth-first order. Values nested inside
+// the found values are searched too.
+//
+// The returned values are valid until Parse is called on the Parser returned v.
+func (v *Value) FindAll(key string) []*Value {
+	var vs []*Value
+	v.FindAllFunc(key, func(v *Value) bool {
+		vs = append(vs, v)
+		return true
+	})
+	return vs
+}
+
+// FindAllFunc calls f for each value for the given key found at any depth in v.
+//
+// Values are visited in depth-first order. The search stops when f returns false.
+func (v *Value) FindAllFunc(key string, f func(v *Value) bool) {
+	if v == nil {
+		return
+	}
+	v.findAll(key, f)
+}
+
+func (v *Value) findAll(key string, f func(v *Value) bool) bool {
+	switch v.t {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for _, kv := range v.o.kvs {
+			if kv.k == key && !f(kv.v) {
+				return false
+			}
+			if !kv.v.findAll(key, f) {
+				return false
+			}
+		}
+	case TypeArray:
+		for _, vv := range v.a {
+			if !vv.findAll(key, f) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GetAll returns all the values matching the given keys path.
+//
+// The "**" key matches zero or more levels of nesting at any depth,
+// so GetAll("**", "error", "code") returns "code" values from "error"
+// objects found at any depth in v. Other keys are matched as in Get:
+// array indexes may be represented as decimal numbers in keys.
+//
+// Values are returned in depth-first order. The same value may be returned
+// multiple times if keys contain multiple "**" keys.
+//
+// The returned values are valid until Parse is called on the Parser returned v.
+func (v *Value) GetAll(keys ...string) []*Value {
+	var vs []*Value
+	if v == nil {
+		return vs
+	}
+	v.getAll(keys, func(v *Value) {
+		vs = append(vs, v)
+	})
+	return vs
+}
+
+func (v *Value) getAll(keys []string, f func(v *Value)) {
+	for len(keys) > 0 && keys[0] != "**" {
+		v = v.Get(keys[0])
+		if v == nil {
+			return
+		}
+		keys = keys[1:]
+	}
+	if len(keys) == 0 {
+		f(v)
+		return
+	}
+
+	// Skip repeated "**" keys, since they match the same values.
+	for len(keys) > 1 && keys[1] == "**" {
+		keys = keys[1:]
+	}
+	v.getAll(keys[1:], f)
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for _, kv := range v.o.kvs {
+			kv.v.getAll(keys, f)
+		}
+	case TypeArray:
+		for _, vv := range v.a {
+			vv.getAll(keys, f)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueFindAll(t *testing.T) {
+	v := MustParse(`{"password":"a","user":{"name":"x","password":"b"},"items":[{"password":{"password":"c"}},1,"password"],"password":"d"}`)
+
+	vs := v.FindAll("password")
+	var result []string
+	for _, vv := range vs {
+		result = append(result, vv.String())
+	}
+	resultExpected := []string{`"a"`, `"b"`, `{"password":"c"}`, `"c"`, `"d"`}
+	if len(result) != len(resultExpected) {
+		t.Fatalf("unexpected number of found values; got %d; want %d; values: %q", len(result), len(resultExpected), result)
+	}
+	for i := range result {
+		if result[i] != resultExpected[i] {
+			t.Fatalf("unexpected value #%d; got %s; want %s", i, result[i], resultExpected[i])
+		}
+	}
+
+	if vs := v.FindAll("missing"); len(vs) != 0 {
+		t.Fatalf("unexpected values found for missing key: %v", vs)
+	}
+
+	// Stop the search early.
+	n := 0
+	v.FindAllFunc("password", func(v *Value) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Fatalf("unexpected number of callback calls; got %d; want %d", n, 2)
+	}
+
+	var vNil *Value
+	if vs := vNil.FindAll("x"); vs != nil {
+		t.Fatalf("unexpected values found in nil value: %v", vs)
+	}
+}
+
+func TestValueGetAll(t *testing.T) {
+	v := MustParse(`{"error":{"code":1},"items":[{"error":{"code":2,"msg":"x"}},{"a":{"b":{"error":{"code":3}}}},{"error":"e"}],"code":4}`)
+
+	f := func(keys []string, resultExpected string) {
+		t.Helper()
+		var result string
+		for _, vv := range v.GetAll(keys...) {
+			result += vv.String() + ";"
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected values for %q; got %s; want %s", keys, result, resultExpected)
+		}
+	}
+	f([]string{"**", "error", "code"}, `1;2;3;`)
+	f([]string{"**", "**", "error", "code"}, `1;2;3;`)
+	f([]string{"items", "**", "code"}, `2;3;`)
+	f([]string{"items", "1", "**", "error"}, `{"code":3};`)
+	f([]string{"error", "code"}, `1;`)
+	f([]string{"**", "missing"}, ``)
+	f([]string{"**", "code", "**"}, `4;1;2;3;`)
+	f([]string{"missing", "**"}, ``)
+
+	var vNil *Value
+	if vs := vNil.GetAll("**"); vs != nil {
+		t.Fatalf("unexpected values for nil value: %v", vs)
+	}
+}
@@ -0,0 +1,179 @@
+package fastjson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Flatten returns an object containing all the leaf values from v
+// under keys obtained by joining paths to the values with sep,
+// for example "a.b.0.c" for sep=".".
+//
+// Leaf values are values other than objects and arrays, plus empty
+// objects and arrays. Flatten returns v if it is neither an object
+// nor an array.
+//
+// The returned object refers to values from v, so v must be unchanged
+// during the returned object lifetime.
+func (v *Value) Flatten(sep string) *Value {
+	if v == nil {
+		return nil
+	}
+	t := v.Type()
+	if t != TypeObject && t != TypeArray {
+		return v
+	}
+	result := &Value{
+		t: TypeObject,
+	}
+	v.Walk(func(path []string, v *Value) bool {
+		switch v.Type() {
+		case TypeObject:
+			if v.o.Len() > 0 {
+				return true
+			}
+		case TypeArray:
+			if len(v.a) > 0 {
+				return true
+			}
+		}
+		if len(path) > 0 {
+			result.o.Set(strings.Join(path, sep), v)
+		}
+		return false
+	})
+	return result
+}
+
+// Unflatten is the inverse of Flatten. It returns nested objects and arrays
+// reconstructed from the object v with keys joined with sep.
+//
+// Intermediate arrays are created for key parts containing decimal
+// array indexes, while intermediate objects are created for other parts.
+// Indexes exceeding both 20 and the number of entries in v are used
+// as object keys, so keys like "a.1000000" cannot allocate huge arrays.
+// The root of the result is always an object. Entries conflicting with
+// already reconstructed values are skipped, so the first entry wins.
+// Unflatten returns v if it isn't an object.
+//
+// The returned value refers to values from v, so v must be unchanged
+// during the returned value lifetime.
+func Unflatten(v *Value, sep string) *Value {
+	if v == nil || v.Type() != TypeObject {
+		return v
+	}
+	result := &Value{
+		t: TypeObject,
+	}
+	u := unflattener{
+		maxArrayIndex: len(v.o.kvs),
+	}
+	if u.maxArrayIndex < 20 {
+		u.maxArrayIndex = 20
+	}
+	v.o.unescapeKeys()
+	for _, kv := range v.o.kvs {
+		var parts []string
+		if len(sep) == 0 {
+			parts = []string{kv.k}
+		} else {
+			parts = strings.Split(kv.k, sep)
+		}
+		u.add(result, parts, kv.v)
+	}
+	return result
+}
+
+type unflattener struct {
+	maxArrayIndex int
+
+	// filled holds array items set by previous entries, so they can be
+	// told apart from null items padding the arrays.
+	filled map[arraySlot]bool
+}
+
+type arraySlot struct {
+	a   *Value
+	idx int
+}
+
+// add sets the value at the given path in dst unless it conflicts
+// with already reconstructed values.
+func (u *unflattener) add(dst *Value, parts []string, value *Value) {
+	for i, part := range parts[:len(parts)-1] {
+		child, ok := u.get(dst, part)
+		if !ok {
+			return
+		}
+		if child == nil {
+			child = &Value{
+				t: TypeObject,
+			}
+			if _, ok := u.arrayIndex(parts[i+1]); ok {
+				child.t = TypeArray
+			}
+			u.set(dst, part, child)
+		} else if child.t != TypeObject && child.t != TypeArray {
+			return
+		}
+		dst = child
+	}
+	key := parts[len(parts)-1]
+	if child, ok := u.get(dst, key); ok && child == nil {
+		u.set(dst, key, value)
+	}
+}
+
+// get returns the value for the given key in dst. It returns false
+// if the key cannot address dst items.
+func (u *unflattener) get(dst *Value, key string) (*Value, bool) {
+	if dst.t == TypeObject {
+		return dst.o.Get(key), true
+	}
+	n, ok := u.arrayIndex(key)
+	if !ok {
+		return nil, false
+	}
+	if !u.filled[arraySlot{dst, n}] {
+		return nil, true
+	}
+	return dst.a[n], true
+}
+
+func (u *unflattener) set(dst *Value, key string, value *Value) {
+	if dst.t == TypeObject {
+		dst.o.Set(key, value)
+		return
+	}
+	n, _ := u.arrayIndex(key)
+	dst.SetArrayItem(n, value)
+	if u.filled == nil {
+		u.filled = make(map[arraySlot]bool)
+	}
+	u.filled[arraySlot{dst, n}] = true
+}
+
+// arrayIndex returns the array index for the given key part.
+func (u *unflattener) arrayIndex(s string) (int, bool) {
+	if !isArrayIndex(s) || len(s) > 9 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n > u.maxArrayIndex {
+		return 0, false
+	}
+	return n, true
+}
+
+// isArrayIndex returns true if s is a decimal array index without leading zeros.
+func isArrayIndex(s string) bool {
+	if len(s) == 0 || (len(s) > 1 && s[0] == '0') {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueFlatten(t *testing.T) {
+	f := func(s, sep, resultExpected string) {
+		t.Helper()
+		result := MustParse(s).Flatten(sep).String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for Flatten(%q) on %s\ngot\n%s\nwant\n%s", sep, s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1}`, ".", `{"a":1}`)
+	f(`{"a":{"b":[{"c":1},2]},"d":"x"}`, ".", `{"a.b.0.c":1,"a.b.1":2,"d":"x"}`)
+	f(`{"a":{"b":null,"c":true}}`, "_", `{"a_b":null,"a_c":true}`)
+	f(`{"a":{},"b":[],"c":{"d":[]}}`, ".", `{"a":{},"b":[],"c.d":[]}`)
+	f(`[1,[2]]`, "/", `{"0":1,"1/0":2}`)
+	f(`{}`, ".", `{}`)
+	f(`[]`, ".", `{}`)
+	f(`"foo"`, ".", `"foo"`)
+	f(`123`, ".", `123`)
+
+	var vNil *Value
+	if v := vNil.Flatten("."); v != nil {
+		t.Fatalf("expecting nil result for nil value; got %s", v)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	f := func(s, sep, resultExpected string) {
+		t.Helper()
+		result := Unflatten(MustParse(s), sep).String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for Unflatten(%q) on %s\ngot\n%s\nwant\n%s", sep, s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1}`, ".", `{"a":1}`)
+	f(`{"a.b.0.c":1,"a.b.1":2,"d":"x"}`, ".", `{"a":{"b":[{"c":1},2]},"d":"x"}`)
+	f(`{"a_b":null,"a_c":true}`, "_", `{"a":{"b":null,"c":true}}`)
+	f(`{"a":{},"b":[],"c.d":[]}`, ".", `{"a":{},"b":[],"c":{"d":[]}}`)
+	f(`{"0":1,"1/0":2}`, "/", `{"0":1,"1":[2]}`)
+	f(`{"a.2":1}`, ".", `{"a":[null,null,1]}`)
+	f(`{"a.01":1}`, ".", `{"a":{"01":1}}`)
+	f(`{"a.b":1}`, "", `{"a.b":1}`)
+	f(`{}`, ".", `{}`)
+	f(`[1]`, ".", `[1]`)
+
+	// Huge array indexes
+	f(`{"x.100000000":1}`, ".", `{"x":{"100000000":1}}`)
+	f(`{"x.20":1,"y.21":2}`, ".", `{"x":[null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,1],"y":{"21":2}}`)
+	f(`{"a.0":1,"a.100000000":2}`, ".", `{"a":[1]}`)
+
+	// Conflicting entries
+	f(`{"a":1,"a.b":2}`, ".", `{"a":1}`)
+	f(`{"c.d":3,"c":4}`, ".", `{"c":{"d":3}}`)
+	f(`{"a.0":1,"a.b":2}`, ".", `{"a":[1]}`)
+	f(`{"a":1,"a":2}`, ".", `{"a":1}`)
+	f(`{"a.1":1,"a.0":2,"a.1":3}`, ".", `{"a":[2,1]}`)
+	f(`{"a.0":null,"a.0.b":1}`, ".", `{"a":[null]}`)
+
+	// Round trip
+	s := `{"a":{"b":[{"c":1},[2,{}]],"d":{"e":"x"}},"f":[]}`
+	v := Unflatten(MustParse(s).Flatten("."), ".")
+	if v.String() != s {
+		t.Fatalf("unexpected round trip result\ngot\n%s\nwant\n%s", v, s)
+	}
+}
@@ -0,0 +1,462 @@
+package fastjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewFromStruct returns new value containing x converted via reflection.
+//
+// x may be a struct, a map, a slice or any other value supported
+// by encoding/json. The conversion follows encoding/json rules:
+//
+//   - Struct fields are named according to `json` tags. Tags with "-" name,
+//     unexported fields and empty fields with "omitempty" option are skipped.
+//     The "string" option encodes numbers and bools as strings.
+//   - Fields of embedded structs are promoted to the outer struct according
+//     to Go visibility rules. Ambiguous fields are skipped. Fields are emitted
+//     in declaration order, where promoted fields replace the embedded struct.
+//   - Map keys must be strings, integers or implement encoding.TextMarshaler.
+//     Object keys for maps are sorted.
+//   - []byte is encoded as base64 string. time.Time is encoded
+//     as RFC 3339 string. Nil pointers, slices, maps and interfaces
+//     are encoded as null.
+//   - Types implementing json.Marshaler or encoding.TextMarshaler
+//     are encoded via these interfaces. Methods with pointer receivers
+//     are used only for addressable values such as values referred
+//     by pointers, slice items and fields of addressable structs.
+//   - *Value is copied as is.
+//
+// An error is returned for unsupported values such as channels, funcs,
+// NaN and Inf floats.
+//
+// The returned value is valid until Reset is called on a.
+func (a *Arena) NewFromStruct(x interface{}) (*Value, error) {
+	return a.newFromReflect(reflect.ValueOf(x), 0)
+}
+
+var (
+	valueType         = reflect.TypeOf((*Value)(nil))
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (a *Arena) newFromReflect(rv reflect.Value, depth int) (*Value, error) {
+	if !rv.IsValid() {
+		return valueNull, nil
+	}
+	depth++
+	if depth > MaxDepth {
+		return nil, fmt.Errorf("too big depth for the converted value; it exceeds %d; the value may contain cycles", MaxDepth)
+	}
+
+	t := rv.Type()
+	switch {
+	case t == valueType:
+		v := rv.Interface().(*Value)
+		if v == nil {
+			return valueNull, nil
+		}
+		return v.CopyTo(a), nil
+	case t == timeType:
+		return a.NewTime(rv.Interface().(time.Time), time.RFC3339Nano), nil
+	case t.Implements(jsonMarshalerType):
+		if isNilReflect(rv) {
+			return valueNull, nil
+		}
+		return a.newFromJSONMarshaler(rv.Interface().(json.Marshaler))
+	case rv.Kind() != reflect.Ptr && rv.CanAddr() && reflect.PtrTo(t).Implements(jsonMarshalerType):
+		// MarshalJSON with pointer receiver is called for addressable values
+		// in the same way as encoding/json does.
+		return a.newFromJSONMarshaler(rv.Addr().Interface().(json.Marshaler))
+	case t.Implements(textMarshalerType):
+		if isNilReflect(rv) {
+			return valueNull, nil
+		}
+		return a.newFromTextMarshaler(rv.Interface().(encoding.TextMarshaler))
+	case rv.Kind() != reflect.Ptr && rv.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType):
+		return a.newFromTextMarshaler(rv.Addr().Interface().(encoding.TextMarshaler))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return valueTrue, nil
+		}
+		return valueFalse, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bLen := len(a.b)
+		a.b = strconv.AppendInt(a.b, rv.Int(), 10)
+		return a.NewNumberString(b2s(a.b[bLen:])), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bLen := len(a.b)
+		a.b = strconv.AppendUint(a.b, rv.Uint(), 10)
+		return a.NewNumberString(b2s(a.b[bLen:])), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("cannot convert %v to JSON number", f)
+		}
+		bLen := len(a.b)
+		a.b = strconv.AppendFloat(a.b, f, 'g', -1, t.Bits())
+		return a.NewNumberString(b2s(a.b[bLen:])), nil
+	case reflect.String:
+		return a.NewString(rv.String()), nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return valueNull, nil
+		}
+		return a.newFromReflect(rv.Elem(), depth)
+	case reflect.Slice:
+		if rv.IsNil() {
+			return valueNull, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !t.Elem().Implements(jsonMarshalerType) && !t.Elem().Implements(textMarshalerType) {
+			return a.NewBytesBase64(rv.Bytes()), nil
+		}
+		return a.newArrayFromReflect(rv, depth)
+	case reflect.Array:
+		return a.newArrayFromReflect(rv, depth)
+	case reflect.Map:
+		if rv.IsNil() {
+			return valueNull, nil
+		}
+		return a.newObjectFromMap(rv, depth)
+	case reflect.Struct:
+		return a.newObjectFromStruct(rv, depth)
+	default:
+		return nil, fmt.Errorf("cannot convert value of type %s", t)
+	}
+}
+
+func isNilReflect(rv reflect.Value) bool {
+	k := rv.Kind()
+	return (k == reflect.Ptr || k == reflect.Interface) && rv.IsNil()
+}
+
+func (a *Arena) newFromJSONMarshaler(m json.Marshaler) (*Value, error) {
+	b, err := m.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %T to JSON: %s", m, err)
+	}
+	p := handyPool.Get()
+	v, err := p.ParseBytes(b)
+	if err != nil {
+		handyPool.Put(p)
+		return nil, fmt.Errorf("cannot parse JSON returned from %T.MarshalJSON: %s", m, err)
+	}
+	v = v.CopyTo(a)
+	handyPool.Put(p)
+	return v, nil
+}
+
+func (a *Arena) newFromTextMarshaler(m encoding.TextMarshaler) (*Value, error) {
+	b, err := m.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %T to text: %s", m, err)
+	}
+	return a.NewStringBytes(b), nil
+}
+
+func (a *Arena) newArrayFromReflect(rv reflect.Value, depth int) (*Value, error) {
+	arr := a.NewArray()
+	n := rv.Len()
+	for i := 0; i < n; i++ {
+		v, err := a.newFromReflect(rv.Index(i), depth)
+		if err != nil {
+			return nil, err
+		}
+		arr.a = append(arr.a, v)
+	}
+	return arr, nil
+}
+
+func (a *Arena) newObjectFromMap(rv reflect.Value, depth int) (*Value, error) {
+	type mapEntry struct {
+		key string
+		v   reflect.Value
+	}
+	entries := make([]mapEntry, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{
+			key: key,
+			v:   iter.Value(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	o := a.NewObject()
+	for _, e := range entries {
+		v, err := a.newFromReflect(e.v, depth)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert map value for key %q: %s", e.key, err)
+		}
+		kv := o.o.getKV()
+		kv.k = e.key
+		kv.v = v
+	}
+	o.o.keysUnescaped = true
+	return o, nil
+}
+
+func mapKeyString(rv reflect.Value) (string, error) {
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	if tm, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		if isNilReflect(rv) {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("cannot marshal map key %v to text: %s", rv, err)
+		}
+		return string(b), nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("unsupported map key type %s", rv.Type())
+	}
+}
+
+func (a *Arena) newObjectFromStruct(rv reflect.Value, depth int) (*Value, error) {
+	o := a.NewObject()
+	for _, f := range getStructFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		v, err := a.newFromReflect(fv, depth)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert field %q: %s", f.name, err)
+		}
+		if f.asString {
+			switch v.t {
+			case TypeNumber:
+				v = a.NewString(v.s)
+			case TypeTrue:
+				v = a.NewString("true")
+			case TypeFalse:
+				v = a.NewString("false")
+			}
+		}
+		kv := o.o.getKV()
+		kv.k = f.name
+		kv.v = v
+	}
+	o.o.keysUnescaped = true
+	return o, nil
+}
+
+// fieldByIndex returns the field with the given index path in rv.
+//
+// false is returned if the path goes through a nil embedded pointer.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, n := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return rv, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(n)
+	}
+	return rv, true
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// structField describes a struct field converted by NewFromStruct.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	asString  bool
+
+	// tagged is set if the name is obtained from `json` tag.
+	tagged bool
+}
+
+var structFieldsCache sync.Map
+
+func getStructFields(t reflect.Type) []structField {
+	if fs, ok := structFieldsCache.Load(t); ok {
+		return fs.([]structField)
+	}
+	fs := dominantStructFields(collectStructFields(t))
+	structFieldsCache.Store(t, fs)
+	return fs
+}
+
+// collectStructFields returns all the fields of t including the fields
+// promoted from embedded structs.
+//
+// Embedded structs are visited level by level in the same way as
+// encoding/json does, so shallower fields are collected first.
+func collectStructFields(t reflect.Type) []structField {
+	type embeddedStruct struct {
+		t     reflect.Type
+		index []int
+	}
+	var fields []structField
+	next := []embeddedStruct{{t: t}}
+	var count map[reflect.Type]int
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, map[reflect.Type]int{}
+		for _, es := range current {
+			if visited[es.t] {
+				continue
+			}
+			visited[es.t] = true
+			for i := 0; i < es.t.NumField(); i++ {
+				sf := es.t.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.PkgPath != "" && (!sf.Anonymous || ft.Kind() != reflect.Struct) {
+					// Unexported field.
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := tag, ""
+				if n := strings.IndexByte(tag, ','); n >= 0 {
+					name, opts = tag[:n], tag[n+1:]
+				}
+				index := append(append([]int(nil), es.index...), i)
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					// Fields of embedded structs are collected at the next level.
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, embeddedStruct{
+							t:     ft,
+							index: index,
+						})
+					}
+					continue
+				}
+				f := structField{
+					name:   name,
+					index:  index,
+					tagged: name != "",
+				}
+				if name == "" {
+					f.name = sf.Name
+				}
+				for opts != "" {
+					var opt string
+					opt, opts = opts, ""
+					if n := strings.IndexByte(opt, ','); n >= 0 {
+						opt, opts = opt[:n], opt[n+1:]
+					}
+					switch opt {
+					case "omitempty":
+						f.omitEmpty = true
+					case "string":
+						f.asString = true
+					}
+				}
+				fields = append(fields, f)
+				if count[es.t] > 1 {
+					// The struct is embedded multiple times at the same level,
+					// so its fields conflict with each other. Add the field twice,
+					// so it is dropped by dominantStructFields.
+					fields = append(fields, f)
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// dominantStructFields returns fields, which win over other fields
+// with the same name according to Go visibility rules extended with
+// `json` tags. The returned fields are sorted in declaration order.
+func dominantStructFields(fields []structField) []structField {
+	sort.Slice(fields, func(i, j int) bool {
+		x, y := &fields[i], &fields[j]
+		if x.name != y.name {
+			return x.name < y.name
+		}
+		if len(x.index) != len(y.index) {
+			return len(x.index) < len(y.index)
+		}
+		if x.tagged != y.tagged {
+			return x.tagged
+		}
+		return lessIndex(x.index, y.index)
+	})
+	dst := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		// The field is ambiguous if other field with the same name
+		// has the same depth and the same tagged state.
+		if j-i == 1 || len(fields[i].index) < len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			dst = append(dst, fields[i])
+		}
+		i = j
+	}
+	sort.Slice(dst, func(i, j int) bool {
+		return lessIndex(dst[i].index, dst[j].index)
+	})
+	return dst
+}
+
+func lessIndex(x, y []int) bool {
+	for i, n := range x {
+		if i >= len(y) {
+			return false
+		}
+		if n != y[i] {
+			return n < y[i]
+		}
+	}
+	return len(x) < len(y)
+}
@@ -0,0 +1,249 @@
+package fastjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+type testEmbedded struct {
+	E    int    `json:"e"`
+	Name string `json:"name"`
+}
+
+type testEmbeddedPtr struct {
+	P string
+}
+
+type testTextKey struct {
+	a, b int
+}
+
+func (k testTextKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d-%d", k.a, k.b)), nil
+}
+
+type testJSONMarshaler struct{}
+
+func (testJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom":[1,2]}`), nil
+}
+
+type testBadJSONMarshaler struct{}
+
+func (testBadJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{`), nil
+}
+
+type testPtrJSONMarshaler struct {
+	N int
+}
+
+func (m *testPtrJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"ptr":%d}`, m.N)), nil
+}
+
+type testPtrTextMarshaler struct {
+	N int
+}
+
+func (m *testPtrTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("text-%d", m.N)), nil
+}
+
+type testInner struct {
+	A int `json:"a"`
+	B int
+	C int
+}
+
+type testInnerOther struct {
+	B int
+	C int `json:"C"`
+}
+
+type testOrder struct {
+	X int `json:"x"`
+	testInner
+	testInnerOther
+	*testEmbeddedPtr
+	Y int `json:"y"`
+	C int `json:"c"`
+	J testPtrJSONMarshaler
+	T testPtrTextMarshaler
+	S []testPtrJSONMarshaler
+	M map[string]testPtrJSONMarshaler
+}
+
+type testStruct struct {
+	testEmbedded
+	*testEmbeddedPtr
+
+	Name       string  `json:"name"`
+	ID         int64   `json:"id"`
+	U          uint8   `json:"u,omitempty"`
+	F          float32 `json:"f"`
+	G          float64 `json:"g,omitempty"`
+	S          int     `json:"s,string"`
+	B          bool    `json:"b,string"`
+	Skip       string  `json:"-"`
+	Default    []string
+	Bytes      []byte              `json:"bytes"`
+	Arr        [2]int              `json:"arr"`
+	Map        map[string]int      `json:"map"`
+	IntMap     map[int]bool        `json:"int_map,omitempty"`
+	TextMap    map[testTextKey]int `json:"text_map,omitempty"`
+	Ptr        *int                `json:"ptr"`
+	Iface      interface{}         `json:"iface"`
+	Time       time.Time           `json:"time"`
+	Value      *Value              `json:"value"`
+	Custom     testJSONMarshaler   `json:"custom"`
+	Raw        json.RawMessage     `json:"raw,omitempty"`
+	unexported int
+}
+
+func TestArenaNewFromStruct(t *testing.T) {
+	var a Arena
+	f := func(x interface{}, resultExpected string) {
+		t.Helper()
+		v, err := a.NewFromStruct(x)
+		if err != nil {
+			t.Fatalf("unexpected error for %#v: %s", x, err)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %#v\ngot\n%s\nwant\n%s", x, result, resultExpected)
+		}
+	}
+
+	f(nil, `null`)
+	f(true, `true`)
+	f(-12, `-12`)
+	f(uint64(math.MaxUint64), `18446744073709551615`)
+	f(1.5, `1.5`)
+	f(float32(0.1), `0.1`)
+	f("a\"b", `"a\"b"`)
+	f([]int{1, 2}, `[1,2]`)
+	f([]int(nil), `null`)
+	f([]interface{}{"x", nil, map[string]interface{}{"b": 1, "a": []string{}}}, `["x",null,{"a":[],"b":1}]`)
+	f(map[string]int(nil), `null`)
+	f(map[testTextKey]int{{2, 1}: 1, {1, 2}: 2}, `{"1-2":2,"2-1":1}`)
+
+	n := 7
+	v := MustParse(`{"x":[1]}`)
+	x := testStruct{
+		testEmbedded:    testEmbedded{E: 1, Name: "hidden"},
+		testEmbeddedPtr: &testEmbeddedPtr{P: "p"},
+		Name:            "foo",
+		ID:              -5,
+		F:               0.25,
+		S:               42,
+		B:               true,
+		Skip:            "skip",
+		Bytes:           []byte("hi"),
+		Arr:             [2]int{3, 4},
+		Map:             map[string]int{"z": 1, "a": 2},
+		IntMap:          map[int]bool{10: true, 2: false},
+		Ptr:             &n,
+		Iface:           []int{1},
+		Time:            time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Value:           v,
+		Raw:             json.RawMessage(`[true]`),
+		unexported:      1,
+	}
+	f(x, `{"e":1,"P":"p","name":"foo","id":-5,"f":0.25,"s":"42","b":"true","Default":null,"bytes":"aGk=","arr":[3,4],"map":{"a":2,"z":1},"int_map":{"10":true,"2":false},"ptr":7,"iface":[1],"time":"2020-01-02T03:04:05Z","value":{"x":[1]},"custom":{"custom":[1,2]},"raw":[true]}`)
+	f(&x, `{"e":1,"P":"p","name":"foo","id":-5,"f":0.25,"s":"42","b":"true","Default":null,"bytes":"aGk=","arr":[3,4],"map":{"a":2,"z":1},"int_map":{"10":true,"2":false},"ptr":7,"iface":[1],"time":"2020-01-02T03:04:05Z","value":{"x":[1]},"custom":{"custom":[1,2]},"raw":[true]}`)
+	f(testStruct{}, `{"e":0,"name":"","id":0,"f":0,"s":"0","b":"false","Default":null,"bytes":null,"arr":[0,0],"map":null,"ptr":null,"iface":null,"time":"0001-01-01T00:00:00Z","value":null,"custom":{"custom":[1,2]}}`)
+
+	// The result must be independent of the copied *Value.
+	r, err := a.NewFromStruct(map[string]*Value{"v": v})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	v.Set("x", MustParse(`2`))
+	if s := r.String(); s != `{"v":{"x":[1]}}` {
+		t.Fatalf("unexpected result after modifying the source value: %s", s)
+	}
+}
+
+func TestArenaNewFromStructEncodingJSON(t *testing.T) {
+	// The result must match encoding/json output.
+	var a Arena
+	f := func(x interface{}) {
+		t.Helper()
+		v, err := a.NewFromStruct(x)
+		if err != nil {
+			t.Fatalf("unexpected error for %#v: %s", x, err)
+		}
+		b, err := json.Marshal(x)
+		if err != nil {
+			t.Fatalf("cannot marshal %#v: %s", x, err)
+		}
+		if result := v.String(); result != string(b) {
+			t.Fatalf("unexpected result for %#v\ngot\n%s\nwant\n%s", x, result, b)
+		}
+	}
+
+	x := testOrder{
+		X:               5,
+		testInner:       testInner{A: 1, B: 2, C: 3},
+		testInnerOther:  testInnerOther{B: 4, C: 6},
+		testEmbeddedPtr: &testEmbeddedPtr{P: "p"},
+		Y:               7,
+		C:               8,
+		J:               testPtrJSONMarshaler{N: 9},
+		T:               testPtrTextMarshaler{N: 10},
+		S:               []testPtrJSONMarshaler{{N: 11}},
+		M:               map[string]testPtrJSONMarshaler{"k": {N: 12}},
+	}
+	f(x)
+	f(&x)
+	f([]testOrder{x})
+	f(map[string]testOrder{"a": x})
+	f(struct {
+		testInner
+		B string
+	}{testInner: testInner{A: 1, B: 2, C: 3}, B: "outer"})
+	f(testPtrJSONMarshaler{N: 1})
+	f(&testPtrJSONMarshaler{N: 2})
+	f([]testPtrTextMarshaler{{N: 3}})
+	f([1]testPtrTextMarshaler{{N: 4}})
+
+	// Embedded fields precede the outer fields declared after them.
+	v, err := a.NewFromStruct(&x)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sExpected := `{"x":5,"a":1,"C":6,"P":"p","y":7,"c":8,"J":{"ptr":9},"T":"text-10","S":[{"ptr":11}],"M":{"k":{"N":12}}}`
+	if s := v.String(); s != sExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+}
+
+func TestArenaNewFromStructError(t *testing.T) {
+	var a Arena
+	f := func(x interface{}) {
+		t.Helper()
+		v, err := a.NewFromStruct(x)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %#v; got %s", x, v)
+		}
+	}
+
+	f(make(chan int))
+	f(func() {})
+	f(math.NaN())
+	f([]float64{math.Inf(1)})
+	f(map[float64]int{1: 2})
+	f(struct{ F func() }{})
+	f(testBadJSONMarshaler{})
+
+	type node struct {
+		Next *node
+	}
+	cycle := &node{}
+	cycle.Next = cycle
+	f(cycle)
+}
@@ -0,0 +1,263 @@
+package fastjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetMany returns values for the given keys paths.
+//
+// The i-th item in the returned slice contains the value for paths[i]
+// or nil if the path doesn't exist. Paths are resolved during a single walk
+// of v, so GetMany is faster than independent Get calls for many paths
+// sharing common prefixes. Array indexes may be represented as decimal
+// numbers in keys.
+//
+// The returned values are valid until Parse is called on the Parser returned v.
+func (v *Value) GetMany(paths ...[]string) []*Value {
+	result := make([]*Value, len(paths))
+	if v == nil {
+		return result
+	}
+	idxs := make([]int, len(paths))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	v.getMany(paths, idxs, 0, result)
+	return result
+}
+
+func (v *Value) getMany(paths [][]string, idxs []int, depth int, result []*Value) {
+	var active []int
+	for _, i := range idxs {
+		if len(paths[i]) == depth {
+			result[i] = v
+		} else {
+			active = append(active, i)
+		}
+	}
+	if len(active) == 0 {
+		return
+	}
+
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		var sub []int
+		for _, kv := range v.o.kvs {
+			sub = sub[:0]
+			for j, i := range active {
+				if i >= 0 && paths[i][depth] == kv.k {
+					sub = append(sub, i)
+					// Only the first entry with the given key is matched like in Get.
+					active[j] = -1
+				}
+			}
+			if len(sub) > 0 {
+				kv.v.getMany(paths, sub, depth+1, result)
+			}
+		}
+	case TypeArray:
+		for _, i := range active {
+			n, err := strconv.Atoi(paths[i][depth])
+			if err != nil || n < 0 || n >= len(v.a) {
+				continue
+			}
+			v.a[n].getMany(paths, []int{i}, depth+1, result)
+		}
+	}
+}
+
+// GetManyRaw returns raw JSON values for the given keys paths in data.
+//
+// The i-th item in the returned slice contains the raw value for paths[i]
+// or nil if the path doesn't exist. Paths are resolved during a single scan
+// of data without building Value tree. The scan stops as soon as all
+// the paths are resolved, so the rest of data isn't validated then.
+// Array indexes may be represented as decimal numbers in keys.
+//
+// The returned values refer to data, so they must not be modified.
+func GetManyRaw(data []byte, paths ...[]string) ([][]byte, error) {
+	rg := rawGetter{
+		paths:      paths,
+		result:     make([][]byte, len(paths)),
+		unresolved: len(paths),
+	}
+	if len(paths) == 0 {
+		return rg.result, nil
+	}
+	idxs := make([]int, len(paths))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	s := skipWS(b2s(data))
+	tail, err := rg.getMany(s, idxs, 0)
+	if err == errRawGetDone {
+		return rg.result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(tail))
+	}
+	if tail = skipWS(tail); len(tail) > 0 {
+		return nil, fmt.Errorf("unexpected tail: %q", startEndString(tail))
+	}
+	return rg.result, nil
+}
+
+// errRawGetDone is returned by rawGetter when all the paths are resolved.
+var errRawGetDone = errors.New("all the paths are resolved")
+
+type rawGetter struct {
+	paths      [][]string
+	result     [][]byte
+	unresolved int
+}
+
+// getMany scans the value at the start of s and resolves paths with
+// the given idxs in it. It returns the tail after the value.
+func (rg *rawGetter) getMany(s string, idxs []int, depth int) (string, error) {
+	var active []int
+	for _, i := range idxs {
+		if len(rg.paths[i]) > depth {
+			active = append(active, i)
+		}
+	}
+
+	var tail string
+	var err error
+	switch {
+	case len(active) == 0 || len(s) == 0:
+		tail, err = validateValue(s)
+	case s[0] == '{':
+		tail, err = rg.getManyObject(s[1:], active, depth)
+	case s[0] == '[':
+		tail, err = rg.getManyArray(s[1:], active, depth)
+	default:
+		tail, err = validateValue(s)
+	}
+	if err != nil {
+		return tail, err
+	}
+
+	if len(active) < len(idxs) {
+		raw := s2b(s[:len(s)-len(tail)])
+		for _, i := range idxs {
+			if len(rg.paths[i]) == depth {
+				rg.result[i] = raw
+				rg.unresolved--
+			}
+		}
+		if rg.unresolved == 0 {
+			return tail, errRawGetDone
+		}
+	}
+	return tail, nil
+}
+
+func (rg *rawGetter) getManyObject(s string, active []int, depth int) (string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return s, fmt.Errorf("missing '}'")
+	}
+	if s[0] == '}' {
+		return s[1:], nil
+	}
+	var sub []int
+	for {
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != '"' {
+			return s, fmt.Errorf(`cannot find opening '"" for object key`)
+		}
+		var key string
+		var err error
+		key, s, err = parseRawKey(s[1:])
+		if err != nil {
+			return s, fmt.Errorf("cannot parse object key: %s", err)
+		}
+		if strings.IndexByte(key, '\\') >= 0 {
+			if _, _, err := validateString(key + `"`); err != nil {
+				return s, fmt.Errorf("cannot parse object key: %s", err)
+			}
+			// Unescape a copy of the key, since data mustn't be modified.
+			b := append([]byte(nil), key...)
+			key = unescapeStringBestEffort(b2s(b))
+		}
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != ':' {
+			return s, fmt.Errorf("missing ':' after object key")
+		}
+		s = skipWS(s[1:])
+
+		sub = sub[:0]
+		for j, i := range active {
+			if i >= 0 && rg.paths[i][depth] == key {
+				sub = append(sub, i)
+				// Only the first entry with the given key is matched like in Get.
+				active[j] = -1
+			}
+		}
+		s, err = rg.getMany(s, sub, depth+1)
+		if err != nil {
+			return s, err
+		}
+
+		s = skipWS(s)
+		if len(s) == 0 {
+			return s, fmt.Errorf("unexpected end of object")
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == '}' {
+			return s[1:], nil
+		}
+		return s, fmt.Errorf("missing ',' after object value")
+	}
+}
+
+func (rg *rawGetter) getManyArray(s string, active []int, depth int) (string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return s, fmt.Errorf("missing ']'")
+	}
+	if s[0] == ']' {
+		return s[1:], nil
+	}
+	var sub []int
+	for n := 0; ; n++ {
+		sub = sub[:0]
+		for _, i := range active {
+			if rawArrayIndexEqual(rg.paths[i][depth], n) {
+				sub = append(sub, i)
+			}
+		}
+		var err error
+		s = skipWS(s)
+		s, err = rg.getMany(s, sub, depth+1)
+		if err != nil {
+			return s, err
+		}
+
+		s = skipWS(s)
+		if len(s) == 0 {
+			return s, fmt.Errorf("unexpected end of array")
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == ']' {
+			return s[1:], nil
+		}
+		return s, fmt.Errorf("missing ',' after array value")
+	}
+}
+
+// rawArrayIndexEqual returns true if key refers to the array index n.
+func rawArrayIndexEqual(key string, n int) bool {
+	m, err := strconv.Atoi(key)
+	return err == nil && m == n
+}
@@ -0,0 +1,84 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueGetMany(t *testing.T) {
+	s := `{"a":{"b":1,"c":[2,{"d":"x"}]},"e":null,"a\nb":3,"e":4}`
+	paths := [][]string{
+		{"a", "b"},
+		{"a", "c", "1", "d"},
+		{"a", "c", "5"},
+		{"a", "b", "x"},
+		{"e"},
+		{"a\nb"},
+		{},
+		{"missing"},
+		{"a", "c"},
+		{"a", "b"},
+	}
+	resultExpected := []string{`1`, `"x"`, ``, ``, `null`, `3`, s, ``, `[2,{"d":"x"}]`, `1`}
+
+	v := MustParse(s)
+	vs := v.GetMany(paths...)
+	if len(vs) != len(paths) {
+		t.Fatalf("unexpected number of values; got %d; want %d", len(vs), len(paths))
+	}
+	for i, vv := range vs {
+		var result string
+		if vv != nil {
+			result = vv.String()
+		}
+		if result != resultExpected[i] {
+			t.Fatalf("unexpected value for %q; got %q; want %q", paths[i], result, resultExpected[i])
+		}
+		if vv != v.Get(paths[i]...) {
+			t.Fatalf("GetMany result for %q differs from Get result", paths[i])
+		}
+	}
+
+	raws, err := GetManyRaw([]byte(s), paths...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, raw := range raws {
+		if string(raw) != resultExpected[i] {
+			t.Fatalf("unexpected raw value for %q; got %q; want %q", paths[i], raw, resultExpected[i])
+		}
+		if (raw == nil) != (resultExpected[i] == "") {
+			t.Fatalf("unexpected nil raw value for %q", paths[i])
+		}
+	}
+
+	var vNil *Value
+	if vs := vNil.GetMany([]string{"a"}); len(vs) != 1 || vs[0] != nil {
+		t.Fatalf("unexpected values for nil value: %v", vs)
+	}
+}
+
+func TestGetManyRaw(t *testing.T) {
+	// The scan must stop as soon as all the paths are resolved.
+	raws, err := GetManyRaw([]byte(` {"a":[1, 2 ,{"b" : "c"}],"x":"y"} [invalid tail`), []string{"a", "1"}, []string{"a", "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(raws[0]) != `2` || string(raws[1]) != `{"b" : "c"}` {
+		t.Fatalf("unexpected raw values: %q", raws)
+	}
+
+	// Invalid JSON must be detected until all the paths are resolved.
+	for _, s := range []string{``, `{`, `{"a":1,}`, `[1 2]`, `{"a" 1}`, `{"x":1} 2`, `{"a":1,"b":[}`} {
+		if _, err := GetManyRaw([]byte(s), []string{"missing"}); err == nil {
+			t.Fatalf("expecting non-nil error for %q", s)
+		}
+	}
+
+	raws, err = GetManyRaw([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(raws) != 0 {
+		t.Fatalf("unexpected raw values: %q", raws)
+	}
+}
@@ -0,0 +1,215 @@
+package fastjson
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// GetGlob returns values for keys matching the given shell-style pattern
+// in the order of keys in o.
+//
+// The pattern syntax:
+//
+//   - '*' matches any sequence of chars including the empty sequence.
+//   - '?' matches any single char.
+//   - '[abc]', '[a-z]' and '[!a-z]' match a single char from the class.
+//   - '\c' matches char c literally.
+//
+// Unlike path.Match, '*' and '?' match '/' too, so "app.io/*"
+// matches "app.io/name". Malformed patterns match nothing.
+//
+// The returned values are valid until Parse is called on the Parser returned o.
+func (o *Object) GetGlob(pattern string) []*Value {
+	var vs []*Value
+	if o == nil {
+		return vs
+	}
+	o.unescapeKeys()
+	for _, kv := range o.kvs {
+		if matchGlob(pattern, kv.k) {
+			vs = append(vs, kv.v)
+		}
+	}
+	return vs
+}
+
+// GetAllGlob returns all the values matching the given path of shell-style
+// key patterns.
+//
+// Every key in keys is matched against object keys with the syntax described
+// at Object.GetGlob. Array indexes are matched as decimal numbers,
+// so "*" matches all the array items. For example,
+// GetAllGlob("headers", "x-*") returns values for all the "x-" prefixed
+// keys in the "headers" object.
+//
+// Values are returned in the order of the matched keys.
+//
+// The returned values are valid until Parse is called on the Parser returned v.
+func (v *Value) GetAllGlob(keys ...string) []*Value {
+	var vs []*Value
+	if v == nil {
+		return vs
+	}
+	v.getAllGlob(keys, func(v *Value) {
+		vs = append(vs, v)
+	})
+	return vs
+}
+
+func (v *Value) getAllGlob(keys []string, f func(v *Value)) {
+	if len(keys) == 0 {
+		f(v)
+		return
+	}
+	pattern := keys[0]
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for _, kv := range v.o.kvs {
+			if matchGlob(pattern, kv.k) {
+				kv.v.getAllGlob(keys[1:], f)
+			}
+		}
+	case TypeArray:
+		var buf [20]byte
+		for i, vv := range v.a {
+			if matchGlob(pattern, b2s(strconv.AppendInt(buf[:0], int64(i), 10))) {
+				vv.getAllGlob(keys[1:], f)
+			}
+		}
+	}
+}
+
+// matchGlob returns true if s matches the given shell-style pattern.
+func matchGlob(pattern, s string) bool {
+	// The last '*' position for backtracking.
+	starP, starS := -1, -1
+	p, i := 0, 0
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				starP, starS = p, i
+				p++
+				continue
+			case '?':
+				_, size := utf8.DecodeRuneInString(s[i:])
+				p++
+				i += size
+				continue
+			case '[':
+				r, size := utf8.DecodeRuneInString(s[i:])
+				n, ok := matchGlobClass(pattern[p+1:], r)
+				if n < 0 {
+					// Malformed class.
+					return false
+				}
+				if ok {
+					p += n + 1
+					i += size
+					continue
+				}
+			case '\\':
+				if p+1 == len(pattern) {
+					return false
+				}
+				if pattern[p+1] == s[i] {
+					p += 2
+					i++
+					continue
+				}
+			default:
+				if pattern[p] == s[i] {
+					p++
+					i++
+					continue
+				}
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		// Let the last '*' consume one more char.
+		_, size := utf8.DecodeRuneInString(s[starS:])
+		starS += size
+		p, i = starP+1, starS
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern) && isValidGlob(pattern)
+}
+
+// matchGlobClass matches r against the char class at the start of class,
+// which follows the opening '['.
+//
+// It returns the class length including the closing ']' and whether r matches
+// the class. The returned length is negative if the class is malformed.
+func matchGlobClass(class string, r rune) (int, bool) {
+	i := 0
+	negate := false
+	if i < len(class) && (class[i] == '!' || class[i] == '^') {
+		negate = true
+		i++
+	}
+	matched := false
+	first := true
+	for {
+		if i >= len(class) {
+			return -1, false
+		}
+		if class[i] == ']' && !first {
+			return i + 1, matched != negate
+		}
+		first = false
+		lo, n := decodeGlobClassChar(class[i:])
+		if n < 0 {
+			return -1, false
+		}
+		i += n
+		hi := lo
+		if i+1 < len(class) && class[i] == '-' && class[i+1] != ']' {
+			hi, n = decodeGlobClassChar(class[i+1:])
+			if n < 0 || hi < lo {
+				return -1, false
+			}
+			i += n + 1
+		}
+		if lo <= r && r <= hi {
+			matched = true
+		}
+	}
+}
+
+func decodeGlobClassChar(s string) (rune, int) {
+	if s[0] == '\\' {
+		if len(s) == 1 {
+			return 0, -1
+		}
+		r, size := utf8.DecodeRuneInString(s[1:])
+		return r, size + 1
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return r, size
+}
+
+// isValidGlob returns false if pattern contains malformed char classes
+// or a trailing backslash.
+func isValidGlob(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return false
+			}
+		case '[':
+			n, _ := matchGlobClass(pattern[i+1:], 0)
+			if n < 0 {
+				return false
+			}
+			i += n
+		}
+	}
+	return true
+}
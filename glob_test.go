@@ -0,0 +1,104 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	f := func(pattern, s string, resultExpected bool) {
+		t.Helper()
+		result := matchGlob(pattern, s)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for matchGlob(%q, %q); got %v; want %v", pattern, s, result, resultExpected)
+		}
+	}
+
+	f("", "", true)
+	f("", "a", false)
+	f("*", "", true)
+	f("*", "app.io/name", true)
+	f("x-*", "x-request-id", true)
+	f("x-*", "X-request-id", false)
+	f("*-id", "x-request-id", true)
+	f("*-id", "x-request-ids", false)
+	f("a*b*c", "aXXbYYbc", true)
+	f("a*b*c", "aXXbYYbd", false)
+	f("a**c", "abc", true)
+	f("?", "я", true)
+	f("??", "я", false)
+	f("a?c", "abc", true)
+	f("[abc]", "b", true)
+	f("[abc]", "d", false)
+	f("[a-c]x", "bx", true)
+	f("[!a-c]x", "bx", false)
+	f("[^a-c]x", "dx", true)
+	f("[]]", "]", true)
+	f("[а-я]", "ж", true)
+	f(`\*`, "*", true)
+	f(`\*`, "a", false)
+	f(`[\]]`, "]", true)
+	f("foo", "foo", true)
+	f("foo", "fo", false)
+
+	// Malformed patterns
+	f("[a", "a", false)
+	f("[z-a]", "b", false)
+	f(`a\`, "a", false)
+	f("*[", "abc", false)
+}
+
+func TestObjectGetGlob(t *testing.T) {
+	v := MustParse(`{"x-a":1,"y":2,"x-b":3,"x":4}`)
+	o := v.GetObject()
+	f := func(pattern, resultExpected string) {
+		t.Helper()
+		var a []string
+		for _, vv := range o.GetGlob(pattern) {
+			a = append(a, vv.String())
+		}
+		result := strings.Join(a, ",")
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q; got %q; want %q", pattern, result, resultExpected)
+		}
+	}
+
+	f("x-*", "1,3")
+	f("x*", "1,3,4")
+	f("?", "2,4")
+	f("z*", "")
+	f("[", "")
+
+	var oNil *Object
+	if vs := oNil.GetGlob("*"); len(vs) != 0 {
+		t.Fatalf("unexpected values for nil object: %d", len(vs))
+	}
+}
+
+func TestValueGetAllGlob(t *testing.T) {
+	f := func(s string, keys []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		var a []string
+		for _, vv := range v.GetAllGlob(keys...) {
+			a = append(a, vv.String())
+		}
+		result := strings.Join(a, ",")
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q over %s; got %q; want %q", keys, s, result, resultExpected)
+		}
+	}
+
+	f(`{"headers":{"x-a":"1","accept":"2","x-b":"3"}}`, []string{"headers", "x-*"}, `"1","3"`)
+	f(`{"headers":{"x-a":"1"}}`, []string{"head*", "*"}, `"1"`)
+	f(`{"a":[{"n":1},{"n":2},{"m":3}]}`, []string{"a", "*", "n"}, `1,2`)
+	f(`{"a":[10,11,12,13,14,15,16,17,18,19,20]}`, []string{"a", "1?"}, `20`)
+	f(`{"a":1}`, nil, `{"a":1}`)
+	f(`{"a":1}`, []string{"a", "*"}, ``)
+	f(`{"a":1}`, []string{"b"}, ``)
+
+	var vNil *Value
+	if vs := vNil.GetAllGlob("*"); len(vs) != 0 {
+		t.Fatalf("unexpected values for nil value: %d", len(vs))
+	}
+}
@@ -0,0 +1,115 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ParseRequestBody reads and parses JSON body of r.
+//
+// An error is returned if the body exceeds maxSize bytes. There is no limit
+// if maxSize is zero or negative. The body is read into a pooled buffer
+// and is parsed with a pooled Parser.
+//
+// The returned value is valid until the returned release func is called.
+// The release func must be called when the value is no longer needed, so the
+// Parser is returned to the pool. It is safe calling the release func
+// multiple times. A no-op release func is returned on error, so it may be
+// deferred unconditionally.
+func ParseRequestBody(r *http.Request, maxSize int64) (*Value, func(), error) {
+	if maxSize > 0 && r.ContentLength > maxSize {
+		return nil, releaseNoop, fmt.Errorf("request body size %d exceeds maxSize=%d bytes", r.ContentLength, maxSize)
+	}
+	bb := getRequestBodyBuffer()
+	defer putRequestBodyBuffer(bb)
+	if r.Body != nil {
+		if err := bb.readFrom(r.Body, r.ContentLength, maxSize); err != nil {
+			return nil, releaseNoop, err
+		}
+	}
+
+	p := requestBodyParserPool.Get()
+	v, err := p.ParseBytes(bb.b)
+	if err != nil {
+		requestBodyParserPool.Put(p)
+		return nil, releaseNoop, fmt.Errorf("cannot parse request body: %s", err)
+	}
+	// The Parser holds a copy of the body, so the buffer may be reused
+	// right after parsing.
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			requestBodyParserPool.Put(p)
+		})
+	}
+	return v, release, nil
+}
+
+func releaseNoop() {}
+
+var requestBodyParserPool = ParserPool{
+	MaxRetainedSize: maxPooledRequestBodyBufferSize,
+}
+
+type requestBodyBuffer struct {
+	b []byte
+}
+
+// readFrom reads r with the given contentLength into bb.
+//
+// An error is returned if r contains more than maxSize bytes.
+func (bb *requestBodyBuffer) readFrom(r io.Reader, contentLength, maxSize int64) error {
+	if maxSize > 0 {
+		// Read an additional byte in order to detect too big bodies.
+		r = io.LimitReader(r, maxSize+1)
+	}
+	b := bb.b[:0]
+	// Pre-allocate the buffer only for the verified content length, since
+	// the client may send arbitrary Content-Length.
+	if maxSize > 0 && contentLength > 0 && int64(cap(b)) < contentLength+1 {
+		b = make([]byte, 0, contentLength+1)
+	}
+	for {
+		if len(b) == cap(b) {
+			b = append(b, 0)[:len(b)]
+		}
+		n, err := r.Read(b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if maxSize > 0 && int64(len(b)) > maxSize {
+			bb.b = b
+			return fmt.Errorf("request body exceeds maxSize=%d bytes", maxSize)
+		}
+		if err == io.EOF {
+			bb.b = b
+			return nil
+		}
+		if err != nil {
+			bb.b = b
+			return fmt.Errorf("cannot read request body: %s", err)
+		}
+	}
+}
+
+// maxPooledRequestBodyBufferSize is the maximum capacity of buffers
+// returned to requestBodyBufferPool, so occasional huge bodies don't pin
+// huge buffers in the pool.
+const maxPooledRequestBodyBufferSize = 1 << 20
+
+var requestBodyBufferPool sync.Pool
+
+func getRequestBodyBuffer() *requestBodyBuffer {
+	v := requestBodyBufferPool.Get()
+	if v == nil {
+		return &requestBodyBuffer{}
+	}
+	return v.(*requestBodyBuffer)
+}
+
+func putRequestBodyBuffer(bb *requestBodyBuffer) {
+	if cap(bb.b) > maxPooledRequestBodyBufferSize {
+		return
+	}
+	requestBodyBufferPool.Put(bb)
+}
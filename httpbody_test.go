@@ -0,0 +1,79 @@
+package fastjson
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestBody(t *testing.T) {
+	f := func(body string, maxSize int64, resultExpected string) {
+		t.Helper()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		v, release, err := ParseRequestBody(r, maxSize)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", body, err)
+		}
+		defer release()
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q\ngot\n%s\nwant\n%s", body, result, resultExpected)
+		}
+	}
+
+	f(`{"a":[1,"x"]}`, 0, `{"a":[1,"x"]}`)
+	f(` {"a":1} `, 9, `{"a":1}`)
+	f(`"`+strings.Repeat("x", 100000)+`"`, -1, `"`+strings.Repeat("x", 100000)+`"`)
+
+	// Unknown content length
+	r := httptest.NewRequest("POST", "/", ioutil.NopCloser(strings.NewReader(`[1,2,3]`)))
+	r.ContentLength = -1
+	v, release, err := ParseRequestBody(r, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The value must remain valid until release, even if other requests are parsed.
+	for i := 0; i < 10; i++ {
+		f(`{"foo":"barbazxxxxxxxxxxxxxxxxxxxxxxxxxx"}`, 0, `{"foo":"barbazxxxxxxxxxxxxxxxxxxxxxxxxxx"}`)
+	}
+	if s := v.String(); s != `[1,2,3]` {
+		t.Fatalf("unexpected value: %s", s)
+	}
+	release()
+	release()
+}
+
+func TestParseRequestBodyError(t *testing.T) {
+	f := func(body string, contentLength, maxSize int64) {
+		t.Helper()
+		r := httptest.NewRequest("POST", "/", ioutil.NopCloser(strings.NewReader(body)))
+		r.ContentLength = contentLength
+		v, release, err := ParseRequestBody(r, maxSize)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %q; got %s", body, v)
+		}
+		release()
+	}
+
+	f(``, 0, 0)
+	f(`{"a":`, -1, 0)
+	f(`[1, 2]`, 6, 5)
+	f(`[1, 2]`, -1, 5)
+	f(`[1, 2]`, 0, 5)
+
+	// Read error
+	r := httptest.NewRequest("POST", "/", ioutil.NopCloser(&failingReader{}))
+	if _, release, err := ParseRequestBody(r, 0); err == nil {
+		t.Fatalf("expecting non-nil error for failing body")
+	} else {
+		release()
+	}
+
+	// Nil body
+	r.Body = nil
+	if _, _, err := ParseRequestBody(r, 0); err == nil {
+		t.Fatalf("expecting non-nil error for nil body")
+	}
+}
@@ -0,0 +1,285 @@
+package fastjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PatchOperation is a single operation of JSON Patch from RFC 6902.
+type PatchOperation struct {
+	// Op is the operation name: add, remove, replace, move, copy or test.
+	Op string
+
+	// Path is JSON Pointer to the operation target.
+	Path []string
+
+	// From is JSON Pointer to the source location for move and copy operations.
+	From []string
+
+	// Value is the operation value for add, replace and test operations.
+	Value *Value
+}
+
+// Patch is JSON Patch from RFC 6902.
+//
+// Patch may be obtained via ParsePatch or NewPatch.
+type Patch []PatchOperation
+
+// ParsePatch parses JSON Patch from s.
+//
+// The returned patch doesn't refer to s.
+func ParsePatch(s string) (Patch, error) {
+	var p Parser
+	v, err := p.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse JSON patch: %s", err)
+	}
+	return NewPatch(v)
+}
+
+// NewPatch returns JSON Patch for the given operations array v.
+//
+// Operation values from v must be unchanged during the returned patch lifetime.
+func NewPatch(v *Value) (Patch, error) {
+	a, err := v.Array()
+	if err != nil {
+		return nil, fmt.Errorf("JSON patch must be an array: %s", err)
+	}
+	patch := make(Patch, 0, len(a))
+	for i, item := range a {
+		op, err := newPatchOperation(item)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse JSON patch operation #%d: %s", i, err)
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+
+func newPatchOperation(v *Value) (PatchOperation, error) {
+	var op PatchOperation
+	o, err := v.Object()
+	if err != nil {
+		return op, fmt.Errorf("operation must be an object: %s", err)
+	}
+	opName, err := patchMember(o, "op")
+	if err != nil {
+		return op, err
+	}
+	op.Op = opName
+	switch op.Op {
+	case "add", "remove", "replace", "move", "copy", "test":
+	default:
+		return op, fmt.Errorf("unknown `op`: %q", op.Op)
+	}
+	if op.Path, err = patchPointer(o, "path"); err != nil {
+		return op, err
+	}
+	if op.Op == "move" || op.Op == "copy" {
+		if op.From, err = patchPointer(o, "from"); err != nil {
+			return op, err
+		}
+	}
+	if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+		op.Value = o.Get("value")
+		if op.Value == nil {
+			return op, fmt.Errorf("missing `value` for %q operation", op.Op)
+		}
+	}
+	return op, nil
+}
+
+func patchMember(o *Object, member string) (string, error) {
+	v := o.Get(member)
+	if v == nil {
+		return "", fmt.Errorf("missing `%s`", member)
+	}
+	sb, err := v.StringBytes()
+	if err != nil {
+		return "", fmt.Errorf("cannot obtain `%s`: %s", member, err)
+	}
+	return string(sb), nil
+}
+
+func patchPointer(o *Object, member string) ([]string, error) {
+	s, err := patchMember(o, member)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ParsePointer(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `%s`: %s", member, err)
+	}
+	return keys, nil
+}
+
+// Apply applies the patch to target and returns the result.
+//
+// target is modified in place, while the returned value may differ from
+// target only if the patch replaces the whole document. The target
+// may be partially modified if an error is returned, so apply the patch
+// to a copy of the target if all-or-nothing semantics is needed.
+//
+// Values from the patch must be unchanged during the result lifetime.
+func (patch Patch) Apply(target *Value) (*Value, error) {
+	var err error
+	for i := range patch {
+		op := &patch[i]
+		target, err = op.apply(target)
+		if err != nil {
+			return target, fmt.Errorf("cannot apply JSON patch operation #%d (%q at %q): %s", i, op.Op, FormatPointer(op.Path...), err)
+		}
+	}
+	return target, nil
+}
+
+// ApplyPatch applies JSON Patch from RFC 6902 to target and returns the result.
+//
+// See Patch.Apply for details.
+func ApplyPatch(target, patch *Value) (*Value, error) {
+	p, err := NewPatch(patch)
+	if err != nil {
+		return target, err
+	}
+	return p.Apply(target)
+}
+
+func (op *PatchOperation) apply(root *Value) (*Value, error) {
+	switch op.Op {
+	case "add":
+		return patchAdd(root, op.Path, op.Value)
+	case "remove":
+		if len(op.Path) == 0 {
+			return root, fmt.Errorf("cannot remove the whole document")
+		}
+		_, err := patchRemove(root, op.Path)
+		return root, err
+	case "replace":
+		if _, err := patchRemove(root, op.Path); err != nil {
+			return root, err
+		}
+		return patchAdd(root, op.Path, op.Value)
+	case "move":
+		if isPathPrefix(op.From, op.Path) && len(op.From) < len(op.Path) {
+			return root, fmt.Errorf("cannot move %q into its child", FormatPointer(op.From...))
+		}
+		v, err := patchRemove(root, op.From)
+		if err != nil {
+			return root, err
+		}
+		return patchAdd(root, op.Path, v)
+	case "copy":
+		v, err := patchLookup(root, op.From)
+		if err != nil {
+			return root, err
+		}
+		var a Arena
+		return patchAdd(root, op.Path, v.CopyTo(&a))
+	case "test":
+		v, err := patchLookup(root, op.Path)
+		if err != nil {
+			return root, err
+		}
+		if !equalValues(v, op.Value, decimalNumbersEqual) {
+			return root, fmt.Errorf("test failed: got %s; want %s", v, op.Value)
+		}
+		return root, nil
+	default:
+		return root, fmt.Errorf("unknown `op`: %q", op.Op)
+	}
+}
+
+func isPathPrefix(prefix, keys []string) bool {
+	if len(prefix) > len(keys) {
+		return false
+	}
+	for i, key := range prefix {
+		if keys[i] != key {
+			return false
+		}
+	}
+	return true
+}
+
+// patchLookup returns the value at the given keys in root.
+func patchLookup(root *Value, keys []string) (*Value, error) {
+	v := root
+	for i, key := range keys {
+		switch v.Type() {
+		case TypeObject:
+			v = v.o.Get(key)
+		case TypeArray:
+			n, err := patchArrayIndex(key, len(v.a))
+			if err != nil {
+				return nil, err
+			}
+			v = v.a[n]
+		default:
+			v = nil
+		}
+		if v == nil {
+			return nil, fmt.Errorf("missing value at %q", FormatPointer(keys[:i+1]...))
+		}
+	}
+	return v, nil
+}
+
+func patchAdd(root *Value, keys []string, value *Value) (*Value, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+	parent, err := patchLookup(root, keys[:len(keys)-1])
+	if err != nil {
+		return root, err
+	}
+	key := keys[len(keys)-1]
+	switch parent.Type() {
+	case TypeObject:
+		parent.o.Set(key, value)
+	case TypeArray:
+		if key == "-" {
+			parent.Append(value)
+			return root, nil
+		}
+		n, err := patchArrayIndex(key, len(parent.a)+1)
+		if err != nil {
+			return root, err
+		}
+		parent.InsertAt(n, value)
+	default:
+		return root, fmt.Errorf("cannot add a value to %s at %q", parent.Type(), FormatPointer(keys[:len(keys)-1]...))
+	}
+	return root, nil
+}
+
+// patchRemove removes the value at the given keys from root and returns it.
+func patchRemove(root *Value, keys []string) (*Value, error) {
+	if len(keys) == 0 {
+		return root, nil
+	}
+	v, err := patchLookup(root, keys)
+	if err != nil {
+		return nil, err
+	}
+	parent, _ := patchLookup(root, keys[:len(keys)-1])
+	key := keys[len(keys)-1]
+	if parent.Type() == TypeObject {
+		parent.o.Del(key)
+	} else {
+		n, _ := patchArrayIndex(key, len(parent.a))
+		parent.DelRange(n, n+1)
+	}
+	return v, nil
+}
+
+// patchArrayIndex parses array index from key. The index must be smaller than n.
+func patchArrayIndex(key string, n int) (int, error) {
+	if !isArrayIndex(key) {
+		return 0, fmt.Errorf("invalid array index %q", key)
+	}
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx >= n {
+		return 0, fmt.Errorf("array index %q is out of range", key)
+	}
+	return idx, nil
+}
@@ -0,0 +1,114 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestPatchApplySuccess(t *testing.T) {
+	f := func(doc, patch, resultExpected string) {
+		t.Helper()
+		p, err := ParsePatch(patch)
+		if err != nil {
+			t.Fatalf("cannot parse patch %s: %s", patch, err)
+		}
+		result, err := p.Apply(MustParse(doc))
+		if err != nil {
+			t.Fatalf("cannot apply patch %s to %s: %s", patch, doc, err)
+		}
+		if s := result.String(); s != resultExpected {
+			t.Fatalf("unexpected result after applying patch %s to %s\ngot\n%s\nwant\n%s", patch, doc, s, resultExpected)
+		}
+	}
+
+	// Test cases from RFC 6902 appendix A.
+	f(`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`)
+	f(`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`)
+	f(`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`)
+	f(`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`)
+	f(`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"foo":"bar","baz":"boo"}`)
+	f(`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+		`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`)
+	f(`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`)
+	f(`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`)
+	f(`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`)
+	f(`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"foo":"bar","baz":"qux"}`)
+	f(`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`)
+	f(`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"qux"}]`, `{"baz":"qux"}`)
+	f(`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`)
+
+	// Copy must produce independent values.
+	f(`{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/d","value":2}]`, `{"a":{"b":1},"c":{"b":1,"d":2}}`)
+
+	// Whole document operations.
+	f(`{"a":1}`, `[{"op":"replace","path":"","value":[1,2]}]`, `[1,2]`)
+	f(`{"a":1}`, `[{"op":"add","path":"","value":"x"}]`, `"x"`)
+	f(`{"a":1}`, `[{"op":"test","path":"","value":{"a":1.0}}]`, `{"a":1}`)
+	f(`{"a":9007199254740993}`, `[{"op":"test","path":"/a","value":9.007199254740993e15}]`, `{"a":9007199254740993}`)
+	f(`[1,2]`, `[{"op":"add","path":"/2","value":3},{"op":"remove","path":"/0"}]`, `[2,3]`)
+	f(`{}`, `[]`, `{}`)
+}
+
+func TestPatchApplyFailure(t *testing.T) {
+	f := func(doc, patch string) {
+		t.Helper()
+		p, err := ParsePatch(patch)
+		if err != nil {
+			t.Fatalf("cannot parse patch %s: %s", patch, err)
+		}
+		if _, err := p.Apply(MustParse(doc)); err == nil {
+			t.Fatalf("expecting non-nil error when applying patch %s to %s", patch, doc)
+		}
+	}
+
+	// Test cases from RFC 6902 appendix A.
+	f(`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`)
+	f(`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":"10"}]`)
+	f(`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`)
+	f(`{"a":9007199254740993}`, `[{"op":"test","path":"/a","value":9007199254740992}]`)
+	f(`{"a":0.1}`, `[{"op":"test","path":"/a","value":0.10000000000000001}]`)
+
+	f(`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`)
+	f(`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`)
+	f(`{"foo":"bar"}`, `[{"op":"remove","path":""}]`)
+	f(`{"a":[1]}`, `[{"op":"add","path":"/a/2","value":1}]`)
+	f(`{"a":[1]}`, `[{"op":"add","path":"/a/01","value":1}]`)
+	f(`{"a":[1]}`, `[{"op":"remove","path":"/a/1"}]`)
+	f(`{"a":[1]}`, `[{"op":"remove","path":"/a/-1"}]`)
+	f(`{"a":"x"}`, `[{"op":"add","path":"/a/b","value":1}]`)
+	f(`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`)
+	f(`{"a":1}`, `[{"op":"copy","from":"/x","path":"/b"}]`)
+}
+
+func TestParsePatchFailure(t *testing.T) {
+	f := func(patch string) {
+		t.Helper()
+		_, err := ParsePatch(patch)
+		if err == nil {
+			t.Fatalf("expecting non-nil error when parsing patch %s", patch)
+		}
+	}
+
+	f(``)
+	f(`{}`)
+	f(`[1]`)
+	f(`[{}]`)
+	f(`[{"op":"foo","path":""}]`)
+	f(`[{"op":"add","path":"/a"}]`)
+	f(`[{"op":"add","path":"a","value":1}]`)
+	f(`[{"op":"remove"}]`)
+	f(`[{"op":"move","path":"/a"}]`)
+	f(`[{"op":"copy","path":"/a","from":1}]`)
+}
+
+func TestApplyPatch(t *testing.T) {
+	v, err := ApplyPatch(MustParse(`{"a":1}`), MustParse(`[{"op":"add","path":"/b","value":2}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := v.String(); s != `{"a":1,"b":2}` {
+		t.Fatalf("unexpected result: %s", s)
+	}
+	if _, err := ApplyPatch(MustParse(`{}`), MustParse(`{}`)); err == nil {
+		t.Fatalf("expecting non-nil error for invalid patch")
+	}
+}
@@ -0,0 +1,185 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// BoolLenient returns bool for the v.
+//
+// Unlike Bool, it also accepts "true", "false", "1" and "0" strings
+// and 1 and 0 numbers, which are frequently used by real-world APIs
+// for representing booleans.
+//
+// Use GetBoolLenient if you don't need error handling.
+func (v *Value) BoolLenient() (bool, error) {
+	switch v.Type() {
+	case TypeTrue:
+		return true, nil
+	case TypeFalse:
+		return false, nil
+	case TypeString:
+		switch {
+		case v.s == "1" || strings.EqualFold(v.s, "true"):
+			return true, nil
+		case v.s == "0" || strings.EqualFold(v.s, "false"):
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot parse bool from string %q", v.s)
+	case TypeNumber:
+		switch v.s {
+		case "1":
+			return true, nil
+		case "0":
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot parse bool from number %s", v.s)
+	default:
+		return false, fmt.Errorf("value doesn't contain bool; it contains %s", v.Type())
+	}
+}
+
+// GetBoolLenient returns bool value by the given keys path.
+//
+// See BoolLenient for the list of supported bool representations.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// false is returned for non-existing keys path or for invalid value.
+func (v *Value) GetBoolLenient(keys ...string) bool {
+	v = v.Get(keys...)
+	if v == nil {
+		return false
+	}
+	b, err := v.BoolLenient()
+	if err != nil {
+		return false
+	}
+	return b
+}
+
+// lenientNumber returns number literal for the v.
+//
+// Strings are returned as is, so the caller may try parsing them as numbers.
+func (v *Value) lenientNumber() (string, error) {
+	switch v.Type() {
+	case TypeNumber, TypeString:
+		return v.s, nil
+	default:
+		return "", fmt.Errorf("value doesn't contain number; it contains %s", v.Type())
+	}
+}
+
+// Int64Lenient returns int64 for the v.
+//
+// Unlike Int64, it also accepts strings containing numbers such as "12345".
+//
+// Use GetInt64Lenient if you don't need error handling.
+func (v *Value) Int64Lenient() (int64, error) {
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0, err
+	}
+	return fastfloat.ParseInt64(s)
+}
+
+// Uint64Lenient returns uint64 for the v.
+//
+// Unlike Uint64, it also accepts strings containing numbers such as "12345".
+//
+// Use GetUint64Lenient if you don't need error handling.
+func (v *Value) Uint64Lenient() (uint64, error) {
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0, err
+	}
+	return fastfloat.ParseUint64(s)
+}
+
+// Float64Lenient returns float64 for the v.
+//
+// Unlike Float64, it also accepts strings containing numbers such as "1.5".
+//
+// Use GetFloat64Lenient if you don't need error handling.
+func (v *Value) Float64Lenient() (float64, error) {
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0, err
+	}
+	return fastfloat.Parse(s)
+}
+
+// GetIntLenient returns int value by the given keys path.
+//
+// Unlike GetInt, it also accepts strings containing numbers such as "12345".
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// 0 is returned for non-existing keys path or for invalid value.
+func (v *Value) GetIntLenient(keys ...string) int {
+	n := v.GetInt64Lenient(keys...)
+	nn := int(n)
+	if int64(nn) != n {
+		return 0
+	}
+	return nn
+}
+
+// GetInt64Lenient returns int64 value by the given keys path.
+//
+// Unlike GetInt64, it also accepts strings containing numbers such as "12345".
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// 0 is returned for non-existing keys path or for invalid value.
+func (v *Value) GetInt64Lenient(keys ...string) int64 {
+	v = v.Get(keys...)
+	if v == nil {
+		return 0
+	}
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0
+	}
+	return fastfloat.ParseInt64BestEffort(s)
+}
+
+// GetUint64Lenient returns uint64 value by the given keys path.
+//
+// Unlike GetUint64, it also accepts strings containing numbers such as "12345".
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// 0 is returned for non-existing keys path or for invalid value.
+func (v *Value) GetUint64Lenient(keys ...string) uint64 {
+	v = v.Get(keys...)
+	if v == nil {
+		return 0
+	}
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0
+	}
+	return fastfloat.ParseUint64BestEffort(s)
+}
+
+// GetFloat64Lenient returns float64 value by the given keys path.
+//
+// Unlike GetFloat64, it also accepts strings containing numbers such as "1.5".
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// 0 is returned for non-existing keys path or for invalid value.
+func (v *Value) GetFloat64Lenient(keys ...string) float64 {
+	v = v.Get(keys...)
+	if v == nil {
+		return 0
+	}
+	s, err := v.lenientNumber()
+	if err != nil {
+		return 0
+	}
+	return fastfloat.ParseBestEffort(s)
+}
@@ -0,0 +1,117 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueBoolLenient(t *testing.T) {
+	f := func(s string, resultExpected bool) {
+		t.Helper()
+		v := MustParse(s)
+		result, err := v.BoolLenient()
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", s, err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s; got %v; want %v", s, result, resultExpected)
+		}
+		if result := MustParse(`{"x":` + s + `}`).GetBoolLenient("x"); result != resultExpected {
+			t.Fatalf("unexpected GetBoolLenient result for %s; got %v; want %v", s, result, resultExpected)
+		}
+	}
+	f(`true`, true)
+	f(`false`, false)
+	f(`"true"`, true)
+	f(`"TRUE"`, true)
+	f(`"false"`, false)
+	f(`"1"`, true)
+	f(`"0"`, false)
+	f(`1`, true)
+	f(`0`, false)
+
+	fErr := func(s string) {
+		t.Helper()
+		v := MustParse(s)
+		if _, err := v.BoolLenient(); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+		if MustParse(`{"x":` + s + `}`).GetBoolLenient("x") {
+			t.Fatalf("expecting false from GetBoolLenient for %s", s)
+		}
+	}
+	fErr(`null`)
+	fErr(`"yes"`)
+	fErr(`2`)
+	fErr(`1.0`)
+	fErr(`[]`)
+	fErr(`{}`)
+
+	// The strict Bool must remain unchanged.
+	if _, err := MustParse(`"true"`).Bool(); err == nil {
+		t.Fatalf("expecting non-nil error from Bool for string")
+	}
+}
+
+func TestValueNumberLenient(t *testing.T) {
+	v := MustParse(`{"id":"12345","n":-42,"f":"1.5","neg":"-7","big":"1e3","bad":"12x","b":true,"huge":"99999999999999999999"}`)
+
+	if n := v.GetIntLenient("id"); n != 12345 {
+		t.Fatalf("unexpected int; got %d; want %d", n, 12345)
+	}
+	if n := v.GetInt64Lenient("n"); n != -42 {
+		t.Fatalf("unexpected int64; got %d; want %d", n, -42)
+	}
+	if n := v.GetInt64Lenient("neg"); n != -7 {
+		t.Fatalf("unexpected int64; got %d; want %d", n, -7)
+	}
+	if n := v.GetUint64Lenient("id"); n != 12345 {
+		t.Fatalf("unexpected uint64; got %d; want %d", n, 12345)
+	}
+	if f := v.GetFloat64Lenient("f"); f != 1.5 {
+		t.Fatalf("unexpected float64; got %v; want %v", f, 1.5)
+	}
+	if f := v.GetFloat64Lenient("big"); f != 1000 {
+		t.Fatalf("unexpected float64; got %v; want %v", f, 1000)
+	}
+	for _, key := range []string{"bad", "b", "missing"} {
+		if n := v.GetInt64Lenient(key); n != 0 {
+			t.Fatalf("unexpected int64 for key %q; got %d; want 0", key, n)
+		}
+		if n := v.GetIntLenient(key); n != 0 {
+			t.Fatalf("unexpected int for key %q; got %d; want 0", key, n)
+		}
+		if n := v.GetUint64Lenient(key); n != 0 {
+			t.Fatalf("unexpected uint64 for key %q; got %d; want 0", key, n)
+		}
+		if f := v.GetFloat64Lenient(key); f != 0 {
+			t.Fatalf("unexpected float64 for key %q; got %v; want 0", key, f)
+		}
+	}
+
+	n, err := v.Get("id").Int64Lenient()
+	if err != nil || n != 12345 {
+		t.Fatalf("unexpected Int64Lenient result: %d, %v", n, err)
+	}
+	u, err := v.Get("n").Uint64Lenient()
+	if err == nil {
+		t.Fatalf("expecting non-nil error for negative uint64; got %d", u)
+	}
+	f, err := v.Get("f").Float64Lenient()
+	if err != nil || f != 1.5 {
+		t.Fatalf("unexpected Float64Lenient result: %v, %v", f, err)
+	}
+	if _, err := v.Get("bad").Int64Lenient(); err == nil {
+		t.Fatalf("expecting non-nil error for invalid number string")
+	}
+	if _, err := v.Get("huge").Int64Lenient(); err == nil {
+		t.Fatalf("expecting non-nil error for too big number string")
+	}
+	if _, err := v.Get("b").Float64Lenient(); err == nil {
+		t.Fatalf("expecting non-nil error for bool")
+	}
+
+	// Strict getters must remain unchanged.
+	if n := v.GetInt("id"); n != 0 {
+		t.Fatalf("unexpected int from strict getter; got %d; want 0", n)
+	}
+}
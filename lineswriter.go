@@ -0,0 +1,77 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+)
+
+// LinesWriter writes Values as JSON lines ( http://jsonlines.org/ ).
+//
+// Every value is marshaled into a single reusable buffer
+// followed by '\n'. The buffer is written to the underlying writer when
+// its size reaches FlushThreshold. Call Flush after writing the last value.
+//
+// LinesWriter is the counterpart of Scanner, which may read JSON lines.
+//
+// LinesWriter cannot be used from concurrently running goroutines.
+type LinesWriter struct {
+	// Options are used for marshaling Values.
+	//
+	// Write returns an error if pretty printing is enabled in Options
+	// via Prefix or Indent, since it splits values into multiple lines.
+	Options MarshalOptions
+
+	// FlushThreshold is the buffer size in bytes, which triggers writing
+	// the buffer to the underlying writer.
+	//
+	// Every value is written to the underlying writer immediately
+	// if FlushThreshold is zero.
+	FlushThreshold int
+
+	// JSONSeq enables writing JSON text sequences ( https://tools.ietf.org/html/rfc7464 ),
+	// where every value is prefixed with the record separator char 0x1E.
+	//
+	// Such sequences may be read by Scanner after SetJSONSeq(true) call.
+	JSONSeq bool
+
+	w   io.Writer
+	buf []byte
+}
+
+// NewLinesWriter returns new LinesWriter writing to w.
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{
+		w: w,
+	}
+}
+
+// Write writes marshaled v followed by '\n' to lw.
+func (lw *LinesWriter) Write(v *Value) error {
+	if len(lw.Options.Prefix) > 0 || len(lw.Options.Indent) > 0 {
+		return fmt.Errorf("cannot write value: pretty printing via Options.Prefix or Options.Indent splits values into multiple lines")
+	}
+	if lw.JSONSeq {
+		lw.buf = append(lw.buf, recordSeparator)
+	}
+	lw.buf = lw.Options.MarshalTo(lw.buf, v)
+	lw.buf = append(lw.buf, '\n')
+	if len(lw.buf) < lw.FlushThreshold {
+		return nil
+	}
+	return lw.Flush()
+}
+
+// Flush writes the buffered data to the underlying writer.
+func (lw *LinesWriter) Flush() error {
+	if len(lw.buf) == 0 {
+		return nil
+	}
+	_, err := lw.w.Write(lw.buf)
+	lw.buf = lw.buf[:0]
+	return err
+}
+
+// Buffered returns the number of bytes buffered in lw.
+func (lw *LinesWriter) Buffered() int {
+	return len(lw.buf)
+}
@@ -0,0 +1,120 @@
+package fastjson
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLinesWriter(t *testing.T) {
+	var bb bytes.Buffer
+	lw := NewLinesWriter(&bb)
+	if err := lw.Write(MustParse(`{"a": 1}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != "{\"a\":1}\n" {
+		t.Fatalf("unexpected output; got %q; want %q", s, "{\"a\":1}\n")
+	}
+
+	// Lines must be buffered until the threshold is reached.
+	bb.Reset()
+	lw.FlushThreshold = 10
+	if err := lw.Write(MustParse(`[1]`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bb.Len() != 0 || lw.Buffered() != 4 {
+		t.Fatalf("unexpected flush; output %q, buffered %d", bb.String(), lw.Buffered())
+	}
+	if err := lw.Write(MustParse(`"foobar"`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != "[1]\n\"foobar\"\n" || lw.Buffered() != 0 {
+		t.Fatalf("unexpected output after reaching the threshold; got %q, buffered %d", s, lw.Buffered())
+	}
+	if err := lw.Write(MustParse(`null`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := lw.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != "[1]\n\"foobar\"\nnull\n" {
+		t.Fatalf("unexpected output after Flush; got %q", s)
+	}
+
+	// The output must be readable by Scanner.
+	var sc Scanner
+	sc.Init(bb.String())
+	n := 0
+	for sc.Next() {
+		n++
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Fatalf("unexpected number of scanned values; got %d; want 3", n)
+	}
+}
+
+func TestLinesWriterWriteError(t *testing.T) {
+	lw := NewLinesWriter(&failingWriter{})
+	if err := lw.Write(MustParse(`1`)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	lw.FlushThreshold = 100
+	if err := lw.Write(MustParse(`1`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := lw.Flush(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestLinesWriterPrettyPrinting(t *testing.T) {
+	var bb bytes.Buffer
+	lw := NewLinesWriter(&bb)
+	lw.Options.Indent = "  "
+	if err := lw.Write(MustParse(`{"a":[1]}`)); err == nil {
+		t.Fatalf("expecting non-nil error for Indent")
+	}
+	lw.Options.Indent = ""
+	lw.Options.Prefix = "> "
+	if err := lw.Write(MustParse(`{"a":[1]}`)); err == nil {
+		t.Fatalf("expecting non-nil error for Prefix")
+	}
+	if err := lw.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bb.Len() > 0 {
+		t.Fatalf("unexpected output for rejected values: %q", bb.String())
+	}
+}
+
+func TestLinesWriterJSONSeq(t *testing.T) {
+	var bb bytes.Buffer
+	lw := NewLinesWriter(&bb)
+	lw.JSONSeq = true
+	for _, s := range []string{`{"a":1}`, `[2]`, `3`} {
+		if err := lw.Write(MustParse(s)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	s := bb.String()
+	sExpected := "\x1e{\"a\":1}\n\x1e[2]\n\x1e3\n"
+	if s != sExpected {
+		t.Fatalf("unexpected output; got %q; want %q", s, sExpected)
+	}
+
+	var sc Scanner
+	sc.SetJSONSeq(true)
+	sc.Init(s)
+	n := 0
+	for sc.Next() {
+		n++
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Fatalf("unexpected number of scanned values; got %d; want 3", n)
+	}
+}
@@ -0,0 +1,428 @@
+package fastjson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// MarshalOptions contains options for marshaling Values.
+//
+// The zero MarshalOptions marshals Values in the same way as Value.MarshalTo.
+type MarshalOptions struct {
+	// ValidUTF8 enables replacing invalid UTF-8 sequences in strings
+	// and object keys with U+FFFD, so the output is always valid UTF-8.
+	//
+	// Invalid UTF-8 may appear in the output when the parsed JSON contains
+	// raw invalid bytes, since such strings are passed through as is.
+	ValidUTF8 bool
+
+	// SortKeys enables emitting object members in lexicographic key order.
+	//
+	// The order of members in the marshaled objects isn't changed.
+	SortKeys bool
+
+	// ASCII enables escaping all the non-ASCII runes in strings
+	// and object keys as \uXXXX sequences, so the output contains only
+	// ASCII chars. Runes outside the Basic Multilingual Plane are escaped
+	// as UTF-16 surrogate pairs. Invalid UTF-8 sequences are escaped
+	// as \ufffd.
+	ASCII bool
+
+	// NumberFormat controls the output format for non-integer numbers.
+	//
+	// Integer numbers without fraction and exponent are always emitted
+	// as is in order to avoid precision loss for big integers.
+	NumberFormat NumberFormat
+
+	// FloatPrecision is the number of digits after the decimal point
+	// for NumberFormatFixed.
+	FloatPrecision int
+
+	// TrimIntegerFraction enables emitting numbers with integral values
+	// such as 1.0 or 1e2 as integers without fraction and exponent, e.g. 1 or 100.
+	//
+	// Only numbers with magnitude not exceeding 2^53 are trimmed, since bigger
+	// numbers cannot be represented exactly by float64.
+	TrimIntegerFraction bool
+
+	// BigIntsAsStrings enables emitting integer numbers with magnitude
+	// exceeding 2^53 as quoted strings, e.g. "12345678901234567890".
+	//
+	// This protects JavaScript consumers from silent precision loss,
+	// since such numbers cannot be represented exactly by float64.
+	BigIntsAsStrings bool
+
+	// StringHook is called for every string value during marshaling.
+	//
+	// The unescaped string is passed to StringHook, while the returned
+	// string is emitted instead of the original one. Object keys aren't
+	// passed to StringHook. This allows transforming the output
+	// (for example, masking sensitive strings) without modifying Values.
+	StringHook func(s string) string
+
+	// NumberHook is called for every number value during marshaling.
+	//
+	// The original number literal is passed to NumberHook, while
+	// the returned literal is emitted instead of the original one.
+	// The returned literal must be a valid JSON number.
+	NumberHook func(s string) string
+
+	// Prefix and Indent enable pretty printing in the same way
+	// as json.MarshalIndent does.
+	//
+	// Every nested object member and array item is emitted on a new line
+	// starting with Prefix followed by Indent copies according to the nesting
+	// level. Empty objects and arrays are emitted as {} and [].
+	// Pretty printing is disabled if both Prefix and Indent are empty.
+	Prefix string
+	Indent string
+}
+
+// NumberFormat is the output format for numbers.
+type NumberFormat int
+
+const (
+	// NumberFormatPreserve emits numbers as they were in the original JSON.
+	NumberFormatPreserve NumberFormat = iota
+
+	// NumberFormatShortest emits the shortest representation, which
+	// round-trips to the same float64 value, e.g. 1.50E+1 is emitted as 15.
+	NumberFormatShortest
+
+	// NumberFormatFixed emits numbers with MarshalOptions.FloatPrecision digits
+	// after the decimal point and without exponent.
+	NumberFormatFixed
+)
+
+// MarshalTo appends marshaled v to dst according to mo and returns the result.
+//
+// v isn't modified, so it may be marshaled from concurrently running goroutines.
+func (mo *MarshalOptions) MarshalTo(dst []byte, v *Value) []byte {
+	return mo.marshal(dst, v, 0)
+}
+
+func (mo *MarshalOptions) marshal(dst []byte, v *Value, depth int) []byte {
+	switch v.t {
+	case typeRawString:
+		if mo.StringHook != nil {
+			// Pass the unescaped copy to the hook, so v isn't modified.
+			return mo.appendString(dst, mo.StringHook(unescapeStringCopy(v.s)))
+		}
+		return mo.appendRawString(dst, v.s)
+	case TypeObject:
+		return mo.marshalObject(dst, &v.o, depth)
+	case TypeArray:
+		if len(v.a) == 0 {
+			return append(dst, "[]"...)
+		}
+		dst = append(dst, '[')
+		for i, vv := range v.a {
+			dst = mo.appendNewline(dst, depth+1)
+			dst = mo.marshal(dst, vv, depth+1)
+			if i != len(v.a)-1 {
+				dst = append(dst, ',')
+			}
+		}
+		dst = mo.appendNewline(dst, depth)
+		dst = append(dst, ']')
+		return dst
+	case TypeString:
+		if mo.StringHook != nil {
+			return mo.appendString(dst, mo.StringHook(v.s))
+		}
+		return mo.appendString(dst, v.s)
+	case TypeNumber:
+		if mo.NumberHook != nil {
+			return mo.appendNumber(dst, mo.NumberHook(v.s))
+		}
+		return mo.appendNumber(dst, v.s)
+	case TypeTrue:
+		return append(dst, "true"...)
+	case TypeFalse:
+		return append(dst, "false"...)
+	case TypeNull:
+		return append(dst, "null"...)
+	default:
+		panic(fmt.Errorf("BUG: unexpected Value type: %d", v.t))
+	}
+}
+
+// MarshalSortedTo appends marshaled v to dst and returns the result.
+//
+// Unlike MarshalTo, object members are emitted in lexicographic key order,
+// so the output is reproducible regardless of the original keys order.
+// v isn't modified.
+func (v *Value) MarshalSortedTo(dst []byte) []byte {
+	mo := MarshalOptions{
+		SortKeys: true,
+	}
+	return mo.MarshalTo(dst, v)
+}
+
+// MarshalIndentTo appends pretty printed v to dst and returns the result.
+//
+// Every nested object member and array item is emitted on a new line
+// starting with prefix followed by indent copies according to the nesting
+// level in the same way as json.MarshalIndent does.
+func (v *Value) MarshalIndentTo(dst []byte, prefix, indent string) []byte {
+	mo := MarshalOptions{
+		Prefix: prefix,
+		Indent: indent,
+	}
+	return mo.MarshalTo(dst, v)
+}
+
+func (mo *MarshalOptions) marshalObject(dst []byte, o *Object, depth int) []byte {
+	kvs := o.kvs
+	if len(kvs) == 0 {
+		return append(dst, "{}"...)
+	}
+	keysUnescaped := o.keysUnescaped
+	if mo.SortKeys && len(kvs) > 1 {
+		// Sort the copy with unescaped keys, so o isn't modified.
+		kvs = append([]kv(nil), o.kvs...)
+		if !keysUnescaped {
+			for i := range kvs {
+				kvs[i].k = unescapeStringCopy(kvs[i].k)
+			}
+			keysUnescaped = true
+		}
+		sort.SliceStable(kvs, func(i, j int) bool {
+			return kvs[i].k < kvs[j].k
+		})
+	}
+	dst = append(dst, '{')
+	for i, kv := range kvs {
+		dst = mo.appendNewline(dst, depth+1)
+		if keysUnescaped {
+			dst = mo.appendString(dst, kv.k)
+		} else {
+			dst = mo.appendRawString(dst, kv.k)
+		}
+		dst = append(dst, ':')
+		if mo.isIndented() {
+			dst = append(dst, ' ')
+		}
+		dst = mo.marshal(dst, kv.v, depth+1)
+		if i != len(kvs)-1 {
+			dst = append(dst, ',')
+		}
+	}
+	dst = mo.appendNewline(dst, depth)
+	dst = append(dst, '}')
+	return dst
+}
+
+// unescapeStringCopy returns unescaped copy of the raw JSON string s.
+//
+// Unlike unescapeStringBestEffort, s isn't modified.
+func unescapeStringCopy(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	return unescapeStringBestEffort(string(s2b(s)))
+}
+
+func (mo *MarshalOptions) isIndented() bool {
+	return len(mo.Prefix) > 0 || len(mo.Indent) > 0
+}
+
+// appendNewline appends newline followed by the prefix and indentation
+// for the given depth to dst if pretty printing is enabled.
+func (mo *MarshalOptions) appendNewline(dst []byte, depth int) []byte {
+	if !mo.isIndented() {
+		return dst
+	}
+	dst = append(dst, '\n')
+	dst = append(dst, mo.Prefix...)
+	for i := 0; i < depth; i++ {
+		dst = append(dst, mo.Indent...)
+	}
+	return dst
+}
+
+// appendNumber appends number s to dst according to mo.
+func (mo *MarshalOptions) appendNumber(dst []byte, s string) []byte {
+	if mo.BigIntsAsStrings && isBigInt(s) {
+		dst = append(dst, '"')
+		dst = append(dst, s...)
+		return append(dst, '"')
+	}
+	if (mo.NumberFormat == NumberFormatPreserve && !mo.TrimIntegerFraction) || !strings.ContainsAny(s, ".eE") {
+		return append(dst, s...)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		// Leave numbers, which cannot be represented by float64, as is.
+		return append(dst, s...)
+	}
+	if mo.TrimIntegerFraction && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+		return strconv.AppendInt(dst, int64(f), 10)
+	}
+	switch mo.NumberFormat {
+	case NumberFormatShortest:
+		return appendShortestFloat64(dst, f)
+	case NumberFormatFixed:
+		return strconv.AppendFloat(dst, f, 'f', mo.FloatPrecision, 64)
+	default:
+		return append(dst, s...)
+	}
+}
+
+// maxSafeInteger is 2^53 - the maximum magnitude of integers, which may be
+// represented exactly by float64.
+const maxSafeInteger = "9007199254740992"
+
+// isBigInt returns true if s is an integer number with magnitude exceeding 2^53.
+func isBigInt(s string) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	if len(s) < len(maxSafeInteger) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	if len(s) > len(maxSafeInteger) {
+		return true
+	}
+	return s > maxSafeInteger
+}
+
+// appendShortestFloat64 appends the shortest representation of f,
+// which round-trips to f, to dst.
+//
+// The output is deterministic, i.e. it depends only on f.
+func appendShortestFloat64(dst []byte, f float64) []byte {
+	return strconv.AppendFloat(dst, f, 'g', -1, 64)
+}
+
+// appendRawString appends already escaped s to dst.
+func (mo *MarshalOptions) appendRawString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	if mo.ASCII {
+		dst = appendASCII(dst, s, false)
+	} else if mo.ValidUTF8 {
+		dst = appendValidUTF8(dst, s)
+	} else {
+		dst = append(dst, s...)
+	}
+	dst = append(dst, '"')
+	return dst
+}
+
+// appendString appends JSON-escaped s to dst.
+func (mo *MarshalOptions) appendString(dst []byte, s string) []byte {
+	if mo.ASCII {
+		dst = append(dst, '"')
+		dst = appendASCII(dst, s, true)
+		dst = append(dst, '"')
+		return dst
+	}
+	if !mo.ValidUTF8 {
+		return escapeString(dst, s)
+	}
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		ch := s[i]
+		if ch < utf8.RuneSelf {
+			dst = appendEscapedByte(dst, ch)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, "�"...)
+		} else {
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	dst = append(dst, '"')
+	return dst
+}
+
+// appendEscapedByte appends JSON-escaped ASCII char ch to dst.
+func appendEscapedByte(dst []byte, ch byte) []byte {
+	switch ch {
+	case '"':
+		return append(dst, `\"`...)
+	case '\\':
+		return append(dst, `\\`...)
+	case '\n':
+		return append(dst, `\n`...)
+	case '\r':
+		return append(dst, `\r`...)
+	case '\t':
+		return append(dst, `\t`...)
+	case '\b':
+		return append(dst, `\b`...)
+	case '\f':
+		return append(dst, `\f`...)
+	}
+	if ch < 0x20 {
+		return append(dst, '\\', 'u', '0', '0', hexChars[ch>>4], hexChars[ch&0xf])
+	}
+	return append(dst, ch)
+}
+
+const hexChars = "0123456789abcdef"
+
+// appendASCII appends s to dst with non-ASCII runes escaped as \uXXXX.
+//
+// ASCII chars are escaped only if escapeASCII is set.
+func appendASCII(dst []byte, s string, escapeASCII bool) []byte {
+	for i := 0; i < len(s); {
+		ch := s[i]
+		if ch < utf8.RuneSelf {
+			if escapeASCII {
+				dst = appendEscapedByte(dst, ch)
+			} else {
+				dst = append(dst, ch)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			dst = appendEscapedRune(dst, r1)
+			dst = appendEscapedRune(dst, r2)
+		} else {
+			dst = appendEscapedRune(dst, r)
+		}
+		i += size
+	}
+	return dst
+}
+
+// appendEscapedRune appends r from the Basic Multilingual Plane as \uXXXX to dst.
+func appendEscapedRune(dst []byte, r rune) []byte {
+	return append(dst, '\\', 'u', hexChars[(r>>12)&0xf], hexChars[(r>>8)&0xf], hexChars[(r>>4)&0xf], hexChars[r&0xf])
+}
+
+// appendValidUTF8 appends s to dst with invalid UTF-8 sequences replaced by U+FFFD.
+func appendValidUTF8(dst []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		if s[i] < utf8.RuneSelf {
+			dst = append(dst, s[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, "�"...)
+		} else {
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
+}
@@ -0,0 +1,270 @@
+package fastjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMarshalOptionsZero(t *testing.T) {
+	var mo MarshalOptions
+	for _, s := range []string{
+		`null`,
+		`true`,
+		`[1,"foo",{"bar":[false,null],"x\ny":"a\tb"},-1.5e3]`,
+		`{"a":"ሴ\"","b":[]}`,
+	} {
+		v := MustParse(s)
+		result := mo.MarshalTo(nil, v)
+		resultExpected := v.MarshalTo(nil)
+		if string(result) != string(resultExpected) {
+			t.Fatalf("unexpected result for %s; got %s; want %s", s, result, resultExpected)
+		}
+	}
+}
+
+func TestMarshalOptionsValidUTF8(t *testing.T) {
+	mo := &MarshalOptions{
+		ValidUTF8: true,
+	}
+	f := func(v *Value, resultExpected string) {
+		t.Helper()
+		result := mo.MarshalTo(nil, v)
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result; got %q; want %q", result, resultExpected)
+		}
+		if !utf8.Valid(result) {
+			t.Fatalf("invalid UTF-8 in the result %q", result)
+		}
+		if !json.Valid(result) {
+			t.Fatalf("invalid JSON in the result %q", result)
+		}
+	}
+
+	// Raw strings and keys from the parsed JSON.
+	f(MustParse("{\"a\xffb\":\"x\xc0y\\n\"}"), "{\"a�b\":\"x�y\\n\"}")
+	f(MustParse(`"привет"`), `"привет"`)
+
+	// Unescaped strings and keys.
+	v := MustParse("[\"\xfe\\t\",{\"k\\u0001\xff\":1}]")
+	v.GetStringBytes("0")
+	v.Get("1").GetObject().Get("foo")
+	f(v, "[\"�\\t\",{\"k\\u0001�\":1}]")
+}
+
+func TestValueMarshalSortedTo(t *testing.T) {
+	v := MustParse(`{"b":1,"a\n":{"z":[{"y":1,"x":2}],"c":null},"a":true,"":0}`)
+	s := string(v.MarshalSortedTo(nil))
+	sExpected := `{"":0,"a":true,"a\n":{"c":null,"z":[{"x":2,"y":1}]},"b":1}`
+	if s != sExpected {
+		t.Fatalf("unexpected sorted output\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+
+	// The original order must be preserved.
+	s = v.String()
+	sExpected = `{"b":1,"a\n":{"z":[{"y":1,"x":2}],"c":null},"a":true,"":0}`
+	if s != sExpected {
+		t.Fatalf("unexpected original output\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+}
+
+func TestValueMarshalIndentTo(t *testing.T) {
+	f := func(s, prefix, indent string) {
+		t.Helper()
+		v := MustParse(s)
+		result := v.MarshalIndentTo(nil, prefix, indent)
+
+		var bb bytes.Buffer
+		if err := json.Indent(&bb, []byte(v.String()), prefix, indent); err != nil {
+			t.Fatalf("cannot indent %s: %s", s, err)
+		}
+		if string(result) != bb.String() {
+			t.Fatalf("unexpected result for MarshalIndentTo(%q, %q) on %s\ngot\n%s\nwant\n%s", prefix, indent, s, result, bb.String())
+		}
+	}
+
+	for _, s := range []string{
+		`1`,
+		`"foo"`,
+		`{}`,
+		`[]`,
+		`{"a":1}`,
+		`[1,"x",null,true,false]`,
+		`{"a":{"b":[1,{"c":[]},{}],"d":"e\nf"},"g":[[[]]],"h\t":null}`,
+	} {
+		f(s, "", "  ")
+		f(s, "", "\t")
+		f(s, "> ", "  ")
+		f(s, "#", "")
+	}
+
+	v := MustParse(`{"b":1,"a":[2]}`)
+	mo := &MarshalOptions{
+		SortKeys: true,
+		Indent:   " ",
+	}
+	s := string(mo.MarshalTo(nil, v))
+	sExpected := "{\n \"a\": [\n  2\n ],\n \"b\": 1\n}"
+	if s != sExpected {
+		t.Fatalf("unexpected sorted indented output\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+}
+
+func TestMarshalOptionsASCII(t *testing.T) {
+	mo := &MarshalOptions{
+		ASCII: true,
+	}
+	f := func(v *Value, resultExpected string) {
+		t.Helper()
+		result := string(mo.MarshalTo(nil, v))
+		if result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+		if err := Validate(result); err != nil {
+			t.Fatalf("invalid JSON output %s: %s", result, err)
+		}
+	}
+
+	// Raw strings
+	f(MustParse(`"abc"`), `"abc"`)
+	f(MustParse(`{"ключ\n":"значение\t😀"}`), `{"\u043a\u043b\u044e\u0447\n":"\u0437\u043d\u0430\u0447\u0435\u043d\u0438\u0435\t\ud83d\ude00"}`)
+	f(MustParse("[\"\xff\"]"), `["\ufffd"]`)
+
+	// Unescaped strings
+	v := MustParse(`{"ключ\n":["значение\t😀","a\"b"]}`)
+	v.GetObject().Visit(func(k []byte, v *Value) {})
+	v.GetStringBytes("ключ\n", "0")
+	v.GetStringBytes("ключ\n", "1")
+	f(v, `{"\u043a\u043b\u044e\u0447\n":["\u0437\u043d\u0430\u0447\u0435\u043d\u0438\u0435\t\ud83d\ude00","a\"b"]}`)
+
+	// The output must unescape to the original strings.
+	var p Parser
+	vv, err := p.Parse(string(mo.MarshalTo(nil, v)))
+	if err != nil {
+		t.Fatalf("cannot parse ASCII output: %s", err)
+	}
+	if sb := vv.GetStringBytes("ключ\n", "0"); string(sb) != "значение\t😀" {
+		t.Fatalf("unexpected unescaped string; got %q; want %q", sb, "значение\t😀")
+	}
+}
+
+func TestMarshalOptionsNumberFormat(t *testing.T) {
+	f := func(mo *MarshalOptions, s, resultExpected string) {
+		t.Helper()
+		result := string(mo.MarshalTo(nil, MustParse(s)))
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s with %+v\ngot\n%s\nwant\n%s", s, mo, result, resultExpected)
+		}
+	}
+
+	s := `[0,-12,12345678901234567890123,1.0,1.50E+1,-0.000001,2e2,1.25,1e400,123456789012345678.0]`
+
+	f(&MarshalOptions{}, s, s)
+	f(&MarshalOptions{
+		NumberFormat: NumberFormatShortest,
+	}, s, `[0,-12,12345678901234567890123,1,15,-1e-06,200,1.25,1e400,1.2345678901234568e+17]`)
+	f(&MarshalOptions{
+		NumberFormat:   NumberFormatFixed,
+		FloatPrecision: 2,
+	}, s, `[0,-12,12345678901234567890123,1.00,15.00,-0.00,200.00,1.25,1e400,123456789012345680.00]`)
+	f(&MarshalOptions{
+		TrimIntegerFraction: true,
+	}, s, `[0,-12,12345678901234567890123,1,15,-0.000001,200,1.25,1e400,123456789012345678.0]`)
+	f(&MarshalOptions{
+		NumberFormat:        NumberFormatFixed,
+		FloatPrecision:      1,
+		TrimIntegerFraction: true,
+	}, `{"a":2.0,"b":2.25}`, `{"a":2,"b":2.2}`)
+}
+
+func TestMarshalOptionsBigIntsAsStrings(t *testing.T) {
+	mo := &MarshalOptions{
+		BigIntsAsStrings: true,
+	}
+	f := func(s, resultExpected string) {
+		t.Helper()
+		result := string(mo.MarshalTo(nil, MustParse(s)))
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	f(`[0,-1,123456789012345]`, `[0,-1,123456789012345]`)
+	f(`[9007199254740992,-9007199254740992]`, `[9007199254740992,-9007199254740992]`)
+	f(`[9007199254740993,-9007199254740993]`, `["9007199254740993","-9007199254740993"]`)
+	f(`{"id":12345678901234567890}`, `{"id":"12345678901234567890"}`)
+	f(`[1e300,12345678901234567890.5]`, `[1e300,12345678901234567890.5]`)
+
+	var a Arena
+	v := a.NewArray()
+	v.SetArrayItem(0, a.NewNumberString(strconv.FormatInt(math.MaxInt64, 10)))
+	v.SetArrayItem(1, a.NewNumberInt(42))
+	f(v.String(), `["9223372036854775807",42]`)
+}
+
+func TestMarshalOptionsHooks(t *testing.T) {
+	mo := &MarshalOptions{
+		StringHook: func(s string) string {
+			if strings.HasPrefix(s, "secret") {
+				return "***"
+			}
+			return strings.ToUpper(s)
+		},
+		NumberHook: func(s string) string {
+			if s == "0" {
+				return "-1"
+			}
+			return s + "0"
+		},
+	}
+	v := MustParse(`{"a":"foo\nbar","secret":"secret\tvalue","n":[0,1.5,42]}`)
+	result := string(mo.MarshalTo(nil, v))
+	resultExpected := `{"a":"FOO\nBAR","secret":"***","n":[-1,1.50,420]}`
+	if result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	// The second call must work on already unescaped strings.
+	result = string(mo.MarshalTo(nil, v))
+	if result != resultExpected {
+		t.Fatalf("unexpected result on the second call\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	// The original value mustn't be changed.
+	s := v.String()
+	sExpected := `{"a":"foo\nbar","secret":"secret\tvalue","n":[0,1.5,42]}`
+	if s != sExpected {
+		t.Fatalf("unexpected original value\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+}
+
+func TestMarshalOptionsConcurrent(t *testing.T) {
+	// Marshaling mustn't modify v, so it may be marshaled concurrently.
+	v := MustParse(`{"b\n":"x\ty","a":{"d\"":"A","c":[1,"z\n"]}}`)
+	mo := &MarshalOptions{
+		SortKeys:   true,
+		StringHook: strings.ToUpper,
+	}
+	resultExpected := `{"a":{"c":[1,"Z\n"],"d\"":"A"},"b\n":"X\tY"}`
+	ch := make(chan string, 4)
+	for i := 0; i < cap(ch); i++ {
+		go func() {
+			ch <- string(mo.MarshalTo(nil, v))
+		}()
+	}
+	for i := 0; i < cap(ch); i++ {
+		if result := <-ch; result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+	if v.o.keysUnescaped || v.Get("a").o.keysUnescaped {
+		t.Fatalf("object keys mustn't be unescaped by marshaling")
+	}
+	if v.o.kvs[0].v.t != typeRawString {
+		t.Fatalf("strings mustn't be unescaped by marshaling")
+	}
+}
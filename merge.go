@@ -0,0 +1,87 @@
+package fastjson
+
+// MergeStrategy determines how DeepMerge combines arrays.
+type MergeStrategy int
+
+const (
+	// MergeReplaceArrays replaces destination arrays with source arrays.
+	MergeReplaceArrays MergeStrategy = iota
+
+	// MergeConcatArrays appends source array items to destination arrays.
+	MergeConcatArrays
+
+	// MergeArraysByIndex deep merges array items located at the same index.
+	//
+	// Destination arrays are extended if source arrays are longer.
+	MergeArraysByIndex
+)
+
+// Merge sets all the entries from src in o.
+//
+// Entries from src win over already existing entries in o with the same keys.
+// Values from src must be unchanged during o lifetime.
+func (o *Object) Merge(src *Object) {
+	if o == nil || src == nil {
+		return
+	}
+	src.unescapeKeys()
+	for _, kv := range src.kvs {
+		o.Set(kv.k, kv.v)
+	}
+}
+
+// DeepMerge recursively merges src into v.
+//
+// Object entries from src win over entries in v unless both entries
+// are objects, in which case they are merged recursively. Arrays are
+// combined according to the given strategy. Nothing is merged
+// if v and src aren't both objects or both arrays.
+//
+// Values from src must be unchanged during v lifetime.
+func (v *Value) DeepMerge(src *Value, strategy MergeStrategy) {
+	if v == nil || src == nil {
+		return
+	}
+	t := v.Type()
+	if t != src.Type() {
+		return
+	}
+	switch t {
+	case TypeObject:
+		src.o.unescapeKeys()
+		for _, kv := range src.o.kvs {
+			dst := v.o.Get(kv.k)
+			if dst != nil && isMergeable(dst, kv.v, strategy) {
+				dst.DeepMerge(kv.v, strategy)
+				continue
+			}
+			v.o.Set(kv.k, kv.v)
+		}
+	case TypeArray:
+		switch strategy {
+		case MergeConcatArrays:
+			v.a = append(v.a, src.a...)
+		case MergeArraysByIndex:
+			for i, item := range src.a {
+				if i < len(v.a) && isMergeable(v.a[i], item, strategy) {
+					v.a[i].DeepMerge(item, strategy)
+					continue
+				}
+				v.SetArrayItem(i, item)
+			}
+		default:
+			v.a = append(v.a[:0], src.a...)
+		}
+	}
+}
+
+func isMergeable(dst, src *Value, strategy MergeStrategy) bool {
+	t := dst.Type()
+	if t != src.Type() {
+		return false
+	}
+	if t == TypeObject {
+		return true
+	}
+	return t == TypeArray && strategy != MergeReplaceArrays
+}
@@ -0,0 +1,53 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestObjectMerge(t *testing.T) {
+	v := MustParse(`{"a":1,"b":{"x":1}}`)
+	src := MustParse(`{"b":{"y":2},"c":3}`)
+	v.GetObject().Merge(src.GetObject())
+	s := v.String()
+	sExpected := `{"a":1,"b":{"y":2},"c":3}`
+	if s != sExpected {
+		t.Fatalf("unexpected merge result\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+
+	var oNil *Object
+	oNil.Merge(src.GetObject())
+	v.GetObject().Merge(nil)
+}
+
+func TestValueDeepMerge(t *testing.T) {
+	f := func(dst, src string, strategy MergeStrategy, resultExpected string) {
+		t.Helper()
+		v := MustParse(dst)
+		v.DeepMerge(MustParse(src), strategy)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for DeepMerge(%s, %s, %d)\ngot\n%s\nwant\n%s", dst, src, strategy, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1,"b":{"x":1,"y":2}}`, `{"b":{"y":3,"z":4},"c":5}`, MergeReplaceArrays, `{"a":1,"b":{"x":1,"y":3,"z":4},"c":5}`)
+	f(`{"a":{"b":1}}`, `{"a":"foo"}`, MergeReplaceArrays, `{"a":"foo"}`)
+	f(`{"a":"foo"}`, `{"a":{"b":1}}`, MergeReplaceArrays, `{"a":{"b":1}}`)
+	f(`{"a":1}`, `{"a":null}`, MergeReplaceArrays, `{"a":null}`)
+
+	// Arrays
+	f(`{"a":[1,2,3]}`, `{"a":[4]}`, MergeReplaceArrays, `{"a":[4]}`)
+	f(`{"a":[1,2,3]}`, `{"a":[4]}`, MergeConcatArrays, `{"a":[1,2,3,4]}`)
+	f(`{"a":[1,2,3]}`, `{"a":[4]}`, MergeArraysByIndex, `{"a":[4,2,3]}`)
+	f(`{"a":[1]}`, `{"a":[4,5]}`, MergeArraysByIndex, `{"a":[4,5]}`)
+	f(`[{"x":1},[1]]`, `[{"y":2},[2,3]]`, MergeArraysByIndex, `[{"x":1,"y":2},[2,3]]`)
+	f(`[{"x":1},[1]]`, `[{"y":2},[2,3]]`, MergeConcatArrays, `[{"x":1},[1],{"y":2},[2,3]]`)
+	f(`{"a":[[1]]}`, `{"a":[[2]]}`, MergeConcatArrays, `{"a":[[1],[2]]}`)
+
+	// Mismatched root types
+	f(`{"a":1}`, `[1]`, MergeReplaceArrays, `{"a":1}`)
+	f(`1`, `2`, MergeReplaceArrays, `1`)
+
+	var vNil *Value
+	vNil.DeepMerge(MustParse(`{}`), MergeReplaceArrays)
+}
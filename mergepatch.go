@@ -0,0 +1,64 @@
+package fastjson
+
+// ApplyMergePatch applies JSON Merge Patch from RFC 7386 to target
+// and returns the result.
+//
+// Object target is modified in place. Null values in the patch delete
+// the corresponding keys from the target. Non-object patch replaces
+// the target, so it is returned as is.
+//
+// Values from patch must be unchanged during the result lifetime.
+func ApplyMergePatch(target, patch *Value) *Value {
+	if patch == nil || patch.Type() != TypeObject {
+		return patch
+	}
+	if target == nil || target.Type() != TypeObject {
+		target = &Value{
+			t: TypeObject,
+		}
+	}
+	patch.o.unescapeKeys()
+	for _, kv := range patch.o.kvs {
+		if kv.v.Type() == TypeNull {
+			target.o.Del(kv.k)
+			continue
+		}
+		target.o.Set(kv.k, ApplyMergePatch(target.o.Get(kv.k), kv.v))
+	}
+	return target
+}
+
+// CreateMergePatch returns JSON Merge Patch from RFC 7386, which
+// transforms original into modified when applied with ApplyMergePatch.
+//
+// Values from modified must be unchanged during the returned patch lifetime.
+//
+// Merge patch cannot express null values in objects, so null values
+// in modified objects are deleted when the returned patch is applied.
+func CreateMergePatch(original, modified *Value) *Value {
+	if original == nil || modified == nil || original.Type() != TypeObject || modified.Type() != TypeObject {
+		return modified
+	}
+	patch := &Value{
+		t: TypeObject,
+	}
+	original.o.unescapeKeys()
+	for _, kv := range original.o.kvs {
+		if modified.o.Get(kv.k) == nil {
+			patch.o.Set(kv.k, valueNull)
+		}
+	}
+	modified.o.unescapeKeys()
+	for _, kv := range modified.o.kvs {
+		orig := original.o.Get(kv.k)
+		if orig == nil {
+			patch.o.Set(kv.k, kv.v)
+			continue
+		}
+		if equalExact(orig, kv.v) {
+			continue
+		}
+		patch.o.Set(kv.k, CreateMergePatch(orig, kv.v))
+	}
+	return patch
+}
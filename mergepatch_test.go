@@ -0,0 +1,63 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	f := func(target, patch, resultExpected string) {
+		t.Helper()
+		result := ApplyMergePatch(MustParse(target), MustParse(patch)).String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for ApplyMergePatch(%s, %s)\ngot\n%s\nwant\n%s", target, patch, result, resultExpected)
+		}
+	}
+
+	// Test cases from RFC 7386 appendix A.
+	f(`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`)
+	f(`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`)
+	f(`{"a":"b"}`, `{"a":null}`, `{}`)
+	f(`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`)
+	f(`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`)
+	f(`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`)
+	f(`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`)
+	f(`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`)
+	f(`["a","b"]`, `["c","d"]`, `["c","d"]`)
+	f(`{"a":"b"}`, `["c"]`, `["c"]`)
+	f(`{"a":"foo"}`, `null`, `null`)
+	f(`{"a":"foo"}`, `"bar"`, `"bar"`)
+	f(`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`)
+	f(`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`)
+	f(`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`)
+
+	if v := ApplyMergePatch(nil, MustParse(`{"a":1}`)); v.String() != `{"a":1}` {
+		t.Fatalf("unexpected result for nil target: %s", v)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	f := func(original, modified, patchExpected string) {
+		t.Helper()
+		patch := CreateMergePatch(MustParse(original), MustParse(modified))
+		if s := patch.String(); s != patchExpected {
+			t.Fatalf("unexpected patch for CreateMergePatch(%s, %s)\ngot\n%s\nwant\n%s", original, modified, s, patchExpected)
+		}
+
+		// Applying the patch must produce modified.
+		result := ApplyMergePatch(MustParse(original), MustParse(patch.String()))
+		if !equalExact(result, MustParse(modified)) {
+			t.Fatalf("unexpected result after applying the patch %s to %s\ngot\n%s\nwant\n%s", patch, original, result, modified)
+		}
+	}
+
+	f(`{"a":1}`, `{"a":1}`, `{}`)
+	f(`{"a":1}`, `{"a":2}`, `{"a":2}`)
+	f(`{"a":1,"b":2}`, `{"b":2}`, `{"a":null}`)
+	f(`{"a":1}`, `{"a":1,"b":{"c":3}}`, `{"b":{"c":3}}`)
+	f(`{"a":{"b":1,"c":2}}`, `{"a":{"b":1,"c":3}}`, `{"a":{"c":3}}`)
+	f(`{"a":{"b":1,"c":2}}`, `{"a":{"b":1}}`, `{"a":{"c":null}}`)
+	f(`{"a":[1,2]}`, `{"a":[1,3]}`, `{"a":[1,3]}`)
+	f(`{"a":{"b":1}}`, `{"a":"x"}`, `{"a":"x"}`)
+	f(`{"a":1}`, `[1]`, `[1]`)
+	f(`[1]`, `{"a":1}`, `{"a":1}`)
+}
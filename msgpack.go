@@ -0,0 +1,407 @@
+package fastjson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// MarshalMsgpackTo appends MessagePack representation of v to dst
+// and returns the result.
+//
+// Integer numbers fitting int64 or uint64 are encoded in the most compact
+// MessagePack int format, while the rest of numbers are encoded as float64.
+// Strings and object keys are encoded as MessagePack str.
+//
+// An error is returned if v contains numbers, which cannot be represented
+// as finite float64 such as 1e400, since ParseMsgpack rejects non-finite
+// floats.
+func (v *Value) MarshalMsgpackTo(dst []byte) ([]byte, error) {
+	var err error
+	switch v.Type() {
+	case TypeObject:
+		o := &v.o
+		o.unescapeKeys()
+		dst = appendMsgpackHeader(dst, len(o.kvs), 0x80, 0xde)
+		for _, kv := range o.kvs {
+			dst = appendMsgpackString(dst, kv.k)
+			if dst, err = kv.v.MarshalMsgpackTo(dst); err != nil {
+				return dst, err
+			}
+		}
+		return dst, nil
+	case TypeArray:
+		dst = appendMsgpackHeader(dst, len(v.a), 0x90, 0xdc)
+		for _, vv := range v.a {
+			if dst, err = vv.MarshalMsgpackTo(dst); err != nil {
+				return dst, err
+			}
+		}
+		return dst, nil
+	case TypeString:
+		return appendMsgpackString(dst, v.s), nil
+	case TypeNumber:
+		return appendMsgpackNumber(dst, v.s)
+	case TypeTrue:
+		return append(dst, 0xc3), nil
+	case TypeFalse:
+		return append(dst, 0xc2), nil
+	case TypeNull:
+		return append(dst, 0xc0), nil
+	default:
+		panic(fmt.Errorf("BUG: unexpected Value type: %d", v.t))
+	}
+}
+
+// appendMsgpackHeader appends array or map header for n items to dst.
+//
+// fix is the fixarray or fixmap prefix, while prefix16 is the prefix
+// for the 16-bit length. The 32-bit length prefix follows it.
+func appendMsgpackHeader(dst []byte, n int, fix, prefix16 byte) []byte {
+	switch {
+	case n < 16:
+		return append(dst, fix|byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, prefix16, byte(n>>8), byte(n))
+	default:
+		return append(dst, prefix16+1, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMsgpackString(dst []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, 0xda, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(dst, s...)
+}
+
+func appendMsgpackNumber(dst []byte, s string) ([]byte, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if n, err := fastfloat.ParseInt64(s); err == nil {
+			return appendMsgpackInt(dst, n), nil
+		}
+		if n, err := fastfloat.ParseUint64(s); err == nil {
+			return appendMsgpackUint(dst, n), nil
+		}
+	}
+	f := fastfloat.ParseBestEffort(s)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, fmt.Errorf("cannot marshal number %q to MessagePack: it cannot be represented as finite float64", s)
+	}
+	dst = append(dst, 0xcb)
+	return appendUint64BE(dst, math.Float64bits(f)), nil
+}
+
+func appendMsgpackInt(dst []byte, n int64) []byte {
+	if n >= 0 {
+		return appendMsgpackUint(dst, uint64(n))
+	}
+	switch {
+	case n >= -32:
+		return append(dst, byte(n))
+	case n >= math.MinInt8:
+		return append(dst, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return append(dst, 0xd1, byte(n>>8), byte(n))
+	case n >= math.MinInt32:
+		return append(dst, 0xd2, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xd3)
+		return appendUint64BE(dst, uint64(n))
+	}
+}
+
+func appendMsgpackUint(dst []byte, n uint64) []byte {
+	switch {
+	case n <= 0x7f:
+		return append(dst, byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, 0xcd, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(dst, 0xce, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xcf)
+		return appendUint64BE(dst, n)
+	}
+}
+
+func appendUint64BE(dst []byte, n uint64) []byte {
+	return append(dst, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32), byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// ParseMsgpack parses b containing a single MessagePack value.
+//
+// The function is slower than the Parser.ParseMsgpack for re-used Parser.
+func ParseMsgpack(b []byte) (*Value, error) {
+	var p Parser
+	return p.ParseMsgpack(b)
+}
+
+// ParseMsgpack parses b containing a single MessagePack value.
+//
+// MessagePack bin values are converted to base64-encoded strings,
+// while timestamp extension values are converted to RFC3339 strings in UTC.
+// Map keys must be strings. Other extension types, non-finite floats
+// and trailing bytes after the value result in error.
+//
+// The returned value is valid until the next call to Parse*.
+func (p *Parser) ParseMsgpack(b []byte) (*Value, error) {
+	p.b = append(p.b[:0], b...)
+	p.c.reset()
+
+	s := b2s(p.b)
+	v, tail, err := parseMsgpackValue(s, &p.c, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse MessagePack at offset %d: %s", len(s)-len(tail), err)
+	}
+	if len(tail) > 0 {
+		return nil, fmt.Errorf("unexpected tail after MessagePack value at offset %d: %d bytes", len(s)-len(tail), len(tail))
+	}
+	return v, nil
+}
+
+func parseMsgpackValue(s string, c *cache, depth int) (*Value, string, error) {
+	if len(s) == 0 {
+		return nil, s, fmt.Errorf("unexpected end of data")
+	}
+	depth++
+	if depth > MaxDepth {
+		return nil, s, fmt.Errorf("too big depth for the nested MessagePack; it exceeds %d", MaxDepth)
+	}
+
+	b := s[0]
+	switch {
+	case b <= 0x7f:
+		return newMsgpackNumber(c, strconv.FormatUint(uint64(b), 10)), s[1:], nil
+	case b >= 0xe0:
+		return newMsgpackNumber(c, strconv.FormatInt(int64(int8(b)), 10)), s[1:], nil
+	case b&0xf0 == 0x80:
+		return parseMsgpackMap(s[1:], int(b&0x0f), c, depth)
+	case b&0xf0 == 0x90:
+		return parseMsgpackArray(s[1:], int(b&0x0f), c, depth)
+	case b&0xe0 == 0xa0:
+		return parseMsgpackString(s[1:], int(b&0x1f), c)
+	}
+
+	s = s[1:]
+	switch b {
+	case 0xc0:
+		return valueNull, s, nil
+	case 0xc2:
+		return valueFalse, s, nil
+	case 0xc3:
+		return valueTrue, s, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, tail, err := readMsgpackLength(s, b-0xc4)
+		if err != nil {
+			return nil, s, err
+		}
+		return parseMsgpackBin(tail, n, c)
+	case 0xc7, 0xc8, 0xc9:
+		n, tail, err := readMsgpackLength(s, b-0xc7)
+		if err != nil {
+			return nil, s, err
+		}
+		return parseMsgpackExt(tail, n, c)
+	case 0xca:
+		if len(s) < 4 {
+			return nil, s, fmt.Errorf("unexpected end of float32")
+		}
+		f := math.Float32frombits(binary.BigEndian.Uint32([]byte(s[:4])))
+		return newMsgpackFloat(c, float64(f), 32, s[4:])
+	case 0xcb:
+		if len(s) < 8 {
+			return nil, s, fmt.Errorf("unexpected end of float64")
+		}
+		f := math.Float64frombits(binary.BigEndian.Uint64([]byte(s[:8])))
+		return newMsgpackFloat(c, f, 64, s[8:])
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		size := 1 << (b - 0xcc)
+		if len(s) < size {
+			return nil, s, fmt.Errorf("unexpected end of uint")
+		}
+		n := readMsgpackUint(s[:size])
+		return newMsgpackNumber(c, strconv.FormatUint(n, 10)), s[size:], nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		if len(s) < size {
+			return nil, s, fmt.Errorf("unexpected end of int")
+		}
+		// Sign-extend the big-endian value to int64.
+		n := int64(readMsgpackUint(s[:size]) << (64 - 8*uint(size)))
+		n >>= 64 - 8*uint(size)
+		return newMsgpackNumber(c, strconv.FormatInt(n, 10)), s[size:], nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return parseMsgpackExt(s, 1<<(b-0xd4), c)
+	case 0xd9, 0xda, 0xdb:
+		n, tail, err := readMsgpackLength(s, b-0xd9)
+		if err != nil {
+			return nil, s, err
+		}
+		return parseMsgpackString(tail, n, c)
+	case 0xdc, 0xdd:
+		n, tail, err := readMsgpackLength(s, b-0xdc+1)
+		if err != nil {
+			return nil, s, err
+		}
+		return parseMsgpackArray(tail, n, c, depth)
+	case 0xde, 0xdf:
+		n, tail, err := readMsgpackLength(s, b-0xde+1)
+		if err != nil {
+			return nil, s, err
+		}
+		return parseMsgpackMap(tail, n, c, depth)
+	default:
+		return nil, s, fmt.Errorf("unexpected MessagePack format byte 0x%02x", b)
+	}
+}
+
+// readMsgpackLength reads big-endian length with 1<<sizeLog bytes from s.
+func readMsgpackLength(s string, sizeLog byte) (int, string, error) {
+	size := 1 << sizeLog
+	if len(s) < size {
+		return 0, s, fmt.Errorf("unexpected end of length")
+	}
+	n := readMsgpackUint(s[:size])
+	if n > uint64(len(s)) {
+		// Every item occupies at least a byte, so the length cannot exceed
+		// the remaining data. This protects from huge allocations.
+		return 0, s, fmt.Errorf("too big length %d; it exceeds the remaining %d bytes", n, len(s)-size)
+	}
+	return int(n), s[size:], nil
+}
+
+func readMsgpackUint(s string) uint64 {
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		n = n<<8 | uint64(s[i])
+	}
+	return n
+}
+
+func parseMsgpackString(s string, n int, c *cache) (*Value, string, error) {
+	if len(s) < n {
+		return nil, s, fmt.Errorf("unexpected end of str")
+	}
+	v := c.getValue()
+	v.t = TypeString
+	v.s = s[:n]
+	return v, s[n:], nil
+}
+
+func parseMsgpackBin(s string, n int, c *cache) (*Value, string, error) {
+	if len(s) < n {
+		return nil, s, fmt.Errorf("unexpected end of bin")
+	}
+	v := c.getValue()
+	v.t = TypeString
+	v.s = base64.StdEncoding.EncodeToString([]byte(s[:n]))
+	return v, s[n:], nil
+}
+
+// parseMsgpackExt parses ext data with length n from s.
+//
+// Only the timestamp extension type -1 is supported.
+func parseMsgpackExt(s string, n int, c *cache) (*Value, string, error) {
+	if len(s) < n+1 {
+		return nil, s, fmt.Errorf("unexpected end of ext")
+	}
+	typ := int8(s[0])
+	data := s[1 : n+1]
+	if typ != -1 {
+		return nil, s, fmt.Errorf("unsupported ext type %d", typ)
+	}
+	var sec int64
+	var nsec uint64
+	switch n {
+	case 4:
+		sec = int64(readMsgpackUint(data))
+	case 8:
+		x := readMsgpackUint(data)
+		nsec = x >> 34
+		sec = int64(x & (1<<34 - 1))
+	case 12:
+		nsec = readMsgpackUint(data[:4])
+		sec = int64(readMsgpackUint(data[4:]))
+	default:
+		return nil, s, fmt.Errorf("unexpected timestamp length %d; it must be 4, 8 or 12", n)
+	}
+	if nsec >= 1e9 {
+		return nil, s, fmt.Errorf("too big nanoseconds in timestamp: %d", nsec)
+	}
+	v := c.getValue()
+	v.t = TypeString
+	v.s = time.Unix(sec, int64(nsec)).UTC().Format(time.RFC3339Nano)
+	return v, s[n+1:], nil
+}
+
+func parseMsgpackArray(s string, n int, c *cache, depth int) (*Value, string, error) {
+	a := c.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	for i := 0; i < n; i++ {
+		v, tail, err := parseMsgpackValue(s, c, depth)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse array item #%d: %s", i, err)
+		}
+		a.a = append(a.a, v)
+		s = tail
+	}
+	return a, s, nil
+}
+
+func parseMsgpackMap(s string, n int, c *cache, depth int) (*Value, string, error) {
+	o := c.getValue()
+	o.t = TypeObject
+	o.o.reset()
+	o.o.keysUnescaped = true
+	for i := 0; i < n; i++ {
+		k, tail, err := parseMsgpackValue(s, c, depth)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse map key #%d: %s", i, err)
+		}
+		if k.t != TypeString {
+			return nil, s, fmt.Errorf("unsupported map key #%d of type %s; it must be str", i, k.t)
+		}
+		s = tail
+		v, tail, err := parseMsgpackValue(s, c, depth)
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse map value for key %q: %s", k.s, err)
+		}
+		s = tail
+		kv := o.o.getKV()
+		kv.k = k.s
+		kv.v = v
+	}
+	return o, s, nil
+}
+
+func newMsgpackNumber(c *cache, s string) *Value {
+	v := c.getValue()
+	v.t = TypeNumber
+	v.s = s
+	return v
+}
+
+func newMsgpackFloat(c *cache, f float64, bitSize int, tail string) (*Value, string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, tail, fmt.Errorf("unsupported non-finite float %v", f)
+	}
+	return newMsgpackNumber(c, strconv.FormatFloat(f, 'g', -1, bitSize)), tail, nil
+}
@@ -0,0 +1,155 @@
+package fastjson
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMarshalMsgpackTo(t *testing.T) {
+	f := func(s, hexExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		b, err := v.MarshalMsgpackTo(nil)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", s, err)
+		}
+		result := hex.EncodeToString(b)
+		if result != hexExpected {
+			t.Fatalf("unexpected MessagePack for %s\ngot\n%s\nwant\n%s", s, result, hexExpected)
+		}
+	}
+
+	f(`null`, `c0`)
+	f(`true`, `c3`)
+	f(`false`, `c2`)
+	f(`0`, `00`)
+	f(`127`, `7f`)
+	f(`128`, `cc80`)
+	f(`65535`, `cdffff`)
+	f(`65536`, `ce00010000`)
+	f(`4294967296`, `cf0000000100000000`)
+	f(`18446744073709551615`, `cfffffffffffffffff`)
+	f(`-1`, `ff`)
+	f(`-32`, `e0`)
+	f(`-33`, `d0df`)
+	f(`-129`, `d1ff7f`)
+	f(`-32769`, `d2ffff7fff`)
+	f(`-2147483649`, `d3ffffffff7fffffff`)
+	f(`1.5`, `cb3ff8000000000000`)
+	f(`1e2`, `cb4059000000000000`)
+	f(`""`, `a0`)
+	f(`"a\nb"`, `a3610a62`)
+	f(`"`+strings.Repeat("x", 32)+`"`, `d920`+strings.Repeat("78", 32))
+	f(`[]`, `90`)
+	f(`{}`, `80`)
+	f(`{"a":1,"b":[true,null,-1,"x"]}`, `82a16101a16294c3c0ffa178`)
+	f(`{"a":{}}`, `81a16180`)
+	f(`[`+strings.Repeat("1,", 15)+`1]`, `dc0010`+strings.Repeat("01", 16))
+	f(`1.7976931348623157e308`, `cb7fefffffffffffff`)
+}
+
+func TestMarshalMsgpackToError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		if _, err := MustParse(s).MarshalMsgpackTo(nil); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+	}
+
+	// Non-finite numbers
+	f(`1e400`)
+	f(`-1e400`)
+	f(`NaN`)
+	f(`[1,{"a":1e400}]`)
+	f(`{"a":[2,-1e309]}`)
+}
+
+func TestParseMsgpack(t *testing.T) {
+	f := func(hexStr, resultExpected string) {
+		t.Helper()
+		b, err := hex.DecodeString(hexStr)
+		if err != nil {
+			t.Fatalf("cannot decode hex: %s", err)
+		}
+		v, err := ParseMsgpack(b)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", hexStr, err)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s\ngot\n%s\nwant\n%s", hexStr, result, resultExpected)
+		}
+	}
+
+	f(`c0`, `null`)
+	f(`c3`, `true`)
+	f(`c2`, `false`)
+	f(`7f`, `127`)
+	f(`e0`, `-32`)
+	f(`cc80`, `128`)
+	f(`cfffffffffffffffff`, `18446744073709551615`)
+	f(`d0df`, `-33`)
+	f(`d1ff7f`, `-129`)
+	f(`d3ffffffff7fffffff`, `-2147483649`)
+	f(`ca3fc00000`, `1.5`)
+	f(`cb3fb999999999999a`, `0.1`)
+	f(`a3610a62`, `"a\nb"`)
+	f(`da0001`+`22`, `"\""`)
+	f(`c403010203`, `"AQID"`)
+	f(`d6ff00000001`, `"1970-01-01T00:00:01Z"`)
+	f(`d7ff0000000400000002`, `"1970-01-01T00:00:02.000000001Z"`)
+	f(`c70cff000000010000000000000003`, `"1970-01-01T00:00:03.000000001Z"`)
+	f(`82a16101a16294c3c0ffa178`, `{"a":1,"b":[true,null,-1,"x"]}`)
+	f(`de0001a0dd00000000`, `{"":[]}`)
+
+	// Round trip
+	s := `{"id":12345678901,"name":"foo \"bar\"","tags":["a","b"],"n":null,"f":-1.25e-10,"o":{"x":[{},[]]}}`
+	b, err := MustParse(s).MarshalMsgpackTo(nil)
+	if err != nil {
+		t.Fatalf("cannot marshal MessagePack: %s", err)
+	}
+	var p Parser
+	v, err := p.ParseMsgpack(b)
+	if err != nil {
+		t.Fatalf("cannot parse MessagePack: %s", err)
+	}
+	if result := v.String(); result != s {
+		t.Fatalf("unexpected round trip result\ngot\n%s\nwant\n%s", result, s)
+	}
+	if name := v.GetStringBytes("name"); string(name) != `foo "bar"` {
+		t.Fatalf("unexpected name: %q", name)
+	}
+}
+
+func TestParseMsgpackError(t *testing.T) {
+	f := func(hexStr string) {
+		t.Helper()
+		b, err := hex.DecodeString(hexStr)
+		if err != nil {
+			t.Fatalf("cannot decode hex: %s", err)
+		}
+		v, err := ParseMsgpack(b)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %s; got %s", hexStr, v)
+		}
+	}
+
+	f(``)
+	f(`c1`)
+	f(`c0c0`)
+	f(`cd01`)
+	f(`a2`)
+	f(`d9`)
+	f(`dbffffffff`)
+	f(`92c0`)
+	f(`81`)
+	f(`8101c0`)
+	f(`81a161`)
+	f(`cb7ff8000000000000`)
+	f(`ca7f800000`)
+	f(`d40100`)
+	f(`d5ff0000`)
+	f(`d7ffffffffff00000000`)
+	f(strings.Repeat(`91`, MaxDepth+1) + `c0`)
+}
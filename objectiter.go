@@ -0,0 +1,163 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ObjectIter iterates over members of a single top-level JSON object
+// read from io.Reader.
+//
+// Members are parsed one by one without materializing the whole object,
+// so huge objects may be processed with memory usage proportional
+// to the size of the biggest member.
+//
+// ObjectIter may be re-used for subsequent iterations.
+//
+// ObjectIter cannot be used from concurrent goroutines.
+type ObjectIter struct {
+	// sc holds the read buffer and the value cache.
+	sc Scanner
+
+	// state is the iteration state.
+	state int
+
+	// key contains the last parsed member key.
+	key string
+}
+
+const (
+	objectIterStart = iota
+	objectIterFirstMember
+	objectIterMember
+	objectIterEnd
+)
+
+// Init initializes it for iterating over members of the JSON object read from r.
+//
+// r must contain a single JSON object optionally surrounded by whitespace.
+func (it *ObjectIter) Init(r io.Reader) {
+	it.sc.InitReader(r)
+	it.state = objectIterStart
+	it.key = ""
+}
+
+// Next parses the next object member.
+//
+// Returns true on success. The parsed member is available via Key and Value calls.
+//
+// Returns false either on error or on the end of the object.
+// Call Error in order to determine the cause of the returned false.
+func (it *ObjectIter) Next() bool {
+	sc := &it.sc
+	if sc.err != nil {
+		return false
+	}
+	if it.state == objectIterStart {
+		tail, ok := it.scan(func(s string) (string, error) {
+			if s[0] != '{' {
+				return s, fmt.Errorf("missing '{' at the start of the object: %q", startEndString(s))
+			}
+			return s[1:], nil
+		})
+		if !ok {
+			return false
+		}
+		sc.s = tail
+		it.state = objectIterFirstMember
+	}
+	if it.state == objectIterEnd {
+		sc.err = errEOF
+		return false
+	}
+
+	var key string
+	var v *Value
+	end := false
+	tail, ok := it.scan(func(s string) (string, error) {
+		sc.c.reset()
+		end = false
+		if s[0] == '}' {
+			end = true
+			return s[1:], nil
+		}
+		if it.state == objectIterMember {
+			if s[0] != ',' {
+				return s, fmt.Errorf("missing ',' after object value")
+			}
+			s = skipWS(s[1:])
+		}
+		if len(s) == 0 || s[0] != '"' {
+			return s, fmt.Errorf(`cannot find opening '"" for object key`)
+		}
+		var err error
+		key, s, err = parseRawKey(s[1:])
+		if err != nil {
+			return s, fmt.Errorf("cannot parse object key: %s", err)
+		}
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != ':' {
+			return s, fmt.Errorf("missing ':' after object key")
+		}
+		s = skipWS(s[1:])
+		v, s, err = parseValue(s, &sc.c, 1, nil)
+		if err != nil {
+			return s, fmt.Errorf("cannot parse object value: %s", err)
+		}
+		return s, nil
+	})
+	if !ok {
+		return false
+	}
+	sc.s = tail
+	if end {
+		it.state = objectIterEnd
+		// Verify there is only whitespace after the object.
+		it.scan(func(s string) (string, error) {
+			return s, fmt.Errorf("unexpected tail after the object: %q", startEndString(s))
+		})
+		return false
+	}
+	if strings.IndexByte(key, '\\') >= 0 {
+		key = unescapeStringBestEffort(key)
+	}
+	it.key = key
+	it.state = objectIterMember
+	sc.v = v
+	return true
+}
+
+// scan calls parse for the data after whitespace and returns the tail
+// after the parsed data. Missing end of the object is reported as an error.
+func (it *ObjectIter) scan(parse func(s string) (string, error)) (string, bool) {
+	sc := &it.sc
+	_, tail, ok := sc.scanValue(parse)
+	if !ok && sc.err == errEOF && it.state != objectIterEnd {
+		if it.state == objectIterStart {
+			sc.err = fmt.Errorf("missing JSON object")
+		} else {
+			sc.err = fmt.Errorf("missing '}'")
+		}
+	}
+	return tail, ok
+}
+
+// Key returns the key of the last parsed member.
+//
+// The key is valid until the Next call.
+func (it *ObjectIter) Key() string {
+	return it.key
+}
+
+// Value returns the value of the last parsed member.
+//
+// The value is valid until the Next call.
+func (it *ObjectIter) Value() *Value {
+	return it.sc.v
+}
+
+// Error returns the last error.
+func (it *ObjectIter) Error() error {
+	return it.sc.Error()
+}
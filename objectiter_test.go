@@ -0,0 +1,87 @@
+package fastjson
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestObjectIter(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		for _, chunkSize := range []int{1, 3, 1000} {
+			var it ObjectIter
+			it.Init(&chunkedReader{
+				s:         s,
+				chunkSize: chunkSize,
+			})
+			var bb bytes.Buffer
+			for it.Next() {
+				fmt.Fprintf(&bb, "%s=%s;", it.Key(), it.Value())
+			}
+			if err := it.Error(); err != nil {
+				t.Fatalf("unexpected error for chunkSize=%d: %s", chunkSize, err)
+			}
+			if result := bb.String(); result != resultExpected {
+				t.Fatalf("unexpected result for chunkSize=%d\ngot\n%s\nwant\n%s", chunkSize, result, resultExpected)
+			}
+		}
+	}
+
+	f(`{}`, ``)
+	f(` { } `, ``)
+	f(`{"a":1}`, `a=1;`)
+	f(` {"a" : {"b":[1,2]} , "c":"d", "e\nf":null,"g":123.45}
+	`, `a={"b":[1,2]};c="d";e
+f=null;g=123.45;`)
+}
+
+func TestObjectIterError(t *testing.T) {
+	f := func(s string, membersExpected int) {
+		t.Helper()
+		var it ObjectIter
+		it.Init(strings.NewReader(s))
+		n := 0
+		for it.Next() {
+			n++
+		}
+		if err := it.Error(); err == nil {
+			t.Fatalf("expecting non-nil error for %q", s)
+		}
+		if n != membersExpected {
+			t.Fatalf("unexpected number of members for %q; got %d; want %d", s, n, membersExpected)
+		}
+	}
+
+	f(``, 0)
+	f(`  `, 0)
+	f(`[]`, 0)
+	f(`{`, 0)
+	f(`{"a":1`, 1)
+	f(`{"a":1,}`, 1)
+	f(`{"a":1 "b":2}`, 1)
+	f(`{"a" 1}`, 0)
+	f(`{a:1}`, 0)
+	f(`{"a":[}`, 0)
+	f(`{"a":1} {}`, 1)
+}
+
+func TestObjectIterReuse(t *testing.T) {
+	var it ObjectIter
+	for i := 0; i < 3; i++ {
+		it.Init(strings.NewReader(fmt.Sprintf(`{"n":%d}`, i)))
+		if !it.Next() {
+			t.Fatalf("unexpected error: %s", it.Error())
+		}
+		if n := it.Value().GetInt(); n != i {
+			t.Fatalf("unexpected value; got %d; want %d", n, i)
+		}
+		if it.Next() {
+			t.Fatalf("unexpected member %q", it.Key())
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package fastjson
+
+// BuildIndex builds an index for fast key lookups in o.
+//
+// Object.Get performs linear scan over object keys by default. This may be
+// slow for objects with thousands of keys. After BuildIndex call Get
+// and Set use the index instead of linear scan.
+//
+// The index is maintained by Set and is dropped by Del, Rename and MoveKey.
+// BuildIndex may be called again in order to rebuild the dropped index.
+func (o *Object) BuildIndex() {
+	if o == nil {
+		return
+	}
+	o.unescapeKeys()
+	idx := make(map[string]int, len(o.kvs))
+	for i, kv := range o.kvs {
+		if _, ok := idx[kv.k]; !ok {
+			// Get returns the first value for duplicate keys.
+			idx[kv.k] = i
+		}
+	}
+	o.idx = idx
+}
+
+// HasIndex returns true if o has the index built by BuildIndex.
+func (o *Object) HasIndex() bool {
+	return o != nil && o.idx != nil
+}
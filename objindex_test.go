@@ -0,0 +1,87 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestObjectBuildIndex(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(`{"dup":"first","esc\naped":"e"`)
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&b, `,"key_%d":%d`, i, i)
+	}
+	b.WriteString(`,"dup":"second"}`)
+
+	var p Parser
+	v, err := p.Parse(b.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	o := v.GetObject()
+	if o.HasIndex() {
+		t.Fatalf("unexpected index before BuildIndex call")
+	}
+	o.BuildIndex()
+	if !o.HasIndex() {
+		t.Fatalf("missing index after BuildIndex call")
+	}
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key_%d", i)
+		if n := v.GetInt(key); n != i {
+			t.Fatalf("unexpected value for %q; got %d; want %d", key, n, i)
+		}
+	}
+	if s := v.GetStringBytes("dup"); string(s) != "first" {
+		t.Fatalf("unexpected value for duplicate key; got %q; want %q", s, "first")
+	}
+	if s := v.GetStringBytes("esc\naped"); string(s) != "e" {
+		t.Fatalf("unexpected value for escaped key; got %q; want %q", s, "e")
+	}
+	if vv := o.Get("missing"); vv != nil {
+		t.Fatalf("unexpected value for missing key: %s", vv)
+	}
+
+	// Set must maintain the index.
+	var a Arena
+	o.Set("key_5", a.NewString("updated"))
+	o.Set("new_key", a.NewNumberInt(42))
+	if !o.HasIndex() {
+		t.Fatalf("Set mustn't drop the index")
+	}
+	if s := v.GetStringBytes("key_5"); string(s) != "updated" {
+		t.Fatalf("unexpected updated value; got %q; want %q", s, "updated")
+	}
+	if n := v.GetInt("new_key"); n != 42 {
+		t.Fatalf("unexpected new value; got %d; want %d", n, 42)
+	}
+
+	// Del must drop the index.
+	o.Del("key_0")
+	if o.HasIndex() {
+		t.Fatalf("Del must drop the index")
+	}
+	if vv := o.Get("key_0"); vv != nil {
+		t.Fatalf("unexpected value for deleted key: %s", vv)
+	}
+	if n := v.GetInt("key_999"); n != 999 {
+		t.Fatalf("unexpected value after Del; got %d; want %d", n, 999)
+	}
+
+	// The index must be dropped on the next Parse.
+	v, err = p.Parse(`{"a":1}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v.GetObject().HasIndex() {
+		t.Fatalf("unexpected index after Parse")
+	}
+
+	var oNil *Object
+	oNil.BuildIndex()
+	if oNil.HasIndex() {
+		t.Fatalf("unexpected index for nil object")
+	}
+}
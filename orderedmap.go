@@ -0,0 +1,63 @@
+package fastjson
+
+// OrderedMap is a map from string keys to Values, which preserves
+// insertion order of the keys.
+//
+// Object implements OrderedMap, so the same insertion-ordered map
+// implementation may be shared between JSON and non-JSON code.
+type OrderedMap interface {
+	// Get returns the value for the given key or nil if the key is missing.
+	Get(key string) *Value
+
+	// Set sets the value for the given key.
+	//
+	// New keys are appended to the end of the map.
+	Set(key string, value *Value)
+
+	// Delete deletes the given key from the map.
+	Delete(key string)
+
+	// Iterate calls f for each item in the map in insertion order
+	// until f returns false.
+	Iterate(f func(key string, value *Value) bool)
+
+	// Len returns the number of items in the map.
+	Len() int
+}
+
+var _ OrderedMap = (*Object)(nil)
+
+// NewOrderedMap returns new empty insertion-ordered map.
+//
+// The returned map doesn't depend on Parser or Arena, so it may be used
+// independently of JSON parsing.
+func NewOrderedMap() *Object {
+	return &Object{
+		keysUnescaped: true,
+	}
+}
+
+// Delete deletes the entry with the given key from o.
+//
+// It is equivalent to Del.
+func (o *Object) Delete(key string) {
+	o.Del(key)
+}
+
+// Iterate calls f for each item in the o in the original order
+// until f returns false.
+//
+// f cannot hold key and/or value after returning if o belongs to Parser.
+func (o *Object) Iterate(f func(key string, value *Value) bool) {
+	if o == nil {
+		return
+	}
+
+	o.unescapeKeys()
+
+	for _, kv := range o.kvs {
+		if !f(kv.k, kv.v) {
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap = NewOrderedMap()
+	var a Arena
+	m.Set("z", a.NewNumberInt(1))
+	m.Set("a", a.NewString("foo"))
+	m.Set("m", a.NewTrue())
+	m.Set("z", a.NewNumberInt(2))
+	if n := m.Len(); n != 3 {
+		t.Fatalf("unexpected map length; got %d; want %d", n, 3)
+	}
+	if v := m.Get("z"); v == nil || v.GetInt() != 2 {
+		t.Fatalf("unexpected value for key %q: %v", "z", v)
+	}
+	if v := m.Get("missing"); v != nil {
+		t.Fatalf("unexpected non-nil value for missing key: %s", v)
+	}
+
+	var keys []string
+	m.Iterate(func(key string, value *Value) bool {
+		keys = append(keys, fmt.Sprintf("%s=%s", key, value))
+		return true
+	})
+	result := strings.Join(keys, ",")
+	resultExpected := `z=2,a="foo",m=true`
+	if result != resultExpected {
+		t.Fatalf("unexpected iteration order; got %s; want %s", result, resultExpected)
+	}
+
+	// Stop iteration early.
+	n := 0
+	m.Iterate(func(key string, value *Value) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("unexpected number of iterations; got %d; want %d", n, 1)
+	}
+
+	m.Delete("a")
+	if s := m.(*Object).String(); s != `{"z":2,"m":true}` {
+		t.Fatalf("unexpected map contents; got %s; want %s", s, `{"z":2,"m":true}`)
+	}
+
+	// Parsed objects implement OrderedMap too.
+	o := MustParse(`{"x\ny":1,"b":2}`).GetObject()
+	m = o
+	if v := m.Get("x\ny"); v == nil {
+		t.Fatalf("cannot find escaped key")
+	}
+}
@@ -3,9 +3,12 @@ package fastjson
 import (
 	"fmt"
 	"github.com/valyala/fastjson/fastfloat"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // Parser parses JSON.
@@ -20,6 +23,9 @@ type Parser struct {
 
 	// c is a cache for json values.
 	c cache
+
+	// shape is the expected shape of the parsed JSON set via SetShape.
+	shape *Shape
 }
 
 // Parse parses s containing JSON.
@@ -32,7 +38,7 @@ func (p *Parser) Parse(s string) (*Value, error) {
 	p.b = append(p.b[:0], s...)
 	p.c.reset()
 
-	v, tail, err := parseValue(b2s(p.b), &p.c, 0)
+	v, tail, err := parseValue(b2s(p.b), &p.c, 0, p.shape)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(tail))
 	}
@@ -43,6 +49,18 @@ func (p *Parser) Parse(s string) (*Value, error) {
 	return v, nil
 }
 
+// SetShape sets the expected shape for JSON parsed by p.
+//
+// Parse returns an error as soon as the parsed JSON mismatches s,
+// so the rest of clearly invalid JSON isn't parsed. Object fields
+// are checked when the object end is reached. Pass nil in order
+// to disable shape checks.
+//
+// The shape persists across Parse calls.
+func (p *Parser) SetShape(s *Shape) {
+	p.shape = s
+}
+
 // ParseBytes parses b containing JSON.
 //
 // The returned Value is valid until the next call to Parse*.
@@ -70,8 +88,11 @@ func (c *cache) getValue() *Value {
 		c.vs = append(c.vs, Value{})
 	}
 	// Do not reset the value, since the caller must properly init it.
+	// loneSurrogates is reset, since it is set only when unescaping strings.
 	// 返回切片中最后一个元素的地址，这个元素要么是新激活的预分配元素，要么是新追加的元素。
-	return &c.vs[len(c.vs)-1]
+	v := &c.vs[len(c.vs)-1]
+	v.loneSurrogates = false
+	return v
 }
 
 // 跳过字符串的前导空白字符
@@ -105,7 +126,7 @@ type kv struct {
 // MaxDepth is the maximum depth for nested JSON.
 const MaxDepth = 300
 
-func parseValue(s string, c *cache, depth int) (*Value, string, error) {
+func parseValue(s string, c *cache, depth int, sh *Shape) (*Value, string, error) {
 	if len(s) == 0 {
 		return nil, s, fmt.Errorf("cannot parse empty string")
 	}
@@ -115,6 +136,11 @@ func parseValue(s string, c *cache, depth int) (*Value, string, error) {
 	if depth > MaxDepth {
 		return nil, s, fmt.Errorf("too big depth for the nested JSON; it exceeds %d", MaxDepth)
 	}
+	if sh != nil {
+		if err := sh.checkRawValue(s); err != nil {
+			return nil, s, err
+		}
+	}
 
 	// 根据 s[0] 的首字符，判断当前值的类型：
 	//	'{' → 调 parseObject
@@ -125,14 +151,14 @@ func parseValue(s string, c *cache, depth int) (*Value, string, error) {
 	//	'n' → 必须是 null 或 nan
 	//	其他 → 当作 number 调 parseRawNumber
 	if s[0] == '{' {
-		v, tail, err := parseObject(s[1:], c, depth)
+		v, tail, err := parseObject(s[1:], c, depth, sh)
 		if err != nil {
 			return nil, tail, fmt.Errorf("cannot parse object: %s", err)
 		}
 		return v, tail, nil
 	}
 	if s[0] == '[' {
-		v, tail, err := parseArray(s[1:], c, depth)
+		v, tail, err := parseArray(s[1:], c, depth, sh)
 		if err != nil {
 			return nil, tail, fmt.Errorf("cannot parse array: %s", err)
 		}
@@ -184,7 +210,7 @@ func parseValue(s string, c *cache, depth int) (*Value, string, error) {
 	return v, tail, nil
 }
 
-func parseArray(s string, c *cache, depth int) (*Value, string, error) {
+func parseArray(s string, c *cache, depth int, sh *Shape) (*Value, string, error) {
 	// 先跳过前导空白
 	s = skipWS(s)
 	// 如果 s 为空，说明缺少 ]，直接报错
@@ -211,7 +237,11 @@ func parseArray(s string, c *cache, depth int) (*Value, string, error) {
 
 		/// 调用 parseValue 解析下一个值，将解析出的值追加到数组中。
 		s = skipWS(s)
-		v, s, err = parseValue(s, c, depth)
+		var itemShape *Shape
+		if sh != nil {
+			itemShape = sh.item
+		}
+		v, s, err = parseValue(s, c, depth, itemShape)
 		if err != nil {
 			return nil, s, fmt.Errorf("cannot parse array value: %s", err)
 		}
@@ -236,7 +266,7 @@ func parseArray(s string, c *cache, depth int) (*Value, string, error) {
 	}
 }
 
-func parseObject(s string, c *cache, depth int) (*Value, string, error) {
+func parseObject(s string, c *cache, depth int, sh *Shape) (*Value, string, error) {
 	// 跳过前导空白
 	s = skipWS(s)
 	if len(s) == 0 { // 缺少闭合 } 字符。
@@ -245,9 +275,14 @@ func parseObject(s string, c *cache, depth int) (*Value, string, error) {
 
 	// 检查是否是空对象
 	if s[0] == '}' {
-		v := c.getValue()    // 从缓存中获取一个空 Value
-		v.t = TypeObject     // 设置数据类型
-		v.o.reset()          // 清空对象的键值对
+		v := c.getValue() // 从缓存中获取一个空 Value
+		v.t = TypeObject  // 设置数据类型
+		v.o.reset()       // 清空对象的键值对
+		if sh != nil {
+			if err := sh.checkRequiredFields(&v.o); err != nil {
+				return nil, s, err
+			}
+		}
 		return v, s[1:], nil // 返回空对象，推进 s 来跳过 } 。
 	}
 
@@ -288,8 +323,15 @@ func parseObject(s string, c *cache, depth int) (*Value, string, error) {
 		// 跳过前导空白
 		s = skipWS(s)
 		// 解析出 value 并保存到 kv.v
-		kv.v, s, err = parseValue(s, c, depth)
+		var fieldShape *Shape
+		if sh != nil {
+			fieldShape = sh.rawFieldShape(kv.k, s)
+		}
+		kv.v, s, err = parseValue(s, c, depth, fieldShape)
 		if err != nil {
+			if fieldShape != nil {
+				return nil, s, fmt.Errorf("cannot parse object value for key %q: %s", kv.k, err)
+			}
 			return nil, s, fmt.Errorf("cannot parse object value: %s", err)
 		}
 		s = skipWS(s)
@@ -303,6 +345,11 @@ func parseObject(s string, c *cache, depth int) (*Value, string, error) {
 		}
 		// 遇到 } 意味着对象结束，跳过右花括号，返回解析结果
 		if s[0] == '}' {
+			if sh != nil {
+				if err := sh.checkRequiredFields(&o.o); err != nil {
+					return nil, s, err
+				}
+			}
 			return o, s[1:], nil
 		}
 
@@ -355,11 +402,21 @@ func hasSpecialChars(s string) bool {
 }
 
 func unescapeStringBestEffort(s string) string {
+	us, _ := unescapeStringSurrogates(s)
+	return us
+}
+
+// unescapeStringSurrogates unescapes s in the same way as unescapeStringBestEffort.
+//
+// It also returns true if s contains escape sequences for lone UTF-16
+// surrogates, which cannot be represented in UTF-8.
+func unescapeStringSurrogates(s string) (string, bool) {
 	// 当字符串中不包含反斜杠 \ 时，直接返回原字符串，无需任何处理。
 	n := strings.IndexByte(s, '\\')
 	if n < 0 {
-		return s // Fast path - nothing to unescape.
+		return s, false // Fast path - nothing to unescape.
 	}
+	loneSurrogates := false
 	// Slow path - unescape string.
 
 	// 当 s 中包含反斜杠时，进入详细的转义处理逻辑。
@@ -424,17 +481,22 @@ func unescapeStringBestEffort(s string) string {
 				// 没有配对的代理项，保持原样
 				b = append(b, "\\u"...)
 				b = append(b, xs...)
+				loneSurrogates = true
 				break
 			}
 			x1, err := strconv.ParseUint(s[2:6], 16, 16)
 			if err != nil {
 				b = append(b, "\\u"...)
 				b = append(b, xs...)
+				loneSurrogates = true
 				break
 			}
 
 			// 解码 UTF-16 代理对为完整的 Unicode 字符
 			r := utf16.DecodeRune(rune(x), rune(x1))
+			if r == utf8.RuneError {
+				loneSurrogates = true
+			}
 			b = append(b, string(r)...)
 			s = s[6:]
 		default:
@@ -454,7 +516,7 @@ func unescapeStringBestEffort(s string) string {
 	}
 
 	// 将处理后的字节切片转换回字符串返回
-	return b2s(b)
+	return b2s(b), loneSurrogates
 }
 
 // parseRawKey is similar to parseRawString, but is optimized
@@ -562,11 +624,20 @@ func parseRawNumber(s string) (string, string, error) {
 type Object struct {
 	kvs           []kv // 对象的键值对列表
 	keysUnescaped bool // 优化标志，表示键是否是未转义的纯字符串
+
+	// keysLoneSurrogates is set if the unescaped keys contained escape
+	// sequences for lone UTF-16 surrogates.
+	keysLoneSurrogates bool
+
+	// idx maps keys to kvs indexes. It is built by BuildIndex.
+	idx map[string]int
 }
 
 func (o *Object) reset() {
 	o.kvs = o.kvs[:0]
 	o.keysUnescaped = false
+	o.keysLoneSurrogates = false
+	o.idx = nil
 }
 
 // MarshalTo appends marshaled o to dst and returns the result.
@@ -617,7 +688,11 @@ func (o *Object) unescapeKeys() {
 	kvs := o.kvs
 	for i := range kvs {
 		kv := &kvs[i]
-		kv.k = unescapeStringBestEffort(kv.k)
+		k, loneSurrogates := unescapeStringSurrogates(kv.k)
+		kv.k = k
+		if loneSurrogates {
+			o.keysLoneSurrogates = true
+		}
 	}
 	o.keysUnescaped = true
 }
@@ -633,6 +708,15 @@ func (o *Object) Len() int {
 //
 // The returned value is valid until Parse is called on the Parser returned o.
 func (o *Object) Get(key string) *Value {
+	if o.idx != nil {
+		// Fast path - use the index built by BuildIndex.
+		i, ok := o.idx[key]
+		if !ok {
+			return nil
+		}
+		return o.kvs[i].v
+	}
+
 	if !o.keysUnescaped && strings.IndexByte(key, '\\') < 0 {
 		// Fast path - try searching for the key without object keys unescaping.
 		for _, kv := range o.kvs {
@@ -669,6 +753,27 @@ func (o *Object) Visit(f func(key []byte, v *Value)) {
 	}
 }
 
+// VisitErr calls f for each item in the o in the original order
+// of the parsed JSON until f returns an error.
+//
+// The error returned by f is returned from VisitErr.
+//
+// f cannot hold key and/or v after returning.
+func (o *Object) VisitErr(f func(key []byte, v *Value) error) error {
+	if o == nil {
+		return nil
+	}
+
+	o.unescapeKeys()
+
+	for _, kv := range o.kvs {
+		if err := f(s2b(kv.k), kv.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Value represents any JSON value.
 //
 // Call Type in order to determine the actual type of the JSON value.
@@ -680,10 +785,19 @@ type Value struct {
 	a []*Value // 数组类型
 	s string   // 字符串/数字类型
 	t Type     // 类型标记
+
+	// loneSurrogates is set if s has been unescaped from a string
+	// containing escape sequences for lone UTF-16 surrogates.
+	loneSurrogates bool
 }
 
 // MarshalTo appends marshaled v to dst and returns the result.
 func (v *Value) MarshalTo(dst []byte) []byte {
+	if dst == nil {
+		// Allocate the destination buffer at once instead of growing it
+		// via multiple appends for big values.
+		dst = make([]byte, 0, v.MarshaledSize())
+	}
 	switch v.t {
 	case typeRawString:
 		// 原始字符串类型：
@@ -731,6 +845,59 @@ func (v *Value) MarshalTo(dst []byte) []byte {
 	}
 }
 
+// MarshaledSize returns an upper bound for the size of v marshaled
+// via MarshalTo.
+//
+// The upper bound is cheap to calculate, so it may be used
+// for pre-sizing the destination buffer passed to MarshalTo.
+func (v *Value) MarshaledSize() int {
+	switch v.t {
+	case TypeObject:
+		n := 2
+		for i, kv := range v.o.kvs {
+			if v.o.keysUnescaped {
+				n += escapedStringSize(kv.k)
+			} else {
+				n += len(kv.k) + 2
+			}
+			n += kv.v.MarshaledSize() + 1
+			if i != len(v.o.kvs)-1 {
+				n++
+			}
+		}
+		return n
+	case TypeArray:
+		n := 2
+		for i, vv := range v.a {
+			n += vv.MarshaledSize()
+			if i != len(v.a)-1 {
+				n++
+			}
+		}
+		return n
+	case typeRawString:
+		return len(v.s) + 2
+	case TypeString:
+		return escapedStringSize(v.s)
+	case TypeNumber:
+		return len(v.s)
+	case TypeFalse:
+		return len("false")
+	default:
+		// true and null
+		return len("null")
+	}
+}
+
+// escapedStringSize returns an upper bound for the size of s escaped via escapeString.
+func escapedStringSize(s string) int {
+	if !hasSpecialChars(s) {
+		return len(s) + 2
+	}
+	// strconv.AppendQuote emits up to 4 bytes per input byte.
+	return 4*len(s) + 2
+}
+
 // String returns string representation of the v.
 //
 // The function is for debugging purposes only. It isn't optimized for speed.
@@ -771,6 +938,9 @@ const (
 	TypeFalse Type = 6
 
 	typeRawString Type = 7
+
+	// TypeNotExist is returned by TypeOf for non-existing keys path.
+	TypeNotExist Type = -1
 )
 
 // String returns string representation of t.
@@ -790,6 +960,8 @@ func (t Type) String() string {
 		return "false"
 	case TypeNull:
 		return "null"
+	case TypeNotExist:
+		return "notexist"
 
 	// typeRawString is skipped intentionally,
 	// since it shouldn't be visible to user.
@@ -801,12 +973,47 @@ func (t Type) String() string {
 // Type returns the type of the v.
 func (v *Value) Type() Type {
 	if v.t == typeRawString {
-		v.s = unescapeStringBestEffort(v.s)
+		v.s, v.loneSurrogates = unescapeStringSurrogates(v.s)
 		v.t = TypeString
 	}
 	return v.t
 }
 
+// Len returns the number of items in array v, the number of keys
+// in object v or the length of string v in bytes.
+//
+// 0 is returned for other value types.
+func (v *Value) Len() int {
+	if v == nil {
+		return 0
+	}
+	switch v.Type() {
+	case TypeArray:
+		return len(v.a)
+	case TypeObject:
+		return v.o.Len()
+	case TypeString:
+		return len(v.s)
+	default:
+		return 0
+	}
+}
+
+// TypeOf returns the type of the value at the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// TypeNotExist is returned for non-existing keys path. This allows
+// distinguishing missing keys from null values and values of unexpected
+// type with a single call.
+func (v *Value) TypeOf(keys ...string) Type {
+	v = v.Get(keys...)
+	if v == nil {
+		return TypeNotExist
+	}
+	return v.Type()
+}
+
 // Exists returns true if the field exists for the given keys path.
 //
 // Array indexes may be represented as decimal numbers in keys.
@@ -893,6 +1100,19 @@ func (v *Value) GetFloat64(keys ...string) float64 {
 	return fastfloat.ParseBestEffort(v.s)
 }
 
+// GetFloat32 returns float32 value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// 0 is returned for non-existing keys path or for invalid value type.
+func (v *Value) GetFloat32(keys ...string) float32 {
+	v = v.Get(keys...)
+	if v == nil || v.Type() != TypeNumber {
+		return 0
+	}
+	return fastfloat.ParseFloat32BestEffort(v.s)
+}
+
 // GetInt returns int value by the given keys path.
 //
 // Array indexes may be represented as decimal numbers in keys.
@@ -970,6 +1190,40 @@ func (v *Value) GetStringBytes(keys ...string) []byte {
 	return s2b(v.s)
 }
 
+// GetRaw returns JSON representation of the value at the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// nil is returned for non-existing keys path.
+//
+// Numbers are returned without copying, so the returned number is valid
+// until Parse is called on the Parser returned v. Use AppendRaw for
+// marshaling the value into a re-used buffer.
+func (v *Value) GetRaw(keys ...string) []byte {
+	v = v.Get(keys...)
+	if v == nil {
+		return nil
+	}
+	if v.t == TypeNumber {
+		return s2b(v.s)
+	}
+	return v.MarshalTo(nil)
+}
+
+// AppendRaw appends JSON representation of the value at the given keys path
+// to dst and returns the result.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// dst is returned unchanged for non-existing keys path.
+func (v *Value) AppendRaw(dst []byte, keys ...string) []byte {
+	v = v.Get(keys...)
+	if v == nil {
+		return dst
+	}
+	return v.MarshalTo(dst)
+}
+
 // GetBool returns bool value by the given keys path.
 //
 // Array indexes may be represented as decimal numbers in keys.
@@ -983,6 +1237,93 @@ func (v *Value) GetBool(keys ...string) bool {
 	return false
 }
 
+// GetIntOr returns int value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// defaultValue is returned for non-existing keys path or for invalid value type.
+func (v *Value) GetIntOr(defaultValue int, keys ...string) int {
+	v = v.Get(keys...)
+	if v == nil || v.Type() != TypeNumber {
+		return defaultValue
+	}
+	n, err := v.Int()
+	if err != nil {
+		return defaultValue
+	}
+	return n
+}
+
+// GetInt64Or returns int64 value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// defaultValue is returned for non-existing keys path or for invalid value type.
+func (v *Value) GetInt64Or(defaultValue int64, keys ...string) int64 {
+	v = v.Get(keys...)
+	if v == nil || v.Type() != TypeNumber {
+		return defaultValue
+	}
+	n, err := fastfloat.ParseInt64(v.s)
+	if err != nil {
+		return defaultValue
+	}
+	return n
+}
+
+// GetFloat64Or returns float64 value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// defaultValue is returned for non-existing keys path or for invalid value type.
+func (v *Value) GetFloat64Or(defaultValue float64, keys ...string) float64 {
+	v = v.Get(keys...)
+	if v == nil || v.Type() != TypeNumber {
+		return defaultValue
+	}
+	f, err := fastfloat.Parse(v.s)
+	if err != nil {
+		return defaultValue
+	}
+	return f
+}
+
+// GetStringOr returns string value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// defaultValue is returned for non-existing keys path or for invalid value type.
+//
+// Unlike GetStringBytes, the returned string is a copy, so it remains valid
+// after Parse is called on the Parser returned v.
+func (v *Value) GetStringOr(defaultValue string, keys ...string) string {
+	v = v.Get(keys...)
+	if v == nil || v.Type() != TypeString {
+		return defaultValue
+	}
+	return string(s2b(v.s))
+}
+
+// GetBoolOr returns bool value by the given keys path.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// defaultValue is returned for non-existing keys path or for invalid value type.
+func (v *Value) GetBoolOr(defaultValue bool, keys ...string) bool {
+	v = v.Get(keys...)
+	if v == nil {
+		return defaultValue
+	}
+	switch v.t {
+	case TypeTrue:
+		return true
+	case TypeFalse:
+		return false
+	default:
+		return defaultValue
+	}
+}
+
 // Object returns the underlying JSON object for the v.
 //
 // The returned object is valid until Parse is called on the Parser returned v.
@@ -1007,6 +1348,57 @@ func (v *Value) Array() ([]*Value, error) {
 	return v.a, nil
 }
 
+// At returns the array item at idx position.
+//
+// Unlike Get(strconv.Itoa(idx)), At doesn't allocate the index string
+// and returns *TypeError if v isn't an array and *IndexError
+// if idx is out of range.
+//
+// The returned value is valid until Parse is called on the Parser returned v.
+func (v *Value) At(idx int) (*Value, error) {
+	if v.t != TypeArray {
+		return nil, &TypeError{
+			Expected: TypeArray,
+			Actual:   v.Type(),
+		}
+	}
+	if idx < 0 || idx >= len(v.a) {
+		return nil, &IndexError{
+			Index: idx,
+			Len:   len(v.a),
+		}
+	}
+	return v.a[idx], nil
+}
+
+// TypeError is returned when the value has unexpected type.
+type TypeError struct {
+	// Expected is the expected value type.
+	Expected Type
+
+	// Actual is the actual value type.
+	Actual Type
+}
+
+// Error implements error interface.
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("value doesn't contain %s; it contains %s", e.Expected, e.Actual)
+}
+
+// IndexError is returned when the array index is out of range.
+type IndexError struct {
+	// Index is the requested index.
+	Index int
+
+	// Len is the array length.
+	Len int
+}
+
+// Error implements error interface.
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d is out of range [0..%d)", e.Index, e.Len)
+}
+
 // StringBytes returns the underlying JSON string for the v.
 //
 // The returned string is valid until Parse is called on the Parser returned v.
@@ -1029,6 +1421,16 @@ func (v *Value) Float64() (float64, error) {
 	return fastfloat.Parse(v.s)
 }
 
+// Float32 returns the underlying JSON number for the v as float32.
+//
+// Use GetFloat32 if you don't need error handling.
+func (v *Value) Float32() (float32, error) {
+	if v.Type() != TypeNumber {
+		return 0, fmt.Errorf("value doesn't contain number; it contains %s", v.Type())
+	}
+	return fastfloat.ParseFloat32(v.s)
+}
+
 // Int returns the underlying JSON int for the v.
 //
 // Use GetInt if you don't need error handling.
@@ -1085,6 +1487,121 @@ func (v *Value) Uint64() (uint64, error) {
 	return fastfloat.ParseUint64(v.s)
 }
 
+// BigInt returns the underlying JSON integer for the v.
+//
+// Unlike Int64 and Uint64, BigInt doesn't lose precision for integers
+// exceeding int64 and uint64 ranges.
+func (v *Value) BigInt() (*big.Int, error) {
+	if v.Type() != TypeNumber {
+		return nil, fmt.Errorf("value doesn't contain number; it contains %s", v.Type())
+	}
+	n, ok := new(big.Int).SetString(v.s, 10)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse big int from %q", v.s)
+	}
+	return n, nil
+}
+
+// BigFloat returns the underlying JSON number for the v.
+//
+// The returned number has enough precision for holding all the digits
+// from the original JSON number, so it doesn't lose precision like Float64.
+func (v *Value) BigFloat() (*big.Float, error) {
+	if v.Type() != TypeNumber {
+		return nil, fmt.Errorf("value doesn't contain number; it contains %s", v.Type())
+	}
+	// Every decimal digit requires less than 4 bits.
+	prec := uint(len(v.s))*4 + 64
+	f, _, err := big.ParseFloat(v.s, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse big float from %q: %s", v.s, err)
+	}
+	return f, nil
+}
+
+// RawNumber returns the original JSON number literal for the v.
+//
+// An empty string is returned if the v doesn't contain number.
+//
+// The returned string is valid until Parse is called on the Parser returned v.
+func (v *Value) RawNumber() string {
+	if v.t != TypeNumber {
+		return ""
+	}
+	return v.s
+}
+
+// Decimal returns the underlying JSON number for the v
+// in the form mantissa*10^exp without rounding.
+//
+// For instance, 12.50 is returned as (1250, -2). This allows working with
+// decimal fractions such as money amounts without float64 rounding errors.
+//
+// An error is returned if the mantissa doesn't fit int64.
+func (v *Value) Decimal() (int64, int32, error) {
+	if v.Type() != TypeNumber {
+		return 0, 0, fmt.Errorf("value doesn't contain number; it contains %s", v.Type())
+	}
+	return parseDecimal(v.s)
+}
+
+func parseDecimal(s string) (int64, int32, error) {
+	ss := s
+	minus := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		minus = s[0] == '-'
+		s = s[1:]
+	}
+	var mantissa uint64
+	exp := int64(0)
+	digits := 0
+	fraction := false
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		if ch == '.' && !fraction {
+			fraction = true
+			i++
+			continue
+		}
+		if ch < '0' || ch > '9' {
+			break
+		}
+		if mantissa > (1<<63-uint64(ch-'0'))/10 {
+			return 0, 0, fmt.Errorf("mantissa of the number %q doesn't fit int64", ss)
+		}
+		mantissa = mantissa*10 + uint64(ch-'0')
+		if fraction {
+			exp--
+		}
+		digits++
+		i++
+	}
+	if digits == 0 {
+		return 0, 0, fmt.Errorf("cannot parse decimal from %q", ss)
+	}
+	if i < len(s) {
+		if s[i] != 'e' && s[i] != 'E' {
+			return 0, 0, fmt.Errorf("cannot parse decimal from %q", ss)
+		}
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cannot parse exponent of the number %q: %s", ss, err)
+		}
+		exp += e
+	}
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return 0, 0, fmt.Errorf("exponent of the number %q doesn't fit int32", ss)
+	}
+	if minus {
+		return -int64(mantissa), int32(exp), nil
+	}
+	if mantissa > math.MaxInt64 {
+		return 0, 0, fmt.Errorf("mantissa of the number %q doesn't fit int64", ss)
+	}
+	return int64(mantissa), int32(exp), nil
+}
+
 // Bool returns the underlying JSON bool for the v.
 //
 // Use GetBool if you don't need error handling.
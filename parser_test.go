@@ -1275,3 +1275,360 @@ func testParseGetSerial(s string) error {
 	}
 	return nil
 }
+
+func TestValueBigNumbers(t *testing.T) {
+	v := MustParse(`[123, -340282366920938463463374607431768211455, 12345678901234567890123.25e2, "foo", 1.5]`)
+	a := v.GetArray()
+
+	n, err := a[0].BigInt()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n.String() != "123" {
+		t.Fatalf("unexpected big int; got %s; want %s", n, "123")
+	}
+
+	n, err = a[1].BigInt()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n.String() != "-340282366920938463463374607431768211455" {
+		t.Fatalf("unexpected big int; got %s; want %s", n, "-340282366920938463463374607431768211455")
+	}
+
+	f, err := a[2].BigFloat()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := f.Text('f', 0); s != "1234567890123456789012325" {
+		t.Fatalf("unexpected big float; got %s; want %s", s, "1234567890123456789012325")
+	}
+
+	if _, err := a[3].BigInt(); err == nil {
+		t.Fatalf("expecting non-nil error when trying to obtain big int from string")
+	}
+	if _, err := a[3].BigFloat(); err == nil {
+		t.Fatalf("expecting non-nil error when trying to obtain big float from string")
+	}
+	if _, err := a[4].BigInt(); err == nil {
+		t.Fatalf("expecting non-nil error when trying to obtain big int from fractional number")
+	}
+}
+
+func TestValueDecimal(t *testing.T) {
+	f := func(s string, mantissaExpected int64, expExpected int32) {
+		t.Helper()
+		v := MustParse(s)
+		if raw := v.RawNumber(); raw != s {
+			t.Fatalf("unexpected raw number; got %q; want %q", raw, s)
+		}
+		mantissa, exp, err := v.Decimal()
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", s, err)
+		}
+		if mantissa != mantissaExpected || exp != expExpected {
+			t.Fatalf("unexpected decimal for %q; got (%d, %d); want (%d, %d)", s, mantissa, exp, mantissaExpected, expExpected)
+		}
+	}
+	f("0", 0, 0)
+	f("123", 123, 0)
+	f("-12.50", -1250, -2)
+	f("0.001", 1, -3)
+	f("1.5e3", 15, 2)
+	f("25E-2", 25, -2)
+	f("9223372036854775807", 9223372036854775807, 0)
+	f("-9223372036854775808", -9223372036854775808, 0)
+	f("-922337203685477580.8", -9223372036854775808, -1)
+
+	fErr := func(s string) {
+		t.Helper()
+		v := MustParse(s)
+		if _, _, err := v.Decimal(); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+	}
+	fErr("9223372036854775808")
+	fErr("123456789012345678901234567890")
+	fErr("1e9999999999")
+	fErr("NaN")
+	fErr("-Inf")
+	fErr(`"123"`)
+
+	if raw := MustParse(`"123"`).RawNumber(); raw != "" {
+		t.Fatalf("unexpected raw number for string; got %q; want empty string", raw)
+	}
+}
+
+func TestValueFloat32(t *testing.T) {
+	v := MustParse(`{"a":[0.1, 1.5e3, "x"]}`)
+	f := v.GetFloat32("a", "0")
+	if f != 0.1 {
+		t.Fatalf("unexpected float32; got %v; want %v", f, float32(0.1))
+	}
+	f, err := v.Get("a", "1").Float32()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f != 1500 {
+		t.Fatalf("unexpected float32; got %v; want %v", f, float32(1500))
+	}
+	if f := v.GetFloat32("a", "2"); f != 0 {
+		t.Fatalf("unexpected float32 for string; got %v; want 0", f)
+	}
+	if f := v.GetFloat32("missing"); f != 0 {
+		t.Fatalf("unexpected float32 for missing key; got %v; want 0", f)
+	}
+	if _, err := v.Get("a", "2").Float32(); err == nil {
+		t.Fatalf("expecting non-nil error when trying to obtain float32 from string")
+	}
+}
+
+func TestValueGetOr(t *testing.T) {
+	v := MustParse(`{"zero":0,"n":123,"f":1.5,"empty":"","s":"foo","no":false,"yes":true,"null":null,"big":12345678901234567890}`)
+
+	if n := v.GetIntOr(42, "zero"); n != 0 {
+		t.Fatalf("unexpected int; got %d; want %d", n, 0)
+	}
+	if n := v.GetIntOr(42, "n"); n != 123 {
+		t.Fatalf("unexpected int; got %d; want %d", n, 123)
+	}
+	if n := v.GetIntOr(42, "missing"); n != 42 {
+		t.Fatalf("unexpected int; got %d; want %d", n, 42)
+	}
+	if n := v.GetIntOr(42, "s"); n != 42 {
+		t.Fatalf("unexpected int; got %d; want %d", n, 42)
+	}
+	if n := v.GetInt64Or(-1, "big"); n != -1 {
+		t.Fatalf("unexpected int64; got %d; want %d", n, -1)
+	}
+	if n := v.GetInt64Or(-1, "n"); n != 123 {
+		t.Fatalf("unexpected int64; got %d; want %d", n, 123)
+	}
+	if f := v.GetFloat64Or(2.5, "f"); f != 1.5 {
+		t.Fatalf("unexpected float64; got %v; want %v", f, 1.5)
+	}
+	if f := v.GetFloat64Or(2.5, "null"); f != 2.5 {
+		t.Fatalf("unexpected float64; got %v; want %v", f, 2.5)
+	}
+	if s := v.GetStringOr("def", "empty"); s != "" {
+		t.Fatalf("unexpected string; got %q; want %q", s, "")
+	}
+	if s := v.GetStringOr("def", "s"); s != "foo" {
+		t.Fatalf("unexpected string; got %q; want %q", s, "foo")
+	}
+	if s := v.GetStringOr("def", "n"); s != "def" {
+		t.Fatalf("unexpected string; got %q; want %q", s, "def")
+	}
+	if b := v.GetBoolOr(true, "no"); b {
+		t.Fatalf("unexpected bool; got %v; want %v", b, false)
+	}
+	if b := v.GetBoolOr(false, "yes"); !b {
+		t.Fatalf("unexpected bool; got %v; want %v", b, true)
+	}
+	if b := v.GetBoolOr(true, "missing"); !b {
+		t.Fatalf("unexpected bool; got %v; want %v", b, true)
+	}
+	if b := v.GetBoolOr(true, "null"); !b {
+		t.Fatalf("unexpected bool; got %v; want %v", b, true)
+	}
+}
+
+func TestValueTypeOf(t *testing.T) {
+	v := MustParse(`{"a":null,"b":"foo","c":[1,{"d":true}]}`)
+	f := func(typeExpected Type, keys ...string) {
+		t.Helper()
+		typ := v.TypeOf(keys...)
+		if typ != typeExpected {
+			t.Fatalf("unexpected type for %q; got %s; want %s", keys, typ, typeExpected)
+		}
+	}
+	f(TypeObject)
+	f(TypeNull, "a")
+	f(TypeString, "b")
+	f(TypeArray, "c")
+	f(TypeNumber, "c", "0")
+	f(TypeTrue, "c", "1", "d")
+	f(TypeNotExist, "x")
+	f(TypeNotExist, "a", "x")
+	f(TypeNotExist, "c", "2")
+
+	var vNil *Value
+	if typ := vNil.TypeOf(); typ != TypeNotExist {
+		t.Fatalf("unexpected type for nil value; got %s; want %s", typ, TypeNotExist)
+	}
+	if s := TypeNotExist.String(); s != "notexist" {
+		t.Fatalf("unexpected string representation for TypeNotExist; got %q; want %q", s, "notexist")
+	}
+}
+
+func TestValueLen(t *testing.T) {
+	f := func(s string, nExpected int) {
+		t.Helper()
+		v := MustParse(s)
+		n := v.Len()
+		if n != nExpected {
+			t.Fatalf("unexpected length for %s; got %d; want %d", s, n, nExpected)
+		}
+	}
+	f(`[]`, 0)
+	f(`[1,[2,3],{}]`, 3)
+	f(`{}`, 0)
+	f(`{"a":1,"b":[1,2]}`, 2)
+	f(`""`, 0)
+	f(`"foo"`, 3)
+	f(`"a\nb"`, 3)
+	f(`"привет"`, 12)
+	f(`123`, 0)
+	f(`true`, 0)
+	f(`null`, 0)
+
+	var vNil *Value
+	if n := vNil.Len(); n != 0 {
+		t.Fatalf("unexpected length for nil value; got %d; want 0", n)
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	v := MustParse(`[1,"foo",{"a":2}]`)
+	for i, sExpected := range []string{`1`, `"foo"`, `{"a":2}`} {
+		vv, err := v.At(i)
+		if err != nil {
+			t.Fatalf("unexpected error for index %d: %s", i, err)
+		}
+		if s := vv.String(); s != sExpected {
+			t.Fatalf("unexpected value at index %d; got %s; want %s", i, s, sExpected)
+		}
+	}
+
+	for _, idx := range []int{-1, 3, 100} {
+		_, err := v.At(idx)
+		ie, ok := err.(*IndexError)
+		if !ok {
+			t.Fatalf("expecting *IndexError for index %d; got %v", idx, err)
+		}
+		if ie.Index != idx || ie.Len != 3 {
+			t.Fatalf("unexpected IndexError for index %d: %+v", idx, ie)
+		}
+	}
+
+	_, err := v.Get("2").At(0)
+	te, ok := err.(*TypeError)
+	if !ok {
+		t.Fatalf("expecting *TypeError; got %v", err)
+	}
+	if te.Expected != TypeArray || te.Actual != TypeObject {
+		t.Fatalf("unexpected TypeError: %+v", te)
+	}
+	errExpected := "value doesn't contain array; it contains object"
+	if te.Error() != errExpected {
+		t.Fatalf("unexpected error message; got %q; want %q", te.Error(), errExpected)
+	}
+}
+
+func TestObjectVisitErr(t *testing.T) {
+	o := MustParse(`{"a":1,"b\n":"x","c":3}`).GetObject()
+
+	var keys []string
+	err := o.VisitErr(func(k []byte, v *Value) error {
+		keys = append(keys, string(k))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Join(keys, ",") != "a,b\n,c" {
+		t.Fatalf("unexpected keys visited: %q", keys)
+	}
+
+	keys = keys[:0]
+	err = o.VisitErr(func(k []byte, v *Value) error {
+		keys = append(keys, string(k))
+		if _, err := v.Int(); err != nil {
+			return fmt.Errorf("unexpected value for key %q: %s", k, err)
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if len(keys) != 2 {
+		t.Fatalf("iteration must stop on the first error; visited keys: %q", keys)
+	}
+
+	var oNil *Object
+	if err := oNil.VisitErr(func(k []byte, v *Value) error {
+		return fmt.Errorf("unexpected visit call; k=%q; v=%s", k, v)
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestValueGetRaw(t *testing.T) {
+	v := MustParse(`{"a":{"b":[1, "x\ny", {"c" : -1.5e3}]},"n":null}`)
+	f := func(keys []string, resultExpected string) {
+		t.Helper()
+		result := v.GetRaw(keys...)
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected GetRaw result for %q; got %s; want %s", keys, result, resultExpected)
+		}
+		result = v.AppendRaw([]byte("prefix:"), keys...)
+		if string(result) != "prefix:"+resultExpected {
+			t.Fatalf("unexpected AppendRaw result for %q; got %s; want %s", keys, result, "prefix:"+resultExpected)
+		}
+	}
+	f(nil, `{"a":{"b":[1,"x\ny",{"c":-1.5e3}]},"n":null}`)
+	f([]string{"a", "b"}, `[1,"x\ny",{"c":-1.5e3}]`)
+	f([]string{"a", "b", "1"}, `"x\ny"`)
+	f([]string{"a", "b", "2", "c"}, `-1.5e3`)
+	f([]string{"n"}, `null`)
+	f([]string{"missing"}, ``)
+
+	if result := v.GetRaw("missing"); result != nil {
+		t.Fatalf("expecting nil result for missing key; got %q", result)
+	}
+}
+
+func TestValueMarshaledSize(t *testing.T) {
+	f := func(v *Value) {
+		t.Helper()
+		size := v.MarshaledSize()
+		b := v.MarshalTo(nil)
+		if len(b) > size {
+			t.Fatalf("MarshaledSize must be an upper bound; got %d; marshaled size %d for %s", size, len(b), b)
+		}
+		if cap(b) != size {
+			t.Fatalf("MarshalTo(nil) must allocate the buffer at once; got capacity %d; want %d", cap(b), size)
+		}
+	}
+
+	for _, s := range []string{
+		`1`,
+		`true`,
+		`false`,
+		`null`,
+		`"foo\nbar"`,
+		`{}`,
+		`[]`,
+		`{"a\tb":[1,"x\u0001y",{"c":null,"d":false}],"e":-1.5e3}`,
+	} {
+		v := MustParse(s)
+		f(v)
+		if size := v.MarshaledSize(); size != len(s) {
+			t.Fatalf("unexpected MarshaledSize for %s; got %d; want %d", s, size, len(s))
+		}
+
+		// Unescape strings and keys.
+		v.Walk(func(path []string, v *Value) bool {
+			v.Type()
+			return true
+		})
+		f(v)
+	}
+
+	var a Arena
+	v := a.NewObject()
+	v.Set("k\x00\"", a.NewStringCopy("\x01\xff😀 "))
+	v.Set("n", a.NewNumberFloat64(1.25))
+	v.SetRaw("raw", []byte(`{"x": [1]}`))
+	f(v)
+}
@@ -0,0 +1,421 @@
+package fastjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSpecialChars contains chars with special meaning in path strings.
+const pathSpecialChars = `.[]*#\`
+
+// PathEscape escapes key, so it may be used as a single key
+// in path strings such as "a.b[2].c".
+//
+// Chars '.', '[', ']', '*', '#' and '\' are prefixed with '\'.
+func PathEscape(key string) string {
+	if !strings.ContainsAny(key, pathSpecialChars) {
+		// Fast path - nothing to escape.
+		return key
+	}
+	b := make([]byte, 0, len(key)+4)
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(pathSpecialChars, key[i]) >= 0 {
+			b = append(b, '\\')
+		}
+		b = append(b, key[i])
+	}
+	return string(b)
+}
+
+// PathUnescape unescapes key escaped with PathEscape.
+func PathUnescape(key string) (string, error) {
+	n := strings.IndexByte(key, '\\')
+	if n < 0 {
+		// Fast path - nothing to unescape.
+		return key, nil
+	}
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		ch := key[i]
+		if ch == '\\' {
+			i++
+			if i >= len(key) {
+				return "", fmt.Errorf("missing char after trailing '\\' in %q", key)
+			}
+			ch = key[i]
+		}
+		b = append(b, ch)
+	}
+	return string(b), nil
+}
+
+// JoinPath returns path string for the given keys.
+//
+// Keys are escaped with PathEscape and joined with '.',
+// so SplitPath(JoinPath(keys...)) returns the original keys.
+func JoinPath(keys ...string) string {
+	var b []byte
+	for i, key := range keys {
+		if i > 0 {
+			b = append(b, '.')
+		}
+		b = append(b, PathEscape(key)...)
+	}
+	return string(b)
+}
+
+// SplitPath splits path string into keys suitable for passing to Get.
+//
+// Keys in the path are delimited by '.'. Array indexes may be written
+// either as ordinary keys ("a.2.b") or in brackets ("a[2].b").
+// Special chars in keys must be escaped with PathEscape.
+//
+// Paths with predicate segments such as "#(...)" cannot be split into
+// keys. Use GetPath for such paths.
+func SplitPath(path string) ([]string, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, seg := range segs {
+		if seg.pred != nil {
+			return nil, fmt.Errorf("predicate segments aren't supported by SplitPath in path %q", path)
+		}
+		keys = append(keys, seg.key)
+	}
+	return keys, nil
+}
+
+// pathSegment is a single segment of path string.
+type pathSegment struct {
+	// key is the segment key. It is empty for predicate segments.
+	key string
+
+	// pred is the predicate for "#(...)" segments.
+	pred *pathPredicate
+
+	// all is set for "#(...)#" segments, which match all the array items.
+	all bool
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	var segs []pathSegment
+	if len(path) == 0 {
+		return segs, nil
+	}
+	s := path
+	for {
+		var seg pathSegment
+		if s[0] == '[' {
+			n := strings.IndexByte(s, ']')
+			if n < 0 {
+				return nil, fmt.Errorf("missing ']' in path %q", path)
+			}
+			seg.key = s[1:n]
+			if _, err := strconv.ParseUint(seg.key, 10, 0); err != nil {
+				return nil, fmt.Errorf("invalid array index %q in path %q", seg.key, path)
+			}
+			s = s[n+1:]
+		} else if strings.HasPrefix(s, "#(") {
+			n := predicateEnd(s)
+			if n < 0 {
+				return nil, fmt.Errorf("missing ')' in path %q", path)
+			}
+			pred, err := parsePathPredicate(s[2:n])
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse predicate %q in path %q: %s", s[:n+1], path, err)
+			}
+			seg.pred = pred
+			s = s[n+1:]
+			if len(s) > 0 && s[0] == '#' {
+				seg.all = true
+				s = s[1:]
+			}
+			if len(s) > 0 && s[0] != '.' && s[0] != '[' {
+				return nil, fmt.Errorf("unexpected chars after predicate in path %q", path)
+			}
+		} else {
+			n := 0
+			for n < len(s) && s[n] != '.' && s[n] != '[' {
+				if s[n] == '\\' {
+					n++
+				} else if s[n] == ']' {
+					return nil, fmt.Errorf("unexpected ']' in path %q", path)
+				}
+				n++
+			}
+			if n > len(s) {
+				n = len(s)
+			}
+			k, err := PathUnescape(s[:n])
+			if err != nil {
+				return nil, fmt.Errorf("cannot unescape key in path %q: %s", path, err)
+			}
+			seg.key = k
+			s = s[n:]
+		}
+		segs = append(segs, seg)
+		if len(s) == 0 {
+			return segs, nil
+		}
+		if s[0] == '.' {
+			s = s[1:]
+			if len(s) == 0 {
+				return nil, fmt.Errorf("missing key after trailing '.' in path %q", path)
+			}
+		}
+	}
+}
+
+// GetPath returns value by the given path string such as "a.b[2].c".
+//
+// See SplitPath for the path syntax. Keys containing special chars
+// must be escaped with PathEscape.
+//
+// The path may contain predicate segments for selecting array items:
+//
+//   - "items.#(status==\"active\").id" selects "id" of the first item
+//     with "status" equal to "active".
+//   - "items.#(price>100)#.id" selects "id" of all the items with "price"
+//     greater than 100. The result is an array.
+//
+// Predicates have the form "#(path op value)", where path is a path
+// relative to the array item, op is one of ==, !=, <, <=, > or >=,
+// and value is a JSON string, number, true, false or null. An empty
+// path refers to the item itself. The "#(path)" predicate matches items
+// containing the given path.
+//
+// nil is returned for non-existing or invalid path.
+func (v *Value) GetPath(path string) *Value {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil
+	}
+	return v.getPath(segs)
+}
+
+func (v *Value) getPath(segs []pathSegment) *Value {
+	for i, seg := range segs {
+		if seg.pred == nil {
+			v = v.Get(seg.key)
+			if v == nil {
+				return nil
+			}
+			continue
+		}
+		if v.Type() != TypeArray {
+			return nil
+		}
+		if !seg.all {
+			var item *Value
+			for _, vv := range v.a {
+				if seg.pred.match(vv) {
+					item = vv
+					break
+				}
+			}
+			if item == nil {
+				return nil
+			}
+			v = item
+			continue
+		}
+
+		// Collect the remaining path values for all the matching items.
+		a := &Value{
+			t: TypeArray,
+		}
+		for _, vv := range v.a {
+			if !seg.pred.match(vv) {
+				continue
+			}
+			if vv = vv.getPath(segs[i+1:]); vv != nil {
+				a.a = append(a.a, vv)
+			}
+		}
+		return a
+	}
+	return v
+}
+
+// ExistsPath returns true if the field exists for the given path string.
+//
+// See GetPath for details.
+func (v *Value) ExistsPath(path string) bool {
+	return v.GetPath(path) != nil
+}
+
+// GetPathStringBytes returns string value by the given path string.
+//
+// See GetPath for details.
+//
+// nil is returned for non-existing path or for invalid value type.
+func (v *Value) GetPathStringBytes(path string) []byte {
+	return v.GetPath(path).GetStringBytes()
+}
+
+// GetPathInt returns int value by the given path string.
+//
+// See GetPath for details.
+//
+// 0 is returned for non-existing path or for invalid value type.
+func (v *Value) GetPathInt(path string) int {
+	return v.GetPath(path).GetInt()
+}
+
+// GetPathInt64 returns int64 value by the given path string.
+//
+// See GetPath for details.
+//
+// 0 is returned for non-existing path or for invalid value type.
+func (v *Value) GetPathInt64(path string) int64 {
+	return v.GetPath(path).GetInt64()
+}
+
+// GetPathFloat64 returns float64 value by the given path string.
+//
+// See GetPath for details.
+//
+// 0 is returned for non-existing path or for invalid value type.
+func (v *Value) GetPathFloat64(path string) float64 {
+	return v.GetPath(path).GetFloat64()
+}
+
+// GetPathBool returns bool value by the given path string.
+//
+// See GetPath for details.
+//
+// false is returned for non-existing path or for invalid value type.
+func (v *Value) GetPathBool(path string) bool {
+	return v.GetPath(path).GetBool()
+}
+
+// pathPredicate is a predicate for "#(...)" path segments.
+type pathPredicate struct {
+	// path is the path relative to the array item.
+	path []pathSegment
+
+	// op is the comparison operator. It is empty for existence checks.
+	op string
+
+	// value is the value to compare with.
+	value *Value
+}
+
+// predicateEnd returns the index of ')' closing "#(" at the start of s.
+//
+// -1 is returned if the closing ')' is missing.
+func predicateEnd(s string) int {
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			// Skip the quoted string.
+			_, tail, err := parseRawString(s[i+1:])
+			if err != nil {
+				return -1
+			}
+			i = len(s) - len(tail) - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// pathPredicateOps contains supported predicate operators.
+// Longer operators must go first.
+var pathPredicateOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parsePathPredicate(s string) (*pathPredicate, error) {
+	// Find the operator outside nested predicates.
+	n := len(s)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], "#(") {
+			m := predicateEnd(s[i:])
+			if m < 0 {
+				return nil, fmt.Errorf("missing ')' in nested predicate")
+			}
+			i += m
+			continue
+		}
+		if strings.IndexByte("=!<>", s[i]) >= 0 {
+			n = i
+			break
+		}
+	}
+
+	var pred pathPredicate
+	path, err := parsePath(strings.TrimSpace(s[:n]))
+	if err != nil {
+		return nil, err
+	}
+	pred.path = path
+	if n == len(s) {
+		// Existence check.
+		return &pred, nil
+	}
+	for _, op := range pathPredicateOps {
+		if strings.HasPrefix(s[n:], op) {
+			pred.op = op
+			break
+		}
+	}
+	if pred.op == "" {
+		return nil, fmt.Errorf("unsupported operator at %q", s[n:])
+	}
+	value, err := Parse(s[n+len(pred.op):])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse value to compare with: %s", err)
+	}
+	switch value.Type() {
+	case TypeArray, TypeObject:
+		return nil, fmt.Errorf("value to compare with must be a string, number, true, false or null; got %s", value.Type())
+	}
+	pred.value = value
+	return &pred, nil
+}
+
+// match returns true if the array item v matches pred.
+func (pred *pathPredicate) match(v *Value) bool {
+	v = v.getPath(pred.path)
+	if v == nil {
+		return false
+	}
+	if pred.op == "" {
+		return true
+	}
+	t := v.Type()
+	sameType := t == pred.value.Type()
+	if pred.op == "==" || pred.op == "!=" {
+		equal := sameType && t != TypeArray && t != TypeObject && compareValues(v, pred.value) == 0
+		return equal == (pred.op == "==")
+	}
+	if !sameType || (t != TypeNumber && t != TypeString) {
+		return false
+	}
+	n := compareValues(v, pred.value)
+	switch pred.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	default:
+		return n >= 0
+	}
+}
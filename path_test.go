@@ -0,0 +1,196 @@
+package fastjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathEscapeUnescape(t *testing.T) {
+	f := func(key, escapedExpected string) {
+		t.Helper()
+		escaped := PathEscape(key)
+		if escaped != escapedExpected {
+			t.Fatalf("unexpected escaped key for %q; got %q; want %q", key, escaped, escapedExpected)
+		}
+		unescaped, err := PathUnescape(escaped)
+		if err != nil {
+			t.Fatalf("unexpected error when unescaping %q: %s", escaped, err)
+		}
+		if unescaped != key {
+			t.Fatalf("unexpected unescaped key for %q; got %q; want %q", escaped, unescaped, key)
+		}
+	}
+	f("", "")
+	f("foo", "foo")
+	f("a.b", `a\.b`)
+	f("x[0]", `x\[0\]`)
+	f("*", `\*`)
+	f(`a\b`, `a\\b`)
+
+	if _, err := PathUnescape(`foo\`); err == nil {
+		t.Fatalf("expecting non-nil error for trailing backslash")
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	f := func(path string, keysExpected []string) {
+		t.Helper()
+		keys, err := SplitPath(path)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", path, err)
+		}
+		if !reflect.DeepEqual(keys, keysExpected) {
+			t.Fatalf("unexpected keys for %q; got %q; want %q", path, keys, keysExpected)
+		}
+	}
+	f("", nil)
+	f("foo", []string{"foo"})
+	f("a.b.c", []string{"a", "b", "c"})
+	f("a.2.c", []string{"a", "2", "c"})
+	f("a[2].c", []string{"a", "2", "c"})
+	f("a[2][3]", []string{"a", "2", "3"})
+	f("[0].a", []string{"0", "a"})
+	f(`a\.b.c`, []string{"a.b", "c"})
+	f(`x\[1\]`, []string{"x[1]"})
+
+	fErr := func(path string) {
+		t.Helper()
+		if _, err := SplitPath(path); err == nil {
+			t.Fatalf("expecting non-nil error for %q", path)
+		}
+	}
+	fErr("a.")
+	fErr("a[1")
+	fErr("a[x]")
+	fErr("a]")
+	fErr(`a\`)
+}
+
+func TestJoinPath(t *testing.T) {
+	keys := []string{"a.b", "x[1]", "2", `c\d`, "*"}
+	path := JoinPath(keys...)
+	pathExpected := `a\.b.x\[1\].2.c\\d.\*`
+	if path != pathExpected {
+		t.Fatalf("unexpected path; got %q; want %q", path, pathExpected)
+	}
+	result, err := SplitPath(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(result, keys) {
+		t.Fatalf("unexpected keys; got %q; want %q", result, keys)
+	}
+}
+
+func TestValueGetPath(t *testing.T) {
+	v := MustParse(`{"a":{"b":[1,{"c":"foo"},{"x.y":{"z[0]":2.5}}]},"t":true,"n":-12345678901}`)
+
+	f := func(path, resultExpected string) {
+		t.Helper()
+		var result string
+		if vv := v.GetPath(path); vv != nil {
+			result = vv.String()
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected value for %q; got %q; want %q", path, result, resultExpected)
+		}
+		if exists := v.ExistsPath(path); exists != (resultExpected != "") {
+			t.Fatalf("unexpected ExistsPath result for %q: %v", path, exists)
+		}
+	}
+	f("", v.String())
+	f("a.b.0", `1`)
+	f("a.b[0]", `1`)
+	f("a.b.1.c", `"foo"`)
+	f(`a.b[2].x\.y.z\[0\]`, `2.5`)
+	f("a.b.3", ``)
+	f("a.x", ``)
+	f("a.", ``)
+	f("a[x]", ``)
+
+	if s := v.GetPathStringBytes("a.b[1].c"); string(s) != "foo" {
+		t.Fatalf("unexpected string; got %q; want %q", s, "foo")
+	}
+	if s := v.GetPathStringBytes("a.b[0]"); s != nil {
+		t.Fatalf("expecting nil string; got %q", s)
+	}
+	if n := v.GetPathInt("a.b.0"); n != 1 {
+		t.Fatalf("unexpected int; got %d; want 1", n)
+	}
+	if n := v.GetPathInt64("n"); n != -12345678901 {
+		t.Fatalf("unexpected int64; got %d; want -12345678901", n)
+	}
+	if f := v.GetPathFloat64(`a.b.2.x\.y.z\[0\]`); f != 2.5 {
+		t.Fatalf("unexpected float64; got %v; want 2.5", f)
+	}
+	if !v.GetPathBool("t") {
+		t.Fatalf("expecting true")
+	}
+	if v.GetPathBool("a") {
+		t.Fatalf("expecting false")
+	}
+}
+
+func TestValueGetPathPredicates(t *testing.T) {
+	v := MustParse(`{"items":[
+		{"id":1,"status":"active","price":50,"tags":["a","b"],"meta":{"x.y":true}},
+		{"id":2,"status":"deleted","price":150,"tags":[]},
+		{"id":3,"status":"active","price":200.5,"tags":["b"],"owner":null},
+		{"id":4,"status":"a\"b","price":"100"}
+	],"nums":[3,1,4,1,5]}`)
+
+	f := func(path, resultExpected string) {
+		t.Helper()
+		var result string
+		if vv := v.GetPath(path); vv != nil {
+			result = vv.String()
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected value for %q; got %q; want %q", path, result, resultExpected)
+		}
+	}
+
+	// The first matching item.
+	f(`items.#(status=="active").id`, `1`)
+	f(`items.#(status == "deleted").id`, `2`)
+	f(`items.#(status!="active").id`, `2`)
+	f(`items.#(price>100).id`, `2`)
+	f(`items.#(price>=200.5).id`, `3`)
+	f(`items.#(price<100)`, `{"id":1,"status":"active","price":50,"tags":["a","b"],"meta":{"x.y":true}}`)
+	f(`items.#(status=="a\"b").id`, `4`)
+	f(`items.#(owner==null).id`, `3`)
+	f(`items.#(owner).id`, `3`)
+	f(`items.#(meta.x\.y==true).id`, `1`)
+	f(`items.#(tags.1=="b").id`, `1`)
+	f(`items.#(tags.#(=="b")).id`, `1`)
+	f(`items.#(status=="missing").id`, ``)
+	f(`items[2].#(==1)`, ``)
+	f(`nums.#(>3)`, `4`)
+
+	// All the matching items.
+	f(`items.#(status=="active")#.id`, `[1,3]`)
+	f(`items.#(price>100)#.id`, `[2,3]`)
+	f(`items.#(price>"1")#.id`, `[4]`)
+	f(`items.#(tags.#(=="b"))#.id`, `[1,3]`)
+	f(`items.#(status=="missing")#.id`, `[]`)
+	f(`items.#(id>0)#.owner`, `[null]`)
+	f(`items.#(id>0)#.tags[0]`, `["a","b"]`)
+	f(`nums.#(<4)#`, `[3,1,1]`)
+
+	// Invalid predicates.
+	f(`items.#(status=="active"`, ``)
+	f(`items.#(status~"active")`, ``)
+	f(`items.#(status==active)`, ``)
+	f(`items.#(status==[1])`, ``)
+	f(`items.#(id==1)x`, ``)
+	f(`nums.0.#(==3)`, ``)
+
+	// Predicates aren't supported by SplitPath.
+	if _, err := SplitPath(`items.#(id==1).id`); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	// Escaped '#' is an ordinary key.
+	v = MustParse(`{"#(id==1)":2}`)
+	f(PathEscape("#(id==1)"), `2`)
+}
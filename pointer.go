@@ -0,0 +1,63 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePointer parses JSON Pointer from RFC 6901 into keys,
+// which may be passed to Get.
+//
+// Empty pointer refers to the whole document, so it results in nil keys.
+func ParsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("JSON pointer must start with '/'; got %q", pointer)
+	}
+	keys := strings.Split(pointer[1:], "/")
+	for i, key := range keys {
+		if strings.IndexByte(key, '~') < 0 {
+			continue
+		}
+		b := make([]byte, 0, len(key))
+		for j := 0; j < len(key); j++ {
+			ch := key[j]
+			if ch == '~' {
+				j++
+				if j >= len(key) || (key[j] != '0' && key[j] != '1') {
+					return nil, fmt.Errorf("invalid escape sequence in JSON pointer %q; '~' must be followed by '0' or '1'", pointer)
+				}
+				ch = '~'
+				if key[j] == '1' {
+					ch = '/'
+				}
+			}
+			b = append(b, ch)
+		}
+		keys[i] = string(b)
+	}
+	return keys, nil
+}
+
+// FormatPointer returns JSON Pointer from RFC 6901 for the given keys.
+//
+// ParsePointer(FormatPointer(keys...)) returns the original keys.
+func FormatPointer(keys ...string) string {
+	var b []byte
+	for _, key := range keys {
+		b = append(b, '/')
+		for i := 0; i < len(key); i++ {
+			switch key[i] {
+			case '~':
+				b = append(b, "~0"...)
+			case '/':
+				b = append(b, "~1"...)
+			default:
+				b = append(b, key[i])
+			}
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,48 @@
+package fastjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePointerSuccess(t *testing.T) {
+	f := func(pointer string, keysExpected []string) {
+		t.Helper()
+		keys, err := ParsePointer(pointer)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", pointer, err)
+		}
+		if !reflect.DeepEqual(keys, keysExpected) {
+			t.Fatalf("unexpected keys for %q; got %q; want %q", pointer, keys, keysExpected)
+		}
+		if s := FormatPointer(keys...); s != pointer {
+			t.Fatalf("unexpected FormatPointer result; got %q; want %q", s, pointer)
+		}
+	}
+
+	// Test cases from RFC 6901 section 5.
+	f(``, nil)
+	f(`/foo`, []string{"foo"})
+	f(`/foo/0`, []string{"foo", "0"})
+	f(`/`, []string{""})
+	f(`/a~1b`, []string{"a/b"})
+	f(`/c%d`, []string{"c%d"})
+	f(`/m~0n`, []string{"m~n"})
+	f(`/~01`, []string{"~1"})
+	f(`/a//b`, []string{"a", "", "b"})
+}
+
+func TestParsePointerFailure(t *testing.T) {
+	f := func(pointer string) {
+		t.Helper()
+		keys, err := ParsePointer(pointer)
+		if err == nil {
+			t.Fatalf("expecting non-nil error when parsing %q; got keys %q", pointer, keys)
+		}
+	}
+
+	f(`foo`)
+	f(`/foo~`)
+	f(`/foo~2`)
+	f(`/a/~x/b`)
+}
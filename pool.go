@@ -2,10 +2,23 @@ package fastjson
 
 import (
 	"sync"
+	"unsafe"
 )
 
 // ParserPool may be used for pooling Parsers for similarly typed JSONs.
 type ParserPool struct {
+	// MaxRetainedSize is the maximum size in bytes of the memory retained
+	// by a Parser returned to the pool via Put.
+	//
+	// Parsers exceeding the limit are dropped instead of pooling,
+	// so occasional huge JSONs don't pin huge buffers in the pool.
+	//
+	// The memory retained by arrays and objects isn't accounted,
+	// since it would require visiting all the retained values on every Put.
+	//
+	// There is no limit if MaxRetainedSize is zero.
+	MaxRetainedSize int
+
 	pool sync.Pool
 }
 
@@ -25,11 +38,26 @@ func (pp *ParserPool) Get() *Parser {
 // p and objects recursively returned from p cannot be used after p
 // is put into pp.
 func (pp *ParserPool) Put(p *Parser) {
+	if pp.MaxRetainedSize > 0 && cap(p.b)+p.c.size() > pp.MaxRetainedSize {
+		return
+	}
+	p.SetShape(nil)
 	pp.pool.Put(p)
 }
 
 // ArenaPool may be used for pooling Arenas for similarly typed JSONs.
 type ArenaPool struct {
+	// MaxRetainedSize is the maximum size in bytes of the memory retained
+	// by an Arena returned to the pool via Put.
+	//
+	// Arenas exceeding the limit are dropped instead of pooling.
+	//
+	// The memory retained by arrays and objects isn't accounted,
+	// since it would require visiting all the retained values on every Put.
+	//
+	// There is no limit if MaxRetainedSize is zero.
+	MaxRetainedSize int
+
 	pool sync.Pool
 }
 
@@ -48,5 +76,59 @@ func (ap *ArenaPool) Get() *Arena {
 //
 // a and objects created by a cannot be used after a is put into ap.
 func (ap *ArenaPool) Put(a *Arena) {
+	if ap.MaxRetainedSize > 0 && cap(a.b)+a.c.size() > ap.MaxRetainedSize {
+		return
+	}
 	ap.pool.Put(a)
 }
+
+// ScannerPool may be used for pooling Scanners for similarly typed JSON streams.
+type ScannerPool struct {
+	// MaxRetainedSize is the maximum size in bytes of the memory retained
+	// by a Scanner returned to the pool via Put.
+	//
+	// Scanners exceeding the limit are dropped instead of pooling.
+	//
+	// The memory retained by arrays and objects isn't accounted,
+	// since it would require visiting all the retained values on every Put.
+	//
+	// There is no limit if MaxRetainedSize is zero.
+	MaxRetainedSize int
+
+	pool sync.Pool
+}
+
+// Get returns a Scanner from sp.
+//
+// The Scanner must be Put to sp after use.
+func (sp *ScannerPool) Get() *Scanner {
+	v := sp.pool.Get()
+	if v == nil {
+		return &Scanner{}
+	}
+	return v.(*Scanner)
+}
+
+// Put returns sc to sp.
+//
+// sc and objects recursively returned from sc cannot be used after sc
+// is put into sp.
+func (sp *ScannerPool) Put(sc *Scanner) {
+	if sp.MaxRetainedSize > 0 && cap(sc.b)+sc.c.size() > sp.MaxRetainedSize {
+		return
+	}
+	sc.Init("")
+	sc.SetJSONSeq(false)
+	sc.SetDelimiters("")
+	sc.SetSplitFunc(nil)
+	sc.SetFollow(false)
+	sp.pool.Put(sc)
+}
+
+// size returns the size in bytes of the Values occupied by c.
+//
+// The memory retained by arrays and objects in c isn't included,
+// so size is O(1) and may be called on every Put.
+func (c *cache) size() int {
+	return cap(c.vs) * int(unsafe.Sizeof(Value{}))
+}
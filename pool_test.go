@@ -0,0 +1,72 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPoolMaxRetainedSize(t *testing.T) {
+	bigJSON := `[` + strings.Repeat(`"foobar",`, 1000) + `1]`
+
+	t.Run("parser", func(t *testing.T) {
+		pp := &ParserPool{
+			MaxRetainedSize: 1024,
+		}
+		p := pp.Get()
+		if _, err := p.Parse(bigJSON); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		pp.Put(p)
+		if p1 := pp.Get(); p1 == p {
+			t.Fatalf("the parser exceeding MaxRetainedSize mustn't be pooled")
+		}
+	})
+
+	t.Run("arena", func(t *testing.T) {
+		ap := &ArenaPool{
+			MaxRetainedSize: 1024,
+		}
+		a := ap.Get()
+		a.NewString(bigJSON)
+		ap.Put(a)
+		if a1 := ap.Get(); a1 == a {
+			t.Fatalf("the arena exceeding MaxRetainedSize mustn't be pooled")
+		}
+	})
+
+	t.Run("scanner", func(t *testing.T) {
+		sp := &ScannerPool{
+			MaxRetainedSize: 1024,
+		}
+		sc := sp.Get()
+		sc.Init(bigJSON)
+		for sc.Next() {
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sp.Put(sc)
+		if sc1 := sp.Get(); sc1 == sc {
+			t.Fatalf("the scanner exceeding MaxRetainedSize mustn't be pooled")
+		}
+	})
+}
+
+func TestScannerPool(t *testing.T) {
+	var sp ScannerPool
+	for i := 0; i < 10; i++ {
+		sc := sp.Get()
+		sc.Init(`{"a":1} [2]`)
+		n := 0
+		for sc.Next() {
+			n++
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if n != 2 {
+			t.Fatalf("unexpected number of scanned values; got %d; want %d", n, 2)
+		}
+		sp.Put(sc)
+	}
+}
@@ -0,0 +1,189 @@
+package fastjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Project returns a new value allocated in dst, which contains only
+// the given paths from v.
+//
+// The structure of v is preserved: objects contain only the selected
+// members in the original order. Decimal keys select array items, while
+// other keys are applied to every array item, so "items.id" selects
+// "id" members of all the "items". Array items without the selected
+// paths are dropped.
+//
+// See SplitPath for the path syntax. Invalid paths are ignored.
+// nil is returned if v contains none of the paths.
+//
+// The returned value doesn't refer to v and remains valid until dst.Reset call.
+func Project(v *Value, dst *Arena, paths ...string) *Value {
+	if v == nil {
+		return nil
+	}
+	var p Projection
+	for _, path := range paths {
+		keys, err := SplitPath(path)
+		if err != nil {
+			continue
+		}
+		p.root.add(keys)
+	}
+	return p.root.project(v, dst)
+}
+
+// Projection is a compiled set of paths for projecting many values.
+//
+// Projection may be obtained via CompileProjection. It may be used
+// concurrently from multiple goroutines.
+type Projection struct {
+	root projNode
+}
+
+// CompileProjection compiles the given paths into Projection.
+//
+// See SplitPath for the path syntax.
+func CompileProjection(paths ...string) (*Projection, error) {
+	var p Projection
+	for _, path := range paths {
+		keys, err := SplitPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compile projection path %q: %s", path, err)
+		}
+		p.root.add(keys)
+	}
+	return &p, nil
+}
+
+// Project returns a new value allocated in dst, which contains only
+// the paths from p found in v.
+//
+// See Project function for details.
+func (p *Projection) Project(v *Value, dst *Arena) *Value {
+	if v == nil {
+		return nil
+	}
+	return p.root.project(v, dst)
+}
+
+// projNode is a node in the tree of projected paths.
+type projNode struct {
+	// leaf is set if the whole value at the node must be projected.
+	leaf bool
+
+	// children contains child nodes by keys.
+	children map[string]*projNode
+}
+
+func (pn *projNode) add(keys []string) {
+	for _, key := range keys {
+		if pn.leaf {
+			// The whole value is already projected.
+			return
+		}
+		if pn.children == nil {
+			pn.children = make(map[string]*projNode)
+		}
+		child := pn.children[key]
+		if child == nil {
+			child = &projNode{}
+			pn.children[key] = child
+		}
+		pn = child
+	}
+	pn.leaf = true
+	pn.children = nil
+}
+
+func (pn *projNode) project(v *Value, dst *Arena) *Value {
+	if pn.leaf {
+		return v.CopyTo(dst)
+	}
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		var o *Value
+		for _, kv := range v.o.kvs {
+			child := pn.children[kv.k]
+			if child == nil {
+				continue
+			}
+			pv := child.project(kv.v, dst)
+			if pv == nil {
+				continue
+			}
+			if o == nil {
+				o = dst.NewObject()
+			}
+			kvp := o.o.getKV()
+			kvp.k, _ = dst.copyString(kv.k, false)
+			kvp.v = pv
+		}
+		if o != nil {
+			o.o.keysUnescaped = true
+			o.o.keysLoneSurrogates = v.o.keysLoneSurrogates
+		}
+		return o
+	case TypeArray:
+		// Non-index keys are applied to every array item.
+		var each *projNode
+		for key, child := range pn.children {
+			if isArrayIndex(key) {
+				continue
+			}
+			if each == nil {
+				each = &projNode{
+					children: make(map[string]*projNode),
+				}
+			}
+			each.children[key] = child
+		}
+		var a *Value
+		for i, item := range v.a {
+			child := pn.children[strconv.Itoa(i)]
+			if child == nil {
+				child = each
+			} else if each != nil {
+				child = mergeProjNodes(child, each)
+			}
+			if child == nil {
+				continue
+			}
+			pv := child.project(item, dst)
+			if pv == nil {
+				continue
+			}
+			if a == nil {
+				a = dst.NewArray()
+			}
+			a.a = append(a.a, pv)
+		}
+		return a
+	default:
+		return nil
+	}
+}
+
+// mergeProjNodes returns a node projecting paths from both x and y.
+func mergeProjNodes(x, y *projNode) *projNode {
+	if x.leaf {
+		return x
+	}
+	if y.leaf {
+		return y
+	}
+	pn := &projNode{
+		children: make(map[string]*projNode, len(x.children)+len(y.children)),
+	}
+	for key, child := range x.children {
+		pn.children[key] = child
+	}
+	for key, child := range y.children {
+		if c := pn.children[key]; c != nil {
+			child = mergeProjNodes(c, child)
+		}
+		pn.children[key] = child
+	}
+	return pn
+}
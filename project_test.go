@@ -0,0 +1,117 @@
+package fastjson
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	s := `{"id":1,"name":"foo","a\nb":"x","user":{"name":"bar","email":"e","roles":["a","b"]},"items":[{"id":10,"price":5,"tags":["x"]},{"price":6},{"id":12,"tags":[]}],"n":null}`
+
+	f := func(paths []string, resultExpected string) {
+		t.Helper()
+		var p Parser
+		v, err := p.Parse(s)
+		if err != nil {
+			t.Fatalf("cannot parse JSON: %s", err)
+		}
+		var a Arena
+		pv := Project(v, &a, paths...)
+
+		// The projected value mustn't refer to v.
+		if _, err := p.Parse(`{"xxxxxxxxxxxxxxxxxxxxxxxx":"yyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyy"}`); err != nil {
+			t.Fatalf("cannot parse JSON: %s", err)
+		}
+		var result string
+		if pv != nil {
+			result = pv.String()
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected projection for %q\ngot\n%s\nwant\n%s", paths, result, resultExpected)
+		}
+	}
+
+	f(nil, ``)
+	f([]string{"missing"}, ``)
+	f([]string{"id"}, `{"id":1}`)
+	f([]string{"name", "id"}, `{"id":1,"name":"foo"}`)
+	f([]string{"a\nb"}, `{"a\nb":"x"}`)
+	f([]string{"user.name", "user.roles.1"}, `{"user":{"name":"bar","roles":["b"]}}`)
+	f([]string{"user.name", "user"}, `{"user":{"name":"bar","email":"e","roles":["a","b"]}}`)
+	f([]string{"items.id"}, `{"items":[{"id":10},{"id":12}]}`)
+	f([]string{"items.id", "items.1.price"}, `{"items":[{"id":10},{"price":6},{"id":12}]}`)
+	f([]string{"items[0]", "items.id"}, `{"items":[{"id":10,"price":5,"tags":["x"]},{"id":12}]}`)
+	f([]string{"items.tags.0"}, `{"items":[{"tags":["x"]}]}`)
+	f([]string{"n", "id.x", "a."}, `{"n":null}`)
+	f([]string{""}, s)
+
+	var a Arena
+	if pv := Project(nil, &a, "a"); pv != nil {
+		t.Fatalf("unexpected projection for nil value: %s", pv)
+	}
+	if pv := Project(MustParse(`[1,2,3]`), &a, "2", "0"); pv.String() != `[1,3]` {
+		t.Fatalf("unexpected projection for array: %s", pv)
+	}
+}
+
+func TestCompileProjection(t *testing.T) {
+	p, err := CompileProjection("user.name", "items.id")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var a Arena
+	v := MustParse(`{"user":{"name":"x","age":3},"items":[{"id":1,"n":2}]}`)
+	if pv := p.Project(v, &a); pv.String() != `{"user":{"name":"x"},"items":[{"id":1}]}` {
+		t.Fatalf("unexpected projection: %s", pv)
+	}
+	if pv := p.Project(nil, &a); pv != nil {
+		t.Fatalf("unexpected projection for nil value: %s", pv)
+	}
+
+	if _, err := CompileProjection("a", "b["); err == nil {
+		t.Fatalf("expecting non-nil error for invalid path")
+	}
+}
+
+func TestProjectLines(t *testing.T) {
+	p, err := CompileProjection("id", "user.name")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	r := strings.NewReader(`{"id":1,"user":{"name":"a","age":2},"x":3}
+{"x":4}
+{"id":2}
+`)
+	var bb bytes.Buffer
+	if err := ProjectLines(&bb, r, p); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resultExpected := `{"id":1,"user":{"name":"a"}}
+{"id":2}
+`
+	if result := bb.String(); result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	// Read error
+	bb.Reset()
+	if err := ProjectLines(&bb, strings.NewReader(`{"id":1} {"id":`), p); err == nil {
+		t.Fatalf("expecting non-nil error for malformed input")
+	}
+
+	// Write error
+	w := &errorWriter{err: errors.New("write error")}
+	if err := ProjectLines(w, strings.NewReader(`{"id":1}`), p); err == nil {
+		t.Fatalf("expecting non-nil error for failed write")
+	}
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (w *errorWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
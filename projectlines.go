@@ -0,0 +1,68 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ProjectLines reads JSON lines ( http://jsonlines.org/ ) from r,
+// projects every record with p and writes the projected records
+// to w as JSON lines.
+//
+// Records containing none of p paths are dropped. The Scanner, Arena
+// and write buffer are pooled, so ProjectLines doesn't allocate memory
+// per record in the steady state.
+//
+// The first read or write error is returned.
+func ProjectLines(w io.Writer, r io.Reader, p *Projection) error {
+	sc := projectLinesScannerPool.Get()
+	defer projectLinesScannerPool.Put(sc)
+	a := projectLinesArenaPool.Get()
+	defer projectLinesArenaPool.Put(a)
+	lw := getProjectLinesWriter(w)
+	defer putProjectLinesWriter(lw)
+
+	sc.InitReader(r)
+	for sc.Next() {
+		pv := p.Project(sc.Value(), a)
+		if pv != nil {
+			if err := lw.Write(pv); err != nil {
+				return fmt.Errorf("cannot write projected record: %s", err)
+			}
+		}
+		a.Reset()
+	}
+	if err := sc.Error(); err != nil {
+		return fmt.Errorf("cannot read record: %s", err)
+	}
+	if err := lw.Flush(); err != nil {
+		return fmt.Errorf("cannot write projected record: %s", err)
+	}
+	return nil
+}
+
+var (
+	projectLinesScannerPool ScannerPool
+	projectLinesArenaPool   ArenaPool
+	projectLinesWriterPool  sync.Pool
+)
+
+func getProjectLinesWriter(w io.Writer) *LinesWriter {
+	v := projectLinesWriterPool.Get()
+	if v == nil {
+		return &LinesWriter{
+			FlushThreshold: 64 * 1024,
+			w:              w,
+		}
+	}
+	lw := v.(*LinesWriter)
+	lw.w = w
+	return lw
+}
+
+func putProjectLinesWriter(lw *LinesWriter) {
+	lw.w = nil
+	lw.buf = lw.buf[:0]
+	projectLinesWriterPool.Put(lw)
+}
@@ -0,0 +1,254 @@
+package fastjson
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// GetRawFromBytes returns raw JSON value for the field identified by keys path
+// in JSON data.
+//
+// Unlike GetBytes, data is scanned without building Value tree,
+// and the scan stops at the target value, so the rest of data isn't
+// validated. Nothing is allocated.
+//
+// Array indexes may be represented as decimal numbers in keys.
+//
+// nil is returned for non-existing keys path or on error.
+// The returned value refers to data, so it must not be modified.
+func GetRawFromBytes(data []byte, keys ...string) []byte {
+	raw, ok := rawLookup(b2s(data), keys)
+	if !ok {
+		return nil
+	}
+	return s2b(raw)
+}
+
+// GetStringFromBytes returns string value for the field identified by keys path
+// in JSON data.
+//
+// See GetRawFromBytes for details. The returned string refers to data
+// unless it contains escape sequences, which are unescaped into a newly
+// allocated buffer.
+//
+// nil is returned for non-existing keys path, for invalid value type or on error.
+func GetStringFromBytes(data []byte, keys ...string) []byte {
+	raw, ok := rawLookup(b2s(data), keys)
+	if !ok || raw[0] != '"' {
+		return nil
+	}
+	s := raw[1 : len(raw)-1]
+	if strings.IndexByte(s, '\\') >= 0 {
+		// Unescape a copy of the string, since data mustn't be modified.
+		b := append([]byte(nil), s...)
+		s = unescapeStringBestEffort(b2s(b))
+	}
+	return s2b(s)
+}
+
+// GetInt64FromBytes returns int64 value for the field identified by keys path
+// in JSON data.
+//
+// See GetRawFromBytes for details.
+//
+// 0 is returned for non-existing keys path, for invalid value type or on error.
+func GetInt64FromBytes(data []byte, keys ...string) int64 {
+	raw, ok := rawLookupNumber(b2s(data), keys)
+	if !ok {
+		return 0
+	}
+	return fastfloat.ParseInt64BestEffort(raw)
+}
+
+// GetFloat64FromBytes returns float64 value for the field identified by keys path
+// in JSON data.
+//
+// See GetRawFromBytes for details.
+//
+// 0 is returned for non-existing keys path, for invalid value type or on error.
+func GetFloat64FromBytes(data []byte, keys ...string) float64 {
+	raw, ok := rawLookupNumber(b2s(data), keys)
+	if !ok {
+		return 0
+	}
+	return fastfloat.ParseBestEffort(raw)
+}
+
+// GetBoolFromBytes returns bool value for the field identified by keys path
+// in JSON data.
+//
+// See GetRawFromBytes for details.
+//
+// false is returned for non-existing keys path, for invalid value type or on error.
+func GetBoolFromBytes(data []byte, keys ...string) bool {
+	raw, ok := rawLookup(b2s(data), keys)
+	return ok && raw == "true"
+}
+
+func rawLookupNumber(s string, keys []string) (string, bool) {
+	raw, ok := rawLookup(s, keys)
+	if !ok || strings.IndexByte(`"{[tfn`, raw[0]) >= 0 {
+		return "", false
+	}
+	return raw, true
+}
+
+// rawLookup returns raw value for the given keys path in s.
+func rawLookup(s string, keys []string) (string, bool) {
+	s = skipWS(s)
+	for _, key := range keys {
+		if len(s) == 0 {
+			return "", false
+		}
+		ok := false
+		switch s[0] {
+		case '{':
+			s, ok = rawObjectMember(s[1:], key)
+		case '[':
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 0 {
+				return "", false
+			}
+			s, ok = rawArrayItem(s[1:], n)
+		}
+		if !ok {
+			return "", false
+		}
+	}
+	tail, err := validateValue(s)
+	if err != nil {
+		return "", false
+	}
+	return s[:len(s)-len(tail)], true
+}
+
+// rawObjectMember returns s starting at the value for the given key
+// in the object at s. s must start after the opening '{'.
+func rawObjectMember(s, key string) (string, bool) {
+	for {
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != '"' {
+			return "", false
+		}
+		rawKey, tail, err := parseRawKey(s[1:])
+		if err != nil {
+			return "", false
+		}
+		s = skipWS(tail)
+		if len(s) == 0 || s[0] != ':' {
+			return "", false
+		}
+		s = skipWS(s[1:])
+		if rawKeyEqual(rawKey, key) {
+			return s, true
+		}
+		tail, err = validateValue(s)
+		if err != nil {
+			return "", false
+		}
+		s = skipWS(tail)
+		if len(s) == 0 || s[0] != ',' {
+			return "", false
+		}
+		s = s[1:]
+	}
+}
+
+// rawArrayItem returns s starting at the n-th item in the array at s.
+// s must start after the opening '['.
+func rawArrayItem(s string, n int) (string, bool) {
+	for i := 0; ; i++ {
+		s = skipWS(s)
+		if len(s) == 0 || s[0] == ']' {
+			return "", false
+		}
+		if i == n {
+			return s, true
+		}
+		tail, err := validateValue(s)
+		if err != nil {
+			return "", false
+		}
+		s = skipWS(tail)
+		if len(s) == 0 || s[0] != ',' {
+			return "", false
+		}
+		s = s[1:]
+	}
+}
+
+// rawKeyEqual returns true if unescaped rawKey equals to key.
+//
+// The comparison is performed without allocations. Escape sequences
+// are handled in the same way as by unescapeStringBestEffort.
+func rawKeyEqual(rawKey, key string) bool {
+	for {
+		n := strings.IndexByte(rawKey, '\\')
+		if n < 0 {
+			return rawKey == key
+		}
+		if !strings.HasPrefix(key, rawKey[:n]) {
+			return false
+		}
+		key = key[n:]
+		rawKey = rawKey[n+1:]
+		if len(rawKey) == 0 {
+			return len(key) == 0
+		}
+		ch := rawKey[0]
+		rawKey = rawKey[1:]
+		var buf [2 * utf8.UTFMax]byte
+		var b []byte
+		switch ch {
+		case '"', '\\', '/':
+			b = append(buf[:0], ch)
+		case 'b':
+			b = append(buf[:0], '\b')
+		case 'f':
+			b = append(buf[:0], '\f')
+		case 'n':
+			b = append(buf[:0], '\n')
+		case 'r':
+			b = append(buf[:0], '\r')
+		case 't':
+			b = append(buf[:0], '\t')
+		case 'u':
+			b = append(buf[:0], '\\', 'u')
+			if len(rawKey) < 4 {
+				break
+			}
+			xs := rawKey[:4]
+			x, err := strconv.ParseUint(xs, 16, 16)
+			if err != nil {
+				break
+			}
+			rawKey = rawKey[4:]
+			if !utf16.IsSurrogate(rune(x)) {
+				b = buf[:utf8.EncodeRune(buf[:], rune(x))]
+				break
+			}
+			if len(rawKey) < 6 || rawKey[0] != '\\' || rawKey[1] != 'u' {
+				b = append(b, xs...)
+				break
+			}
+			x1, err := strconv.ParseUint(rawKey[2:6], 16, 16)
+			if err != nil {
+				b = append(b, xs...)
+				break
+			}
+			r := utf16.DecodeRune(rune(x), rune(x1))
+			b = buf[:utf8.EncodeRune(buf[:], r)]
+			rawKey = rawKey[6:]
+		default:
+			b = append(buf[:0], '\\', ch)
+		}
+		if len(key) < len(b) || key[:len(b)] != string(b) {
+			return false
+		}
+		key = key[len(b):]
+	}
+}
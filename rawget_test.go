@@ -0,0 +1,114 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestGetFromBytes(t *testing.T) {
+	data := []byte(`{"a":{"b":[1,{"c":"foo\nbar"},-12.5e1]},"x\"y":"z","ሴ😀":123456789012,"t":true,"f":false,"n":null,"e":[]}`)
+
+	f := func(keys ...string) {
+		t.Helper()
+		v := MustParseBytes(data)
+		vv := v.Get(keys...)
+
+		raw := GetRawFromBytes(data, keys...)
+		if vv == nil {
+			if raw != nil {
+				t.Fatalf("expecting nil raw value for %q; got %q", keys, raw)
+			}
+		} else if rawExpected := vv.String(); string(raw) != rawExpected {
+			t.Fatalf("unexpected raw value for %q; got %q; want %q", keys, raw, rawExpected)
+		}
+
+		if s, sExpected := GetStringFromBytes(data, keys...), v.GetStringBytes(keys...); string(s) != string(sExpected) || (s == nil) != (sExpected == nil) {
+			t.Fatalf("unexpected string for %q; got %q; want %q", keys, s, sExpected)
+		}
+		if n, nExpected := GetInt64FromBytes(data, keys...), v.GetInt64(keys...); n != nExpected {
+			t.Fatalf("unexpected int64 for %q; got %d; want %d", keys, n, nExpected)
+		}
+		if f, fExpected := GetFloat64FromBytes(data, keys...), v.GetFloat64(keys...); f != fExpected {
+			t.Fatalf("unexpected float64 for %q; got %v; want %v", keys, f, fExpected)
+		}
+		if b, bExpected := GetBoolFromBytes(data, keys...), v.GetBool(keys...); b != bExpected {
+			t.Fatalf("unexpected bool for %q; got %v; want %v", keys, b, bExpected)
+		}
+	}
+
+	f()
+	f("a")
+	f("a", "b")
+	f("a", "b", "0")
+	f("a", "b", "1", "c")
+	f("a", "b", "2")
+	f("a", "b", "3")
+	f("a", "b", "-1")
+	f("a", "b", "x")
+	f("x\"y")
+	f("ሴ😀")
+	f("t")
+	f("f")
+	f("n")
+	f("e")
+	f("e", "0")
+	f("missing")
+	f("t", "x")
+
+	// The input must remain unchanged.
+	if s := string(data); s != `{"a":{"b":[1,{"c":"foo\nbar"},-12.5e1]},"x\"y":"z","ሴ😀":123456789012,"t":true,"f":false,"n":null,"e":[]}` {
+		t.Fatalf("the input has been modified: %s", s)
+	}
+
+	// The scan must stop at the target value.
+	if n := GetInt64FromBytes([]byte(`{"a":1,"b":2} invalid`), "a"); n != 1 {
+		t.Fatalf("unexpected value; got %d; want 1", n)
+	}
+
+	// Invalid JSON before the target value.
+	for _, s := range []string{`{"a":[},"b":1}`, `{"a" 1,"b":1}`, `{"a":1 "b":1}`, `{"b":tru}`, `[1,,2]`} {
+		if raw := GetRawFromBytes([]byte(s), "b"); raw != nil {
+			t.Fatalf("expecting nil value for %q; got %q", s, raw)
+		}
+	}
+}
+
+func TestRawKeyEqual(t *testing.T) {
+	f := func(rawKey string) {
+		t.Helper()
+		key := unescapeStringBestEffort(string(append([]byte(nil), rawKey...)))
+		if !rawKeyEqual(rawKey, key) {
+			t.Fatalf("expecting %q to be equal to %q", rawKey, key)
+		}
+		if rawKeyEqual(rawKey, key+"x") {
+			t.Fatalf("unexpected equality of %q and %q", rawKey, key+"x")
+		}
+		if len(key) > 0 && rawKeyEqual(rawKey, key[:len(key)-1]) {
+			t.Fatalf("unexpected equality of %q and %q", rawKey, key[:len(key)-1])
+		}
+	}
+	f(``)
+	f(`foo`)
+	f(`a\"b\\c\/d\b\f\n\r\t`)
+	f(`Aሴ`)
+	f(`😀x`)
+	f(`\ud83dx`)
+	f(`\ud83d\uzzzz`)
+	f(`\u12`)
+	f(`\uzzzz`)
+	f(`\q`)
+}
+
+func TestGetFromBytesAllocs(t *testing.T) {
+	data := []byte(`{"a":{"b":[1,{"c":"foo"}]},"x":"y","n":-123}`)
+	n := testing.AllocsPerRun(100, func() {
+		if s := GetStringFromBytes(data, "a", "b", "1", "c"); string(s) != "foo" {
+			panic("unexpected string")
+		}
+		if n := GetInt64FromBytes(data, "n"); n != -123 {
+			panic("unexpected int64")
+		}
+	})
+	if n != 0 {
+		t.Fatalf("unexpected number of allocations; got %v; want 0", n)
+	}
+}
@@ -0,0 +1,60 @@
+package fastjson
+
+// Redact replaces values at the given paths in v with replacement.
+//
+// Every path is a list of keys in the format accepted by Get.
+// Missing paths are skipped. nil replacement is stored as null.
+//
+// The replacement must be unchanged during v lifetime.
+func (v *Value) Redact(replacement *Value, paths ...[]string) {
+	if replacement == nil {
+		replacement = valueNull
+	}
+	for _, keys := range paths {
+		if len(keys) == 0 {
+			continue
+		}
+		parent := v.Get(keys[:len(keys)-1]...)
+		key := keys[len(keys)-1]
+		if parent.Get(key) == nil {
+			continue
+		}
+		parent.Set(key, replacement)
+	}
+}
+
+// RedactKeys replaces values for object entries with the given keys
+// with replacement throughout v.
+//
+// Objects and arrays are traversed recursively at any depth.
+// nil replacement is stored as null.
+//
+// The replacement must be unchanged during v lifetime.
+func (v *Value) RedactKeys(replacement *Value, keys ...string) {
+	if v == nil || len(keys) == 0 {
+		return
+	}
+	if replacement == nil {
+		replacement = valueNull
+	}
+	v.redactKeys(replacement, keys)
+}
+
+func (v *Value) redactKeys(replacement *Value, keys []string) {
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for i := range v.o.kvs {
+			kv := &v.o.kvs[i]
+			if containsString(keys, kv.k) {
+				kv.v = replacement
+				continue
+			}
+			kv.v.redactKeys(replacement, keys)
+		}
+	case TypeArray:
+		for _, item := range v.a {
+			item.redactKeys(replacement, keys)
+		}
+	}
+}
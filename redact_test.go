@@ -0,0 +1,65 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueRedact(t *testing.T) {
+	f := func(s string, paths [][]string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.Redact(MustParse(`"***"`), paths...)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for Redact(%q) on %s\ngot\n%s\nwant\n%s", paths, s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1,"b":2}`, [][]string{{"a"}}, `{"a":"***","b":2}`)
+	f(`{"user":{"name":"x","password":"y"},"token":"z"}`, [][]string{{"user", "password"}, {"token"}}, `{"user":{"name":"x","password":"***"},"token":"***"}`)
+	f(`{"a":[{"b":1},{"b":2}]}`, [][]string{{"a", "1", "b"}}, `{"a":[{"b":1},{"b":"***"}]}`)
+	f(`{"a":[1,2]}`, [][]string{{"a", "0"}}, `{"a":["***",2]}`)
+
+	// Missing paths are skipped.
+	f(`{"a":1}`, [][]string{{"b"}, {"a", "b"}, {"x", "y"}, {}}, `{"a":1}`)
+	f(`{"a":[1]}`, [][]string{{"a", "5"}}, `{"a":[1]}`)
+
+	v := MustParse(`{"a":1}`)
+	v.Redact(nil, []string{"a"})
+	if s := v.String(); s != `{"a":null}` {
+		t.Fatalf("unexpected result for nil replacement: %s", s)
+	}
+	v = MustParse(`{"a":[1,2]}`)
+	v.Redact(nil, []string{"a", "0"})
+	if s := v.String(); s != `{"a":[null,2]}` {
+		t.Fatalf("unexpected result for nil replacement in array: %s", s)
+	}
+	var vNil *Value
+	vNil.Redact(nil, []string{"a"})
+}
+
+func TestValueRedactKeys(t *testing.T) {
+	f := func(s string, keys []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.RedactKeys(MustParse(`"***"`), keys...)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for RedactKeys(%q) on %s\ngot\n%s\nwant\n%s", keys, s, result, resultExpected)
+		}
+	}
+
+	f(`{"password":"x","user":"y"}`, []string{"password", "token"}, `{"password":"***","user":"y"}`)
+	f(`{"a":{"token":{"nested":1}},"b":[{"token":2},3],"token":4}`, []string{"token"}, `{"a":{"token":"***"},"b":[{"token":"***"},3],"token":"***"}`)
+	f(`[{"password":1}]`, []string{"password"}, `[{"password":"***"}]`)
+	f(`{"a":1}`, nil, `{"a":1}`)
+	f(`"password"`, []string{"password"}, `"password"`)
+
+	v := MustParse(`{"a":{"b":1}}`)
+	v.RedactKeys(nil, "b")
+	if s := v.String(); s != `{"a":{"b":null}}` {
+		t.Fatalf("unexpected result for nil replacement: %s", s)
+	}
+	var vNil *Value
+	vNil.RedactKeys(nil, "a")
+}
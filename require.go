@@ -0,0 +1,25 @@
+package fastjson
+
+// Require checks that all the given keys paths exist in v and aren't null.
+//
+// Array indexes may be represented as decimal numbers in keys.
+// ShapeErrors listing every missing or null path is returned on failure,
+// so all the mandatory fields may be checked in a single call:
+//
+//	err := v.Require([]string{"id"}, []string{"user", "name"})
+func (v *Value) Require(paths ...[]string) error {
+	var errs ShapeErrors
+	for _, keys := range paths {
+		fv := v.Get(keys...)
+		switch {
+		case fv == nil:
+			errs.add(keys, "missing required field")
+		case fv.Type() == TypeNull:
+			errs.add(keys, "required field is null")
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueRequire(t *testing.T) {
+	v := MustParse(`{"id":1,"user":{"name":"x","email":null},"items":[{"id":2}],"empty":""}`)
+
+	if err := v.Require([]string{"id"}, []string{"user", "name"}, []string{"items", "0", "id"}, []string{"empty"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := v.Require(); err != nil {
+		t.Fatalf("unexpected error for empty paths: %s", err)
+	}
+
+	err := v.Require([]string{"id"}, []string{"user", "email"}, []string{"missing"}, []string{"items", "1", "id"})
+	es, ok := err.(ShapeErrors)
+	if !ok {
+		t.Fatalf("unexpected error type %T: %v", err, err)
+	}
+	errExpected := `"/user/email": required field is null; "/missing": missing required field; "/items/1/id": missing required field`
+	if es.Error() != errExpected {
+		t.Fatalf("unexpected error\ngot\n%s\nwant\n%s", es, errExpected)
+	}
+
+	var vNil *Value
+	if err := vNil.Require([]string{"a"}); err == nil {
+		t.Fatalf("expecting non-nil error for nil value")
+	}
+}
@@ -0,0 +1,267 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SAXHandler handles events emitted by SAXParser.
+//
+// Byte slices passed to the handler are valid only until the handler
+// returns, so they must be copied if they are needed later.
+//
+// Parsing stops on the first error returned by the handler,
+// and the error is returned from SAXParser.Parse.
+type SAXHandler interface {
+	// OnObjectStart is called on the start of an object.
+	OnObjectStart() error
+
+	// OnObjectEnd is called on the end of an object.
+	OnObjectEnd() error
+
+	// OnArrayStart is called on the start of an array.
+	OnArrayStart() error
+
+	// OnArrayEnd is called on the end of an array.
+	OnArrayEnd() error
+
+	// OnKey is called with unescaped object key before the key value.
+	OnKey(key []byte) error
+
+	// OnString is called with unescaped string value.
+	OnString(s []byte) error
+
+	// OnNumber is called with raw number value. It may be parsed
+	// with fastfloat.Parse* functions.
+	OnNumber(n []byte) error
+
+	// OnBool is called with bool value.
+	OnBool(b bool) error
+
+	// OnNull is called with null value.
+	OnNull() error
+}
+
+// SAXParser parses JSON and emits events to SAXHandler.
+//
+// Unlike Parser, SAXParser never allocates Values, so it is suitable
+// for extracting a few fields from huge JSON documents at the highest
+// possible speed.
+//
+// SAXParser may be re-used for subsequent parsing.
+//
+// SAXParser cannot be used from concurrent goroutines.
+type SAXParser struct {
+	// b is a scratch buffer for unescaping strings.
+	b []byte
+}
+
+// Parse parses s containing a single JSON value and emits events for it to h.
+//
+// s isn't modified, so it may be re-used after returning.
+func (p *SAXParser) Parse(s string, h SAXHandler) error {
+	s = skipWS(s)
+	tail, err := p.parseValue(s, h, 0)
+	if err != nil {
+		if he, ok := err.(*saxHandlerError); ok {
+			return he.err
+		}
+		return fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(tail))
+	}
+	tail = skipWS(tail)
+	if len(tail) > 0 {
+		return fmt.Errorf("unexpected tail: %q", startEndString(tail))
+	}
+	return nil
+}
+
+// ParseBytes parses b containing a single JSON value and emits events for it to h.
+//
+// b isn't modified, so it may be re-used after returning.
+func (p *SAXParser) ParseBytes(b []byte, h SAXHandler) error {
+	return p.Parse(b2s(b), h)
+}
+
+// saxHandlerError wraps errors returned by SAXHandler, so they are returned
+// without additional context.
+type saxHandlerError struct {
+	err error
+}
+
+func (e *saxHandlerError) Error() string {
+	return e.err.Error()
+}
+
+func (p *SAXParser) parseValue(s string, h SAXHandler, depth int) (string, error) {
+	if len(s) == 0 {
+		return s, fmt.Errorf("cannot parse empty string")
+	}
+	depth++
+	if depth > MaxDepth {
+		return s, fmt.Errorf("too big depth for the nested JSON; it exceeds %d", MaxDepth)
+	}
+
+	var err error
+	switch s[0] {
+	case '{':
+		if err := h.OnObjectStart(); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		s, err = p.parseObject(s[1:], h, depth)
+		if err != nil {
+			return s, wrapSAXError("cannot parse object", err)
+		}
+		if err := h.OnObjectEnd(); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		return s, nil
+	case '[':
+		if err := h.OnArrayStart(); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		s, err = p.parseArray(s[1:], h, depth)
+		if err != nil {
+			return s, wrapSAXError("cannot parse array", err)
+		}
+		if err := h.OnArrayEnd(); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		return s, nil
+	case '"':
+		var ss string
+		ss, s, err = parseRawString(s[1:])
+		if err != nil {
+			return s, fmt.Errorf("cannot parse string: %s", err)
+		}
+		if err := h.OnString(p.unescape(ss)); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		return s, nil
+	case 't':
+		if !strings.HasPrefix(s, "true") {
+			return s, fmt.Errorf("unexpected value found: %q", s)
+		}
+		if err := h.OnBool(true); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		return s[len("true"):], nil
+	case 'f':
+		if !strings.HasPrefix(s, "false") {
+			return s, fmt.Errorf("unexpected value found: %q", s)
+		}
+		if err := h.OnBool(false); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		return s[len("false"):], nil
+	case 'n':
+		if strings.HasPrefix(s, "null") {
+			if err := h.OnNull(); err != nil {
+				return s, &saxHandlerError{err}
+			}
+			return s[len("null"):], nil
+		}
+	}
+
+	var ns string
+	ns, s, err = parseRawNumber(s)
+	if err != nil {
+		return s, fmt.Errorf("cannot parse number: %s", err)
+	}
+	if err := h.OnNumber(s2b(ns)); err != nil {
+		return s, &saxHandlerError{err}
+	}
+	return s, nil
+}
+
+func (p *SAXParser) parseArray(s string, h SAXHandler, depth int) (string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return s, fmt.Errorf("missing ']'")
+	}
+	if s[0] == ']' {
+		return s[1:], nil
+	}
+	for {
+		var err error
+		s = skipWS(s)
+		s, err = p.parseValue(s, h, depth)
+		if err != nil {
+			return s, wrapSAXError("cannot parse array value", err)
+		}
+		s = skipWS(s)
+		if len(s) == 0 {
+			return s, fmt.Errorf("unexpected end of array")
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == ']' {
+			return s[1:], nil
+		}
+		return s, fmt.Errorf("missing ',' after array value")
+	}
+}
+
+func (p *SAXParser) parseObject(s string, h SAXHandler, depth int) (string, error) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return s, fmt.Errorf("missing '}'")
+	}
+	if s[0] == '}' {
+		return s[1:], nil
+	}
+	for {
+		var err error
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != '"' {
+			return s, fmt.Errorf(`cannot find opening '"" for object key`)
+		}
+		var k string
+		k, s, err = parseRawKey(s[1:])
+		if err != nil {
+			return s, fmt.Errorf("cannot parse object key: %s", err)
+		}
+		if err := h.OnKey(p.unescape(k)); err != nil {
+			return s, &saxHandlerError{err}
+		}
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != ':' {
+			return s, fmt.Errorf("missing ':' after object key")
+		}
+		s = skipWS(s[1:])
+		s, err = p.parseValue(s, h, depth)
+		if err != nil {
+			return s, wrapSAXError("cannot parse object value", err)
+		}
+		s = skipWS(s)
+		if len(s) == 0 {
+			return s, fmt.Errorf("unexpected end of object")
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == '}' {
+			return s[1:], nil
+		}
+		return s, fmt.Errorf("missing ',' after object value")
+	}
+}
+
+// unescape returns unescaped s. The input string isn't modified,
+// since escaped strings are unescaped in p.b.
+func (p *SAXParser) unescape(s string) []byte {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s2b(s)
+	}
+	p.b = append(p.b[:0], s...)
+	return s2b(unescapeStringBestEffort(b2s(p.b)))
+}
+
+func wrapSAXError(prefix string, err error) error {
+	if _, ok := err.(*saxHandlerError); ok {
+		return err
+	}
+	return fmt.Errorf("%s: %s", prefix, err)
+}
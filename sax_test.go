@@ -0,0 +1,100 @@
+package fastjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type saxRecorder struct {
+	bb      bytes.Buffer
+	stopKey string
+}
+
+var errSAXStop = errors.New("stop")
+
+func (r *saxRecorder) OnObjectStart() error { r.bb.WriteString("{ "); return nil }
+func (r *saxRecorder) OnObjectEnd() error   { r.bb.WriteString("} "); return nil }
+func (r *saxRecorder) OnArrayStart() error  { r.bb.WriteString("[ "); return nil }
+func (r *saxRecorder) OnArrayEnd() error    { r.bb.WriteString("] "); return nil }
+func (r *saxRecorder) OnKey(key []byte) error {
+	if string(key) == r.stopKey {
+		return errSAXStop
+	}
+	fmt.Fprintf(&r.bb, "key:%q ", key)
+	return nil
+}
+func (r *saxRecorder) OnString(s []byte) error { fmt.Fprintf(&r.bb, "str:%q ", s); return nil }
+func (r *saxRecorder) OnNumber(n []byte) error { fmt.Fprintf(&r.bb, "num:%s ", n); return nil }
+func (r *saxRecorder) OnBool(b bool) error     { fmt.Fprintf(&r.bb, "bool:%v ", b); return nil }
+func (r *saxRecorder) OnNull() error           { r.bb.WriteString("null "); return nil }
+
+func TestSAXParser(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		var p SAXParser
+		var r saxRecorder
+		sOrig := s
+		if err := p.Parse(s, &r); err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", s, err)
+		}
+		if result := r.bb.String(); result != resultExpected {
+			t.Fatalf("unexpected events for %q\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+		if s != sOrig {
+			t.Fatalf("the input has been modified; got %q; want %q", s, sOrig)
+		}
+	}
+
+	f(`1`, `num:1 `)
+	f(` "foo" `, `str:"foo" `)
+	f(`"a\nbA"`, `str:"a\nbA" `)
+	f(`true`, `bool:true `)
+	f(`false`, `bool:false `)
+	f(`null`, `null `)
+	f(`NaN`, `num:NaN `)
+	f(`[]`, `[ ] `)
+	f(`{}`, `{ } `)
+	f(`{"a":[1,-2.5e3,{"b\"c":null}],"d":{}, "e" : "x"}`,
+		`{ key:"a" [ num:1 num:-2.5e3 { key:"b\"c" null } ] key:"d" { } key:"e" str:"x" } `)
+}
+
+func TestSAXParserError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		var p SAXParser
+		var r saxRecorder
+		if err := p.Parse(s, &r); err == nil {
+			t.Fatalf("expecting non-nil error when parsing %q", s)
+		}
+	}
+
+	f(``)
+	f(`[`)
+	f(`[1 2]`)
+	f(`{"a"}`)
+	f(`{"a":1,}`)
+	f(`{a:1}`)
+	f(`tru`)
+	f(`nul`)
+	f(`"foo`)
+	f(`1 2`)
+	f(`{"a":[1}`)
+	f(string(bytes.Repeat([]byte("["), MaxDepth+1)))
+}
+
+func TestSAXParserHandlerError(t *testing.T) {
+	var p SAXParser
+	r := saxRecorder{
+		stopKey: "stop",
+	}
+	err := p.ParseBytes([]byte(`{"a":1,"b":{"stop":2},"c":3}`), &r)
+	if err != errSAXStop {
+		t.Fatalf("unexpected error; got %v; want %v", err, errSAXStop)
+	}
+	resultExpected := `{ key:"a" num:1 key:"b" { `
+	if result := r.bb.String(); result != resultExpected {
+		t.Fatalf("unexpected events\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+}
@@ -1,7 +1,12 @@
 package fastjson
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 )
 
 // Scanner scans a series of JSON values. Values may be delimited by whitespace.
@@ -11,12 +16,20 @@ import (
 // Scanner may be re-used for subsequent parsing.
 //
 // Scanner cannot be used from concurrent goroutines.
+// Use per-goroutine scanners or ScannerPool instead.
 //
 // Use Parser for parsing only a single JSON value.
 type Scanner struct {
-	// b contains a working copy of json value passed to Init.
+	// b contains a working copy of json value passed to Init
+	// or the data read from r.
 	b []byte
 
+	// r is the reader passed to InitReader.
+	r io.Reader
+
+	// readerEOF is set when r returns io.EOF.
+	readerEOF bool
+
 	// s points to the next JSON value to parse.
 	s string
 
@@ -28,6 +41,34 @@ type Scanner struct {
 
 	// c is used for caching JSON values.
 	c cache
+
+	// peeked is set if v contains the value parsed by Peek, which isn't consumed yet.
+	peeked bool
+
+	// peekRaw points to the raw value parsed by Peek.
+	peekRaw string
+
+	// peekTail points to the tail after the value parsed by Peek.
+	peekTail string
+
+	// jsonSeq is set if sc reads JSON text sequences. See SetJSONSeq.
+	jsonSeq bool
+
+	// inSeqRecord is set if the record separator in front of the next value
+	// has been already consumed.
+	inSeqRecord bool
+
+	// delims contains additional value delimiters. See SetDelimiters.
+	delims string
+
+	// split is the function for splitting the input into records. See SetSplitFunc.
+	split bufio.SplitFunc
+
+	// follow is set if sc waits for more data at the end of input. See SetFollow.
+	follow bool
+
+	// pending tracks the structure of the value truncated at the end of b.
+	pending pendingValue
 }
 
 // Init initializes sc with the given s.
@@ -36,8 +77,25 @@ type Scanner struct {
 func (sc *Scanner) Init(s string) {
 	sc.b = append(sc.b[:0], s...) // 重用底层字节切片
 	sc.s = b2s(sc.b)              // 字节切片转字符串（零拷贝）
+	sc.r = nil
+	sc.readerEOF = false
 	sc.err = nil
 	sc.v = nil
+	sc.peeked = false
+	sc.peekRaw = ""
+	sc.peekTail = ""
+	sc.inSeqRecord = false
+}
+
+// InitReader initializes sc for reading JSON values from r.
+//
+// r may contain multiple JSON values, which may be delimited by whitespace.
+// The data is read from r on demand into a reusable buffer, so arbitrarily
+// long streams may be scanned with memory usage proportional to the size
+// of the biggest value.
+func (sc *Scanner) InitReader(r io.Reader) {
+	sc.Init("")
+	sc.r = r
 }
 
 // InitBytes initializes sc with the given b.
@@ -54,25 +112,21 @@ func (sc *Scanner) InitBytes(b []byte) {
 // Returns false either on error or on the end of s.
 // Call Error in order to determine the cause of the returned false.
 func (sc *Scanner) Next() bool {
+	sc.resume()
 	// 有错误，不再继续
 	if sc.err != nil {
 		return false
 	}
-
-	// 跳过空白字符
-	sc.s = skipWS(sc.s)
-	if len(sc.s) == 0 { // 到达字符串末尾
-		sc.err = errEOF
-		return false
+	if sc.peeked {
+		// The value has been already parsed by Peek.
+		sc.s = sc.peekTail
+		sc.peeked = false
+		return true
 	}
 
-	// 重置缓存，注意，因为底层数组是复用的，Next() 之后需要通过 Value() 访问当前值，下次 Next 之后此前的 Value 都可能失效。
-	sc.c.reset()
-
 	// 解析单个 JSON 值
-	v, tail, err := parseValue(sc.s, &sc.c, 0)
-	if err != nil {
-		sc.err = err
+	v, _, tail, ok := sc.scanParsedValue()
+	if !ok {
 		return false
 	}
 
@@ -81,6 +135,618 @@ func (sc *Scanner) Next() bool {
 	return true
 }
 
+// NextRaw returns raw bytes of the next JSON value from s passed to Init.
+//
+// The value is checked against the same grammar as Next uses, but it isn't
+// parsed into Value tree, so NextRaw is faster than Next. This means NextRaw
+// accepts values such as NaN, Inf or 1e, which are accepted by Next, but are
+// rejected by Validate. This is useful for forwarding records untouched.
+// Value returns nil after NextRaw call.
+//
+// The returned bytes must not be modified. They are valid until
+// the next Init call. If sc reads from io.Reader, then the returned
+// bytes are valid only until the next call to sc.
+//
+// Returns false either on error or on the end of s.
+// Call Error in order to determine the cause of the returned false.
+func (sc *Scanner) NextRaw() ([]byte, bool) {
+	sc.resume()
+	if sc.err != nil {
+		return nil, false
+	}
+	if sc.peeked {
+		sc.peeked = false
+		sc.v = nil
+		sc.s = sc.peekTail
+		return s2b(sc.peekRaw), true
+	}
+
+	sc.v = nil
+	raw, tail, ok := sc.scanValue(func(s string) (string, error) {
+		return skipValue(s, 0)
+	})
+	if !ok {
+		return nil, false
+	}
+	sc.s = tail
+	return s2b(raw), true
+}
+
+// Skip skips the next JSON value from s passed to Init.
+//
+// The skipped value is checked against the same grammar as Next uses,
+// but it isn't parsed into Value tree, so Skip is faster than Next. This is useful for filtering out
+// unneeded records.
+//
+// Returns false either on error or on the end of s.
+// Call Error in order to determine the cause of the returned false.
+func (sc *Scanner) Skip() bool {
+	_, ok := sc.NextRaw()
+	return ok
+}
+
+// Peek parses the next JSON value from s passed to Init without consuming it.
+//
+// The next Next call returns the peeked value without parsing it again,
+// while Skip and NextRaw consume it. The returned value is valid until
+// the value is consumed. Strings in the peeked value mustn't be accessed
+// if the value is consumed via NextRaw, since accessing them may modify
+// the raw bytes.
+//
+// Returns false either on error or on the end of s.
+// Call Error in order to determine the cause of the returned false.
+func (sc *Scanner) Peek() (*Value, bool) {
+	sc.resume()
+	if sc.err != nil {
+		return nil, false
+	}
+	if sc.peeked {
+		return sc.v, true
+	}
+	v, raw, tail, ok := sc.scanParsedValue()
+	if !ok {
+		return nil, false
+	}
+	sc.v = v
+	sc.peekRaw = raw
+	sc.peekTail = tail
+	sc.peeked = true
+	return v, true
+}
+
+// ForEach calls f for every JSON value in s.
+//
+// s may contain multiple JSON values, which may be delimited by whitespace.
+// Scanning stops on the first error returned by f, and the error is returned.
+// The value passed to f is valid only until f returns.
+//
+// sc is initialized with s, so the previous sc state is lost.
+func (sc *Scanner) ForEach(s string, f func(v *Value) error) error {
+	sc.Init(s)
+	return sc.forEach(f)
+}
+
+// ForEachBytes calls f for every JSON value in b.
+//
+// See ForEach for details.
+func (sc *Scanner) ForEachBytes(b []byte, f func(v *Value) error) error {
+	sc.InitBytes(b)
+	return sc.forEach(f)
+}
+
+func (sc *Scanner) forEach(f func(v *Value) error) error {
+	for sc.Next() {
+		if err := f(sc.v); err != nil {
+			return err
+		}
+	}
+	return sc.Error()
+}
+
+// SetJSONSeq enables or disables reading JSON text sequences
+// ( https://tools.ietf.org/html/rfc7464 ).
+//
+// Every value in JSON text sequence must be prefixed with the record
+// separator char 0x1E. Malformed records are skipped as recommended
+// by RFC 7464, so the scanning resumes at the next record.
+//
+// The setting persists across Init calls.
+func (sc *Scanner) SetJSONSeq(enabled bool) {
+	sc.jsonSeq = enabled
+	sc.inSeqRecord = false
+}
+
+// SetDelimiters sets additional value delimiters for sc.
+//
+// Every byte in delims is treated as a value separator in addition
+// to whitespace, so values delimited by e.g. "\x00" or ";" may be scanned.
+// Delimiters are ignored inside JSON values.
+//
+// The setting persists across Init calls. Pass an empty delims
+// for resetting the setting.
+func (sc *Scanner) SetDelimiters(delims string) {
+	sc.delims = delims
+}
+
+// SetSplitFunc sets the function for splitting the input into records.
+//
+// Every non-empty token returned by split must contain a single JSON value
+// optionally surrounded by whitespace. Empty tokens are skipped.
+// See bufio.SplitFunc for details on split semantics. Delimiters
+// set via SetDelimiters and JSON text sequence mode are ignored if split
+// is set.
+//
+// The setting persists across Init calls. Pass nil for resetting the setting.
+func (sc *Scanner) SetSplitFunc(split bufio.SplitFunc) {
+	sc.split = split
+}
+
+// skipDelims skips whitespace and delimiters set via SetDelimiters
+// in front of s.
+func (sc *Scanner) skipDelims(s string) string {
+	for {
+		s = skipWS(s)
+		if len(s) == 0 || len(sc.delims) == 0 || strings.IndexByte(sc.delims, s[0]) < 0 {
+			return s
+		}
+		s = s[1:]
+	}
+}
+
+// SetFollow enables or disables tail-follow mode.
+//
+// In tail-follow mode the end of input isn't final: Next returns false
+// with nil Error at the end of the available data, while a partially
+// written trailing value is retained. Scanning resumes on subsequent
+// calls after more data is added via Append or becomes readable from
+// the reader passed to InitReader. This is useful for tailing actively
+// written files and network streams.
+//
+// Top-level numbers at the end of the available data are retained
+// until more data arrives, since they may be incomplete.
+// Records obtained via SetSplitFunc aren't retained.
+//
+// The setting persists across Init calls.
+func (sc *Scanner) SetFollow(enabled bool) {
+	sc.follow = enabled
+}
+
+// Append appends more data to the input of sc.
+//
+// The data is scanned after the already available data. Values returned
+// before the Append call become invalid. more is copied, so it may be
+// modified after returning.
+//
+// Append is intended for tail-follow mode. See SetFollow.
+func (sc *Scanner) Append(more []byte) {
+	n := copy(sc.b, sc.s)
+	sc.b = append(sc.b[:n], more...)
+	sc.s = b2s(sc.b)
+	sc.v = nil
+	// The peeked value refers to the moved data, so it must be parsed again.
+	sc.peeked = false
+	sc.peekRaw = ""
+	sc.peekTail = ""
+}
+
+// resume prepares sc for scanning newly arrived data in tail-follow mode.
+func (sc *Scanner) resume() {
+	if sc.follow && sc.err == errEOF {
+		sc.err = nil
+		sc.readerEOF = false
+	}
+}
+
+// isTruncatedValue returns true if s contains a value truncated
+// at the end of s.
+func isTruncatedValue(s string) bool {
+	var t Tokenizer
+	t.Init(s)
+	for {
+		tok, err := t.next()
+		if err != nil {
+			return mayBeTruncated(t.s)
+		}
+		if t.Depth() == 0 {
+			return tok.Type == TokenNumber && len(t.s) == 0
+		}
+	}
+}
+
+// recordSeparator is the record separator char used in JSON text sequences.
+const recordSeparator = 0x1E
+
+// scanParsedValue parses the next value and returns it with the raw value
+// and the tail after it.
+func (sc *Scanner) scanParsedValue() (*Value, string, string, bool) {
+	var v *Value
+	raw, tail, ok := sc.scanValue(func(s string) (string, error) {
+		// 重置缓存，注意，因为底层数组是复用的，Next() 之后需要通过 Value() 访问当前值，下次 Next 之后此前的 Value 都可能失效。
+		sc.c.reset()
+		var tail string
+		var err error
+		v, tail, err = parseValue(s, &sc.c, 0, nil)
+		return tail, err
+	})
+	return v, raw, tail, ok
+}
+
+// skipValue skips the value at the start of s and returns the tail after it.
+//
+// It accepts exactly the values accepted by parseValue without a shape,
+// but it doesn't build Value tree.
+func skipValue(s string, depth int) (string, error) {
+	if len(s) == 0 {
+		return s, fmt.Errorf("cannot parse empty string")
+	}
+	depth++
+	if depth > MaxDepth {
+		return s, fmt.Errorf("too big depth for the nested JSON; it exceeds %d", MaxDepth)
+	}
+	var err error
+	switch s[0] {
+	case '{':
+		s = skipWS(s[1:])
+		if len(s) > 0 && s[0] == '}' {
+			return s[1:], nil
+		}
+		for {
+			s = skipWS(s)
+			if len(s) == 0 || s[0] != '"' {
+				return s, fmt.Errorf("cannot parse object: cannot find opening '\"\" for object key")
+			}
+			if _, s, err = parseRawKey(s[1:]); err != nil {
+				return s, fmt.Errorf("cannot parse object: cannot parse object key: %s", err)
+			}
+			s = skipWS(s)
+			if len(s) == 0 || s[0] != ':' {
+				return s, fmt.Errorf("cannot parse object: missing ':' after object key")
+			}
+			if s, err = skipValue(skipWS(s[1:]), depth); err != nil {
+				return s, fmt.Errorf("cannot parse object: cannot parse object value: %s", err)
+			}
+			s = skipWS(s)
+			if len(s) == 0 {
+				return s, fmt.Errorf("cannot parse object: unexpected end of object")
+			}
+			if s[0] == ',' {
+				s = s[1:]
+				continue
+			}
+			if s[0] == '}' {
+				return s[1:], nil
+			}
+			return s, fmt.Errorf("cannot parse object: missing ',' after object value")
+		}
+	case '[':
+		s = skipWS(s[1:])
+		if len(s) > 0 && s[0] == ']' {
+			return s[1:], nil
+		}
+		for {
+			if s, err = skipValue(skipWS(s), depth); err != nil {
+				return s, fmt.Errorf("cannot parse array: cannot parse array value: %s", err)
+			}
+			s = skipWS(s)
+			if len(s) == 0 {
+				return s, fmt.Errorf("cannot parse array: unexpected end of array")
+			}
+			if s[0] == ',' {
+				s = s[1:]
+				continue
+			}
+			if s[0] == ']' {
+				return s[1:], nil
+			}
+			return s, fmt.Errorf("cannot parse array: missing ',' after array value")
+		}
+	case '"':
+		if _, s, err = parseRawString(s[1:]); err != nil {
+			return s, fmt.Errorf("cannot parse string: %s", err)
+		}
+		return s, nil
+	case 't':
+		if strings.HasPrefix(s, "true") {
+			return s[len("true"):], nil
+		}
+	case 'f':
+		if strings.HasPrefix(s, "false") {
+			return s[len("false"):], nil
+		}
+	case 'n':
+		if strings.HasPrefix(s, "null") {
+			return s[len("null"):], nil
+		}
+		if len(s) >= 3 && strings.EqualFold(s[:3], "nan") {
+			return s[3:], nil
+		}
+	default:
+		if _, s, err = parseRawNumber(s); err != nil {
+			return s, fmt.Errorf("cannot parse number: %s", err)
+		}
+		return s, nil
+	}
+	return s, fmt.Errorf("unexpected value found: %q", s)
+}
+
+// scanValue skips whitespace in front of the next value and calls parse
+// for the next value. It returns the raw parsed value and the tail after it.
+//
+// If sc reads from io.Reader, then more data is read and parse
+// is called again if the value may be truncated at the end of the buffer.
+func (sc *Scanner) scanValue(parse func(s string) (string, error)) (string, string, bool) {
+	if sc.split != nil {
+		return sc.scanSplitValue(parse)
+	}
+	sc.pending = pendingValue{}
+	for {
+		sc.s = sc.skipDelims(sc.s)
+		if len(sc.s) == 0 {
+			if sc.canRead() {
+				if !sc.readMore() {
+					return "", "", false
+				}
+				continue
+			}
+			sc.err = errEOF
+			return "", "", false
+		}
+		if sc.jsonSeq && !sc.inSeqRecord {
+			if sc.s[0] != recordSeparator {
+				sc.err = fmt.Errorf("missing record separator 0x1E in front of JSON text sequence record: %q", startEndString(sc.s))
+				return "", "", false
+			}
+			// Skip empty records.
+			for len(sc.s) > 0 && sc.s[0] == recordSeparator {
+				sc.s = sc.s[1:]
+			}
+			sc.inSeqRecord = true
+			continue
+		}
+		tail, err := parse(sc.s)
+		if sc.jsonSeq && err == nil {
+			if t := skipWS(tail); len(t) > 0 && t[0] != recordSeparator {
+				err = fmt.Errorf("unexpected tail after JSON text sequence record: %q", startEndString(t))
+			}
+		}
+		if (err != nil || len(tail) == 0) && sc.canRead() {
+			// The value may be truncated at the end of the buffer.
+			// Read more data and try parsing the value again.
+			if !sc.readMore() {
+				return "", "", false
+			}
+			continue
+		}
+		if sc.follow && (err != nil || len(tail) == 0) && isTruncatedValue(sc.s) {
+			// Wait for the rest of the value.
+			sc.err = errEOF
+			return "", "", false
+		}
+		if err != nil {
+			if sc.jsonSeq {
+				// Skip the malformed record and continue with the next record
+				// as recommended by RFC 7464.
+				n := strings.IndexByte(sc.s, recordSeparator)
+				if n < 0 {
+					n = len(sc.s)
+				}
+				sc.s = sc.s[n:]
+				sc.inSeqRecord = false
+				sc.pending = pendingValue{}
+				continue
+			}
+			sc.err = err
+			return "", "", false
+		}
+		sc.inSeqRecord = false
+		return sc.s[:len(sc.s)-len(tail)], tail, true
+	}
+}
+
+// scanSplitValue obtains the next record via sc.split and calls parse
+// for the value in the record.
+func (sc *Scanner) scanSplitValue(parse func(s string) (string, error)) (string, string, bool) {
+	for {
+		if len(sc.s) == 0 && sc.canRead() {
+			if !sc.readMore() {
+				return "", "", false
+			}
+			continue
+		}
+		atEOF := !sc.canRead()
+		if atEOF && len(sc.s) == 0 {
+			sc.err = errEOF
+			return "", "", false
+		}
+		advance, token, err := sc.split(s2b(sc.s), atEOF)
+		if err == bufio.ErrFinalToken {
+			// Stop scanning after the token.
+			advance = len(sc.s)
+			sc.r = nil
+		} else if err != nil {
+			sc.err = fmt.Errorf("cannot split the input into records: %s", err)
+			return "", "", false
+		}
+		if advance < 0 || advance > len(sc.s) {
+			sc.err = fmt.Errorf("split function returned invalid advance count %d; must be in the range [0..%d]", advance, len(sc.s))
+			return "", "", false
+		}
+		if advance == 0 && token == nil {
+			if atEOF {
+				// The remaining data doesn't contain records.
+				sc.err = errEOF
+				return "", "", false
+			}
+			if !sc.readMore() {
+				return "", "", false
+			}
+			continue
+		}
+		s := skipWS(b2s(token))
+		if len(s) == 0 {
+			if advance == 0 {
+				sc.err = io.ErrNoProgress
+				return "", "", false
+			}
+			// Skip empty record.
+			sc.s = sc.s[advance:]
+			continue
+		}
+		sc.s = sc.s[advance:]
+		tail, err := parse(s)
+		if err == nil && len(skipWS(tail)) > 0 {
+			err = fmt.Errorf("unexpected tail after the value in the record: %q", startEndString(tail))
+		}
+		if err != nil {
+			sc.err = err
+			return "", "", false
+		}
+		return s[:len(s)-len(tail)], sc.s, true
+	}
+}
+
+func (sc *Scanner) canRead() bool {
+	return sc.r != nil && !sc.readerEOF
+}
+
+// minReadSize is the minimum number of bytes to read from io.Reader
+// passed to InitReader at once.
+const minReadSize = 64 * 1024
+
+// maxEmptyReads is the maximum number of consecutive empty reads
+// from io.Reader before giving up.
+const maxEmptyReads = 100
+
+// readMore moves the unparsed tail to the start of sc.b and appends
+// more data from sc.r to it.
+//
+// If the tail contains a truncated value, then reading continues until
+// at least len(sc.s) bytes are read, so the buffer is doubled on every call
+// for huge values. This prevents quadratic parsing time on repeated parse
+// retries when the reader returns data in small chunks. Reading stops earlier
+// if the read data may complete the value, so values arriving over
+// interactive streams are parsed without waiting for more data.
+func (sc *Scanner) readMore() bool {
+	n := copy(sc.b, sc.s)
+	sc.b = sc.b[:n]
+	pv := &sc.pending
+	if n == 0 {
+		*pv = pendingValue{}
+	} else if !pv.started {
+		pv.started = true
+		pv.scan(sc.b)
+	}
+	pv.complete = false
+	need := n
+	if need < minReadSize {
+		need = minReadSize
+	}
+	if cap(sc.b)-n < need {
+		b := make([]byte, n, n+need)
+		copy(b, sc.b)
+		sc.b = b
+	}
+	emptyReads := 0
+	for len(sc.b)-n < need {
+		m, err := sc.r.Read(sc.b[len(sc.b):cap(sc.b)])
+		pv.started = true
+		pv.scan(sc.b[len(sc.b) : len(sc.b)+m])
+		sc.b = sc.b[:len(sc.b)+m]
+		if err == io.EOF {
+			sc.readerEOF = true
+			break
+		}
+		if err != nil {
+			sc.s = b2s(sc.b)
+			sc.err = err
+			return false
+		}
+		if m > 0 {
+			if n == 0 || sc.split != nil || pv.complete {
+				// Parse the available data without waiting for the buffer to fill up.
+				break
+			}
+			emptyReads = 0
+			continue
+		}
+		emptyReads++
+		if emptyReads >= maxEmptyReads {
+			sc.s = b2s(sc.b)
+			sc.err = io.ErrNoProgress
+			return false
+		}
+	}
+	sc.s = b2s(sc.b)
+	return true
+}
+
+// pendingValue tracks the nesting of the value read by Scanner.readMore,
+// so the value is parsed again only if the read data may complete it.
+type pendingValue struct {
+	// started is set if the value start has been scanned.
+	started bool
+
+	// depth is the current nesting depth.
+	depth int
+
+	// inString is set inside a string.
+	inString bool
+
+	// escape is set after a backslash inside a string.
+	escape bool
+
+	// scalar is set after the start of a top-level number or literal.
+	scalar bool
+
+	// complete is set if the scanned data may complete the value.
+	complete bool
+}
+
+func (pv *pendingValue) scan(b []byte) {
+	for _, c := range b {
+		if pv.inString {
+			if pv.escape {
+				pv.escape = false
+			} else if c == '\\' {
+				pv.escape = true
+			} else if c == '"' {
+				pv.inString = false
+				if pv.depth == 0 {
+					pv.complete = true
+				}
+			}
+			continue
+		}
+		if pv.depth == 0 && pv.scalar && !isScalarChar(c) {
+			// The end of a top-level number or literal.
+			pv.complete = true
+			pv.scalar = false
+		}
+		switch c {
+		case '"':
+			pv.inString = true
+		case '{', '[':
+			pv.depth++
+		case '}', ']':
+			// Malformed values with unbalanced brackets cannot be completed.
+			if pv.depth > 0 {
+				pv.depth--
+				if pv.depth == 0 {
+					pv.complete = true
+				}
+			}
+		default:
+			if pv.depth == 0 && isScalarChar(c) {
+				pv.scalar = true
+			}
+		}
+	}
+}
+
+func isScalarChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-' || c == '+' || c == '.'
+}
+
 // Error returns the last error.
 func (sc *Scanner) Error() error {
 	if sc.err == errEOF {
@@ -99,4 +765,53 @@ func (sc *Scanner) Value() *Value {
 	return sc.v
 }
 
+// ValueCopy returns a deep copy of the last parsed value allocated in dst.
+//
+// Unlike the value returned by Value, the copy remains valid after
+// the Next call until dst.Reset call, so selected records may be retained
+// while scanning the rest of the input.
+//
+// nil is returned if there is no parsed value.
+func (sc *Scanner) ValueCopy(dst *Arena) *Value {
+	return sc.v.CopyTo(dst)
+}
+
+// ScanResult is a result sent by Scanner.Chan.
+type ScanResult struct {
+	// Value is the scanned value. It is nil if Err is set.
+	Value *Value
+
+	// Err is the scanning error.
+	Err error
+}
+
+// Chan starts scanning the remaining JSON values in sc from a background
+// goroutine and returns a channel with the scanned values.
+//
+// Values sent to the channel are deep copies, so they may be used
+// after receiving the next value. Scanning error is sent as the last item.
+// The channel is closed after the last item or after ctx is done.
+//
+// sc mustn't be used until the returned channel is closed.
+func (sc *Scanner) Chan(ctx context.Context) <-chan ScanResult {
+	ch := make(chan ScanResult)
+	go func() {
+		defer close(ch)
+		for sc.Next() {
+			select {
+			case ch <- ScanResult{Value: sc.v.CloneForGoroutine()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := sc.Error(); err != nil {
+			select {
+			case ch <- ScanResult{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return ch
+}
+
 var errEOF = errors.New("end of s")
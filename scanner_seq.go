@@ -0,0 +1,36 @@
+//go:build go1.23
+// +build go1.23
+
+package fastjson
+
+import (
+	"iter"
+)
+
+// Seq returns an iterator over the remaining JSON values in sc.
+//
+// Every value is yielded with nil error. The value is valid only until
+// the next iteration. Scanning error is yielded with nil value
+// as the last item.
+//
+// Example:
+//
+//	sc.Init(s)
+//	for v, err := range sc.Seq() {
+//		if err != nil {
+//			return err
+//		}
+//		process(v)
+//	}
+func (sc *Scanner) Seq() iter.Seq2[*Value, error] {
+	return func(yield func(*Value, error) bool) {
+		for sc.Next() {
+			if !yield(sc.v, nil) {
+				return
+			}
+		}
+		if err := sc.Error(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
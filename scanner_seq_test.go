@@ -0,0 +1,44 @@
+//go:build go1.23
+// +build go1.23
+
+package fastjson
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestScannerSeq(t *testing.T) {
+	var sc Scanner
+	sc.Init(`{"a":1} [2] "foo" [`)
+	var bb bytes.Buffer
+	var errLast error
+	for v, err := range sc.Seq() {
+		if err != nil {
+			errLast = err
+			continue
+		}
+		fmt.Fprintf(&bb, "%s;", v)
+	}
+	if errLast == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if s := bb.String(); s != `{"a":1};[2];"foo";` {
+		t.Fatalf("unexpected result: %s", s)
+	}
+
+	// Break must stop the scanning.
+	sc.Init(`1 2 3`)
+	for v := range sc.Seq() {
+		if v.GetInt() == 2 {
+			break
+		}
+	}
+	if !sc.Next() {
+		t.Fatalf("unexpected error: %v", sc.Error())
+	}
+	if n := sc.Value().GetInt(); n != 3 {
+		t.Fatalf("unexpected value after break; got %d; want 3", n)
+	}
+}
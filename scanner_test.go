@@ -1,9 +1,15 @@
 package fastjson
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestScanner(t *testing.T) {
@@ -37,3 +43,811 @@ func TestScanner(t *testing.T) {
 		}
 	})
 }
+
+func TestScannerNextRaw(t *testing.T) {
+	var sc Scanner
+
+	t.Run("success", func(t *testing.T) {
+		sc.Init(" [1, 2]\n{\"a\" : \"b\\nc\"}\n\"\" 123 true")
+		var records []string
+		for {
+			raw, ok := sc.NextRaw()
+			if !ok {
+				break
+			}
+			if sc.Value() != nil {
+				t.Fatalf("Value must return nil after NextRaw")
+			}
+			records = append(records, string(raw))
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		s := fmt.Sprintf("%q", records)
+		sExpected := `["[1, 2]" "{\"a\" : \"b\\nc\"}" "\"\"" "123" "true"]`
+		if s != sExpected {
+			t.Fatalf("unexpected records; got %s; want %s", s, sExpected)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		sc.Init(`{"a":1} {"b":2} {"c":3}`)
+		if !sc.Next() {
+			t.Fatalf("unexpected error: %s", sc.Error())
+		}
+		raw, ok := sc.NextRaw()
+		if !ok || string(raw) != `{"b":2}` {
+			t.Fatalf("unexpected raw record %q; ok=%v", raw, ok)
+		}
+		if !sc.Next() {
+			t.Fatalf("unexpected error: %s", sc.Error())
+		}
+		if n := sc.Value().GetInt("c"); n != 3 {
+			t.Fatalf("unexpected value; got %d; want 3", n)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		sc.Init(`[] [1,]`)
+		if _, ok := sc.NextRaw(); !ok {
+			t.Fatalf("unexpected error: %s", sc.Error())
+		}
+		if _, ok := sc.NextRaw(); ok {
+			t.Fatalf("expecting error")
+		}
+		if err := sc.Error(); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if _, ok := sc.NextRaw(); ok {
+			t.Fatalf("NextRaw must return false")
+		}
+	})
+}
+
+func TestScannerNextRawGrammar(t *testing.T) {
+	// NextRaw and Skip must accept exactly the values accepted by Next.
+	f := func(s string) {
+		t.Helper()
+		var sc Scanner
+		sc.Init(s)
+		var values []string
+		for sc.Next() {
+			values = append(values, sc.Value().String())
+		}
+		nextErr := sc.Error()
+
+		sc.Init(s)
+		var raws []string
+		for {
+			raw, ok := sc.NextRaw()
+			if !ok {
+				break
+			}
+			raws = append(raws, string(raw))
+		}
+		if (sc.Error() == nil) != (nextErr == nil) {
+			t.Fatalf("unexpected NextRaw error for %q; got %v; want %v", s, sc.Error(), nextErr)
+		}
+		if len(raws) != len(values) {
+			t.Fatalf("unexpected number of NextRaw values for %q; got %q; want %q", s, raws, values)
+		}
+		for i, raw := range raws {
+			if v := MustParse(raw).String(); v != values[i] {
+				t.Fatalf("unexpected NextRaw value #%d for %q; got %s; want %s", i, s, v, values[i])
+			}
+		}
+
+		sc.Init(s)
+		n := 0
+		for sc.Skip() {
+			n++
+		}
+		if (sc.Error() == nil) != (nextErr == nil) {
+			t.Fatalf("unexpected Skip error for %q; got %v; want %v", s, sc.Error(), nextErr)
+		}
+		if n != len(values) {
+			t.Fatalf("unexpected number of skipped values for %q; got %d; want %d", s, n, len(values))
+		}
+	}
+
+	// Lenient values accepted by Next
+	f(`1e 2`)
+	f(`[1e,-,+.]`)
+	f(`NaN -Inf {"a":nan}`)
+	f(`"\x" 1`)
+
+	// Valid values
+	f(`{"a" : [1, {"b":null}], "c":"d\"e"} true false null "" 0 []`)
+
+	// Invalid values
+	f(`1 [1,]`)
+	f(`{"a" 1}`)
+	f(`{"a":1,}`)
+	f(`{1:2}`)
+	f(`[1 2]`)
+	f(`tru`)
+	f(`nul`)
+	f(`"abc`)
+	f(`[` + strings.Repeat("[", MaxDepth) + strings.Repeat("]", MaxDepth+1))
+}
+
+func TestScannerSkip(t *testing.T) {
+	var sc Scanner
+	sc.Init(`{"type":"a"} {"type":"b","n":1} [1, 2] {"type":"c"}`)
+	var bb bytes.Buffer
+	n := 0
+	for {
+		ok := false
+		if n%2 == 0 {
+			ok = sc.Skip()
+		} else {
+			ok = sc.Next()
+			if ok {
+				fmt.Fprintf(&bb, "%s", sc.Value())
+			}
+		}
+		if !ok {
+			break
+		}
+		n++
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != `{"type":"b","n":1}{"type":"c"}` {
+		t.Fatalf("unexpected values; got %s", s)
+	}
+
+	sc.Init(`1 [`)
+	if !sc.Skip() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	if sc.Skip() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestScannerPeek(t *testing.T) {
+	var sc Scanner
+	sc.Init(`{"a":1} {"a":2} {"a":3} {"a":4}`)
+
+	v, ok := sc.Peek()
+	if !ok || v.GetInt("a") != 1 {
+		t.Fatalf("unexpected peeked value %s; ok=%v", v, ok)
+	}
+	// The second Peek must return the same value.
+	if v2, ok := sc.Peek(); !ok || v2 != v {
+		t.Fatalf("unexpected second peeked value %s; ok=%v", v2, ok)
+	}
+	if !sc.Next() || sc.Value() != v {
+		t.Fatalf("Next must return the peeked value")
+	}
+
+	// Skip must consume the peeked value.
+	if v, ok := sc.Peek(); !ok || v.GetInt("a") != 2 {
+		t.Fatalf("unexpected peeked value %s; ok=%v", v, ok)
+	}
+	if !sc.Skip() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+
+	// NextRaw must consume the peeked value.
+	if v, ok := sc.Peek(); !ok || v.GetInt("a") != 3 {
+		t.Fatalf("unexpected peeked value %s; ok=%v", v, ok)
+	}
+	raw, ok := sc.NextRaw()
+	if !ok || string(raw) != `{"a":3}` {
+		t.Fatalf("unexpected raw value %q; ok=%v", raw, ok)
+	}
+
+	if !sc.Next() || sc.Value().GetInt("a") != 4 {
+		t.Fatalf("unexpected value %s", sc.Value())
+	}
+	if _, ok := sc.Peek(); ok {
+		t.Fatalf("Peek must return false at the end")
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Init must drop the peeked value.
+	sc.Init(`1 2`)
+	if _, ok := sc.Peek(); !ok {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	sc.Init(`3`)
+	if !sc.Next() || sc.Value().GetInt() != 3 {
+		t.Fatalf("unexpected value %s", sc.Value())
+	}
+
+	sc.Init(`[1,]`)
+	if _, ok := sc.Peek(); ok {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestScannerInitReader(t *testing.T) {
+	f := func(s string, chunkSize int, resultExpected string) {
+		t.Helper()
+		var sc Scanner
+		sc.InitReader(&chunkedReader{
+			s:         s,
+			chunkSize: chunkSize,
+		})
+		var bb bytes.Buffer
+		for sc.Next() {
+			fmt.Fprintf(&bb, "%s;", sc.Value())
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error for chunkSize=%d: %s", chunkSize, err)
+		}
+		if result := bb.String(); result != resultExpected {
+			t.Fatalf("unexpected result for chunkSize=%d\ngot\n%s\nwant\n%s", chunkSize, result, resultExpected)
+		}
+	}
+
+	s := "{\"a\":\"foo\\nbar\"}\n[1,2,3] 123456 \"str\"\ntrue\n\n{\"b\":[{\"c\":null}]}\n"
+	resultExpected := `{"a":"foo\nbar"};[1,2,3];123456;"str";true;{"b":[{"c":null}]};`
+	for _, chunkSize := range []int{1, 2, 3, 7, 100, 1 << 20} {
+		f(s, chunkSize, resultExpected)
+	}
+	f("", 1, "")
+	f("   ", 1, "")
+
+	// Values bigger than the read buffer
+	big := `{"k":"` + strings.Repeat("x", 3*minReadSize) + `"}`
+	f(big+"\n"+big, 1000, big+";"+big+";")
+}
+
+func TestScannerInitReaderMixed(t *testing.T) {
+	var sc Scanner
+	sc.InitReader(&chunkedReader{
+		s:         `{"a":1} {"a":2} {"a":3} {"a":4}`,
+		chunkSize: 3,
+	})
+	if v, ok := sc.Peek(); !ok || v.GetInt("a") != 1 {
+		t.Fatalf("unexpected peeked value %s; err=%v", v, sc.Error())
+	}
+	if !sc.Next() || sc.Value().GetInt("a") != 1 {
+		t.Fatalf("unexpected value %s; err=%v", sc.Value(), sc.Error())
+	}
+	if !sc.Skip() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	raw, ok := sc.NextRaw()
+	if !ok || string(raw) != `{"a":3}` {
+		t.Fatalf("unexpected raw value %q; err=%v", raw, sc.Error())
+	}
+	if !sc.Next() || sc.Value().GetInt("a") != 4 {
+		t.Fatalf("unexpected value %s; err=%v", sc.Value(), sc.Error())
+	}
+	if sc.Next() {
+		t.Fatalf("expecting the end of stream")
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestScannerInitReaderError(t *testing.T) {
+	var sc Scanner
+
+	// Invalid JSON
+	sc.InitReader(&chunkedReader{
+		s:         `{"a":1} [1,]`,
+		chunkSize: 2,
+	})
+	if !sc.Next() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	if sc.Next() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	// Truncated JSON
+	sc.InitReader(&chunkedReader{
+		s:         `{"a":1} {"a":`,
+		chunkSize: 2,
+	})
+	if !sc.Next() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	if sc.Next() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	// Read error
+	sc.InitReader(&failingReader{})
+	if sc.Next() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil || err.Error() != "read error" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Init must reset the reader.
+	sc.Init(`1`)
+	if !sc.Next() || sc.Value().GetInt() != 1 {
+		t.Fatalf("unexpected value %s; err=%v", sc.Value(), sc.Error())
+	}
+}
+
+func TestScannerInitReaderSmallChunks(t *testing.T) {
+	// The value must be parsed a few times only when the reader
+	// returns it in small chunks such as TCP segments.
+	s := "[" + strings.Repeat(`{"foo":"bar","baz":[1,2,3]},`, 50000) + "null]"
+	var sc Scanner
+	sc.InitReader(&chunkedReader{
+		s:         s,
+		chunkSize: 1460,
+	})
+	parseCalls := 0
+	raw, _, ok := sc.scanValue(func(s string) (string, error) {
+		parseCalls++
+		sc.c.reset()
+		_, tail, err := parseValue(s, &sc.c, 0, nil)
+		return tail, err
+	})
+	if !ok {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+	if raw != s {
+		t.Fatalf("unexpected value with len=%d; want len=%d", len(raw), len(s))
+	}
+	if parseCalls > 20 {
+		t.Fatalf("too many parse calls for %d bytes value: %d", len(s), parseCalls)
+	}
+}
+
+func TestScannerInitReaderInteractive(t *testing.T) {
+	// Values must be returned as soon as they are followed by a newline
+	// without waiting for more data.
+	f := func(s string) {
+		t.Helper()
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		ch := make(chan string, 1)
+		go func() {
+			var sc Scanner
+			sc.InitReader(pr)
+			if !sc.Next() {
+				ch <- fmt.Sprintf("error: %v", sc.Error())
+				return
+			}
+			ch <- sc.Value().String()
+		}()
+		for len(s) > 0 {
+			n := 1460
+			if n > len(s) {
+				n = len(s)
+			}
+			if _, err := pw.Write([]byte(s[:n])); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			s = s[n:]
+		}
+		select {
+		case result := <-ch:
+			if strings.HasPrefix(result, "error: ") {
+				t.Fatalf("unexpected %s", result)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout")
+		}
+	}
+	f("{\"foo\":123}\n")
+	f("\"foo bar\"\n")
+	f("12345\n")
+	f(`[` + strings.Repeat(`{"a":"\"]}"},`, 20000) + "true]\n")
+	f(`"` + strings.Repeat("x", 3*minReadSize) + "\"\n")
+}
+
+// chunkedReader returns s in chunks of chunkSize bytes.
+type chunkedReader struct {
+	s         string
+	chunkSize int
+}
+
+func (cr *chunkedReader) Read(p []byte) (int, error) {
+	if len(cr.s) == 0 {
+		return 0, io.EOF
+	}
+	n := cr.chunkSize
+	if n > len(p) {
+		n = len(p)
+	}
+	if n > len(cr.s) {
+		n = len(cr.s)
+	}
+	copy(p, cr.s[:n])
+	cr.s = cr.s[n:]
+	return n, nil
+}
+
+type failingReader struct{}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func TestScannerJSONSeq(t *testing.T) {
+	f := func(s string, resultExpected string) {
+		t.Helper()
+		for _, chunkSize := range []int{0, 1, 3, 1000} {
+			var sc Scanner
+			sc.SetJSONSeq(true)
+			if chunkSize == 0 {
+				sc.Init(s)
+			} else {
+				sc.InitReader(&chunkedReader{
+					s:         s,
+					chunkSize: chunkSize,
+				})
+			}
+			var bb bytes.Buffer
+			for sc.Next() {
+				fmt.Fprintf(&bb, "%s;", sc.Value())
+			}
+			if err := sc.Error(); err != nil {
+				t.Fatalf("unexpected error for chunkSize=%d: %s", chunkSize, err)
+			}
+			if result := bb.String(); result != resultExpected {
+				t.Fatalf("unexpected result for chunkSize=%d\ngot\n%s\nwant\n%s", chunkSize, result, resultExpected)
+			}
+		}
+	}
+
+	f("", "")
+	f("\x1e{\"a\":1}\n\x1e[2]\n\x1e\"foo\"\n", `{"a":1};[2];"foo";`)
+	f("\x1e123\n\x1e456\n", `123;456;`)
+	f("\x1e\x1e\n\x1e true \n", `true;`)
+
+	// Malformed records must be skipped.
+	f("\x1e{\"a\":1\n\x1e[2]\n\x1e1 2\n\x1e3\n\x1e{", `[2];3;`)
+}
+
+func TestScannerJSONSeqError(t *testing.T) {
+	var sc Scanner
+	sc.SetJSONSeq(true)
+	sc.Init("{\"a\":1}\n")
+	if sc.Next() {
+		t.Fatalf("expecting error for missing record separator")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	// The mode must be disabled by SetJSONSeq(false).
+	sc.SetJSONSeq(false)
+	sc.Init("{\"a\":1}\n")
+	if !sc.Next() {
+		t.Fatalf("unexpected error: %s", sc.Error())
+	}
+}
+
+func TestScannerSetDelimiters(t *testing.T) {
+	f := func(delims, s, resultExpected string) {
+		t.Helper()
+		for _, chunkSize := range []int{0, 1, 3, 1000} {
+			var sc Scanner
+			sc.SetDelimiters(delims)
+			if chunkSize == 0 {
+				sc.Init(s)
+			} else {
+				sc.InitReader(&chunkedReader{
+					s:         s,
+					chunkSize: chunkSize,
+				})
+			}
+			var bb bytes.Buffer
+			for sc.Next() {
+				fmt.Fprintf(&bb, "%s|", sc.Value())
+			}
+			if err := sc.Error(); err != nil {
+				t.Fatalf("unexpected error for chunkSize=%d: %s", chunkSize, err)
+			}
+			if result := bb.String(); result != resultExpected {
+				t.Fatalf("unexpected result for chunkSize=%d\ngot\n%s\nwant\n%s", chunkSize, result, resultExpected)
+			}
+		}
+	}
+
+	f("", `1 2`, `1|2|`)
+	f("\x00", "{\"a\":1}\x00{\"b\":\"c\"}\x00\x00 [3]\x00", `{"a":1}|{"b":"c"}|[3]|`)
+	f(";,", `;1;;"a;b",[2];`, `1|"a;b"|[2]|`)
+
+	// Unknown delimiters must result in error.
+	var sc Scanner
+	sc.SetDelimiters(";")
+	sc.Init(`1;2,3`)
+	for sc.Next() {
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestScannerSetSplitFunc(t *testing.T) {
+	f := func(split bufio.SplitFunc, s, resultExpected string) {
+		t.Helper()
+		for _, chunkSize := range []int{0, 1, 3, 1000} {
+			var sc Scanner
+			sc.SetSplitFunc(split)
+			if chunkSize == 0 {
+				sc.Init(s)
+			} else {
+				sc.InitReader(&chunkedReader{
+					s:         s,
+					chunkSize: chunkSize,
+				})
+			}
+			var bb bytes.Buffer
+			for {
+				raw, ok := sc.NextRaw()
+				if !ok {
+					break
+				}
+				fmt.Fprintf(&bb, "%s|", raw)
+			}
+			if err := sc.Error(); err != nil {
+				t.Fatalf("unexpected error for chunkSize=%d: %s", chunkSize, err)
+			}
+			if result := bb.String(); result != resultExpected {
+				t.Fatalf("unexpected result for chunkSize=%d\ngot\n%s\nwant\n%s", chunkSize, result, resultExpected)
+			}
+		}
+	}
+
+	f(bufio.ScanLines, "", "")
+	f(bufio.ScanLines, "{\"a\":1}\n\n  [2] \r\n3", `{"a":1}|[2]|3|`)
+
+	splitPipe := func(data []byte, atEOF bool) (int, []byte, error) {
+		if n := bytes.IndexByte(data, '|'); n >= 0 {
+			return n + 1, data[:n], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+	f(splitPipe, `1|{"a":[2]}||"b"`, `1|{"a":[2]}|"b"|`)
+
+	splitFinal := func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if err == nil && string(token) == "2" {
+			return advance, token, bufio.ErrFinalToken
+		}
+		return advance, token, err
+	}
+	f(splitFinal, "1\n2\n3\n", `1|2|`)
+
+	// A record with multiple values must result in error.
+	var sc Scanner
+	sc.SetSplitFunc(bufio.ScanLines)
+	sc.Init("1\n2 3\n")
+	n := 0
+	for sc.Next() {
+		n++
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if n != 1 {
+		t.Fatalf("unexpected number of scanned values; got %d; want 1", n)
+	}
+
+	// Split errors must be returned.
+	sc.SetSplitFunc(func(data []byte, atEOF bool) (int, []byte, error) {
+		return 0, nil, fmt.Errorf("split error")
+	})
+	sc.Init("1\n")
+	if sc.Next() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestScannerForEach(t *testing.T) {
+	var sc Scanner
+	var bb bytes.Buffer
+	err := sc.ForEach(`{"a":1} [2] "foo"`, func(v *Value) error {
+		fmt.Fprintf(&bb, "%s;", v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := bb.String(); s != `{"a":1};[2];"foo";` {
+		t.Fatalf("unexpected result: %s", s)
+	}
+
+	// Errors from f must be returned.
+	errStop := errors.New("stop")
+	n := 0
+	err = sc.ForEachBytes([]byte(`1 2 3`), func(v *Value) error {
+		n++
+		if v.GetInt() == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("unexpected error; got %v; want %v", err, errStop)
+	}
+	if n != 2 {
+		t.Fatalf("unexpected number of visited values; got %d; want 2", n)
+	}
+
+	// Parse errors must be returned.
+	n = 0
+	err = sc.ForEach(`1 [2`, func(v *Value) error {
+		n++
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if n != 1 {
+		t.Fatalf("unexpected number of visited values; got %d; want 1", n)
+	}
+}
+
+func TestScannerValueCopy(t *testing.T) {
+	var sc Scanner
+	var a Arena
+	if v := sc.ValueCopy(&a); v != nil {
+		t.Fatalf("expecting nil value; got %s", v)
+	}
+
+	sc.InitReader(&chunkedReader{
+		s:         `{"id":1,"name":"foo"} {"id":2,"name":"bar"} {"id":3,"name":"baz\n"}`,
+		chunkSize: 5,
+	})
+	var kept []*Value
+	for sc.Next() {
+		if sc.Value().GetInt("id") != 2 {
+			kept = append(kept, sc.ValueCopy(&a))
+		}
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sc.Init(`{"id":4,"name":"xxxxxxxxxxxxxxxxxxxxxxxxx"}`)
+	for sc.Next() {
+	}
+
+	var bb bytes.Buffer
+	for _, v := range kept {
+		fmt.Fprintf(&bb, "%s;", v)
+	}
+	s := bb.String()
+	sExpected := `{"id":1,"name":"foo"};{"id":3,"name":"baz\n"};`
+	if s != sExpected {
+		t.Fatalf("unexpected retained values\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+}
+
+func TestScannerChan(t *testing.T) {
+	var sc Scanner
+	sc.Init(`{"a":1} [2] "foo" [`)
+	var values []*Value
+	var errLast error
+	for r := range sc.Chan(context.Background()) {
+		if r.Err != nil {
+			errLast = r.Err
+			continue
+		}
+		values = append(values, r.Value)
+	}
+	if errLast == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	var bb bytes.Buffer
+	for _, v := range values {
+		fmt.Fprintf(&bb, "%s;", v)
+	}
+	if s := bb.String(); s != `{"a":1};[2];"foo";` {
+		t.Fatalf("unexpected result: %s", s)
+	}
+
+	// Canceled context must stop the scanning.
+	ctx, cancel := context.WithCancel(context.Background())
+	sc.Init(`1 2 3 4 5`)
+	ch := sc.Chan(ctx)
+	r := <-ch
+	if r.Err != nil || r.Value.GetInt() != 1 {
+		t.Fatalf("unexpected result: %v, %v", r.Value, r.Err)
+	}
+	cancel()
+	n := 0
+	for range ch {
+		n++
+	}
+	if n > 1 {
+		t.Fatalf("too many values received after the cancellation: %d", n)
+	}
+}
+
+func TestScannerFollowAppend(t *testing.T) {
+	var sc Scanner
+	sc.SetFollow(true)
+	sc.Init("")
+
+	var bb bytes.Buffer
+	scanAll := func() {
+		t.Helper()
+		for sc.Next() {
+			fmt.Fprintf(&bb, "%s;", sc.Value())
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	for _, chunk := range []string{`{"a":`, `1} [1,`, `2`, `] 12`, `3 "fo`, "o\"\n", `tr`, `ue `} {
+		sc.Append([]byte(chunk))
+		scanAll()
+	}
+	resultExpected := `{"a":1};[1,2];123;"foo";true;`
+	if result := bb.String(); result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	// Malformed values must result in error.
+	sc.Append([]byte(`[1 2] 3`))
+	if sc.Next() {
+		t.Fatalf("expecting error")
+	}
+	if err := sc.Error(); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestScannerFollowReader(t *testing.T) {
+	var sc Scanner
+	sc.SetFollow(true)
+	var r bytes.Buffer
+	sc.InitReader(&r)
+
+	var bb bytes.Buffer
+	for _, chunk := range []string{`{"a":[`, `"b"]}`, "\n", `[`, `]`, "\n"} {
+		r.WriteString(chunk)
+		for sc.Next() {
+			fmt.Fprintf(&bb, "%s;", sc.Value())
+		}
+		if err := sc.Error(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	resultExpected := `{"a":["b"]};[];`
+	if result := bb.String(); result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	// Peek must work with appended data.
+	sc.Append([]byte(`{"x":`))
+	if _, ok := sc.Peek(); ok {
+		t.Fatalf("unexpected value for incomplete data")
+	}
+	sc.Append([]byte(`1}`))
+	v, ok := sc.Peek()
+	if !ok {
+		t.Fatalf("unexpected error: %v", sc.Error())
+	}
+	if n := v.GetInt("x"); n != 1 {
+		t.Fatalf("unexpected value; got %d; want 1", n)
+	}
+}
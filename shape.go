@@ -0,0 +1,429 @@
+package fastjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Shape describes the expected structure of a Value.
+//
+// Shapes are lightweight alternative to JSON Schema for request validation.
+// Use basic shapes such as Number() and String(), and build composite
+// shapes via ArrayOf, ObjectShape and Field calls:
+//
+//	user := ObjectShape().Field("id", Number()).Field("tags", ArrayOf(String()))
+//	err := user.Validate(v)
+//
+// Shapes are immutable, so they may be shared and used concurrently
+// from multiple goroutines. Methods building shapes return new shapes.
+type Shape struct {
+	kind     shapeKind
+	nullable bool
+
+	// item is the shape of array items.
+	item *Shape
+
+	// fields contains the expected object fields.
+	fields []shapeField
+}
+
+type shapeKind int
+
+const (
+	shapeAny shapeKind = iota
+	shapeNull
+	shapeBool
+	shapeNumber
+	shapeString
+	shapeArray
+	shapeObject
+)
+
+func (k shapeKind) String() string {
+	switch k {
+	case shapeNull:
+		return "null"
+	case shapeBool:
+		return "bool"
+	case shapeNumber:
+		return "number"
+	case shapeString:
+		return "string"
+	case shapeArray:
+		return "array"
+	case shapeObject:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+type shapeField struct {
+	key      string
+	s        *Shape
+	optional bool
+}
+
+var (
+	anyShape    = &Shape{kind: shapeAny}
+	nullShape   = &Shape{kind: shapeNull}
+	boolShape   = &Shape{kind: shapeBool}
+	numberShape = &Shape{kind: shapeNumber}
+	stringShape = &Shape{kind: shapeString}
+	arrayShape  = ArrayOf(anyShape)
+)
+
+// Any returns a shape matching any value.
+func Any() *Shape {
+	return anyShape
+}
+
+// Null returns a shape matching null.
+func Null() *Shape {
+	return nullShape
+}
+
+// Bool returns a shape matching true and false.
+func Bool() *Shape {
+	return boolShape
+}
+
+// Number returns a shape matching numbers.
+func Number() *Shape {
+	return numberShape
+}
+
+// String returns a shape matching strings.
+func String() *Shape {
+	return stringShape
+}
+
+// Array returns a shape matching arrays with arbitrary items.
+func Array() *Shape {
+	return arrayShape
+}
+
+// ArrayOf returns a shape matching arrays with items matching item.
+//
+// The function panics if item is nil.
+func ArrayOf(item *Shape) *Shape {
+	if item == nil {
+		panic(fmt.Errorf("cannot create array shape: item shape cannot be nil"))
+	}
+	return &Shape{
+		kind: shapeArray,
+		item: item,
+	}
+}
+
+// ObjectShape returns a shape matching objects.
+//
+// The expected object fields may be added via Field and OptionalField.
+// Fields not mentioned in the shape are allowed.
+func ObjectShape() *Shape {
+	return &Shape{
+		kind: shapeObject,
+	}
+}
+
+// Field returns a copy of the object shape s with the required field key
+// matching fs.
+//
+// The field must exist and mustn't be null unless fs is Nullable.
+// s is converted to object shape if it isn't an object shape.
+// The function panics if fs is nil.
+func (s *Shape) Field(key string, fs *Shape) *Shape {
+	return s.withField(key, fs, false)
+}
+
+// OptionalField returns a copy of the object shape s with the optional
+// field key matching fs.
+//
+// The field may be missing or null. The function panics if fs is nil.
+func (s *Shape) OptionalField(key string, fs *Shape) *Shape {
+	return s.withField(key, fs, true)
+}
+
+func (s *Shape) withField(key string, fs *Shape, optional bool) *Shape {
+	if fs == nil {
+		panic(fmt.Errorf("cannot add field %q to object shape: field shape cannot be nil", key))
+	}
+	ns := &Shape{
+		kind:     shapeObject,
+		nullable: s.nullable,
+	}
+	if s.kind == shapeObject {
+		ns.fields = append(ns.fields, s.fields...)
+	}
+	ns.fields = append(ns.fields, shapeField{
+		key:      key,
+		s:        fs,
+		optional: optional,
+	})
+	return ns
+}
+
+// Nullable returns a copy of s, which matches null too.
+func (s *Shape) Nullable() *Shape {
+	ns := *s
+	ns.nullable = true
+	return &ns
+}
+
+// Validate checks whether v matches s.
+//
+// ShapeErrors with all the mismatches is returned if v doesn't match s.
+// If an object contains duplicate keys, then every member with the field
+// key must match the field shape, in the same way as Parser.SetShape
+// requires.
+func (s *Shape) Validate(v *Value) error {
+	var errs ShapeErrors
+	var path []string
+	if v == nil {
+		errs.add(path, "missing value")
+	} else {
+		s.validate(v, &path, &errs)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Shape) validate(v *Value, path *[]string, errs *ShapeErrors) {
+	t := v.Type()
+	if s.kind == shapeAny || t == TypeNull && s.nullable {
+		return
+	}
+	if !s.matchesType(t) {
+		errs.add(*path, "expecting %s; got %s", s.kind, t)
+		return
+	}
+
+	switch s.kind {
+	case shapeArray:
+		if s.item.kind == shapeAny {
+			return
+		}
+		for i, item := range v.a {
+			*path = append(*path, strconv.Itoa(i))
+			s.item.validate(item, path, errs)
+			*path = (*path)[:len(*path)-1]
+		}
+	case shapeObject:
+		v.o.unescapeKeys()
+		for _, f := range s.fields {
+			*path = append(*path, f.key)
+			found := false
+			for _, kv := range v.o.kvs {
+				// All the members with duplicate keys are checked
+				// in the same way as Parser.SetShape checks them.
+				if kv.k == f.key {
+					found = true
+					f.validate(kv.v, path, errs)
+				}
+			}
+			if !found && !f.optional {
+				errs.add(*path, "missing required field")
+			}
+			*path = (*path)[:len(*path)-1]
+		}
+	}
+}
+
+func (f *shapeField) validate(fv *Value, path *[]string, errs *ShapeErrors) {
+	if fv.Type() == TypeNull && !f.s.nullable && f.s.kind != shapeNull && f.s.kind != shapeAny {
+		if !f.optional {
+			errs.add(*path, "required field is null")
+		}
+		return
+	}
+	f.s.validate(fv, path, errs)
+}
+
+// checkRawValue checks whether the type of JSON value at the start of raw
+// matches s. It is used by Parser for early shape checks.
+func (s *Shape) checkRawValue(raw string) error {
+	if s.kind == shapeAny {
+		return nil
+	}
+	var t Type
+	switch raw[0] {
+	case '{':
+		t = TypeObject
+	case '[':
+		t = TypeArray
+	case '"':
+		t = TypeString
+	case 't':
+		t = TypeTrue
+	case 'f':
+		t = TypeFalse
+	case 'n':
+		t = TypeNumber
+		if strings.HasPrefix(raw, "null") {
+			t = TypeNull
+		}
+	default:
+		t = TypeNumber
+	}
+	if s.matchesType(t) {
+		return nil
+	}
+	return fmt.Errorf("value doesn't match shape: expecting %s; got %s", s.kind, t)
+}
+
+// rawFieldShape returns the shape for the object field with the given raw key
+// and the raw value at the start of rawValue.
+//
+// nil is returned if the field value mustn't be checked.
+func (s *Shape) rawFieldShape(rawKey, rawValue string) *Shape {
+	if s.kind != shapeObject || len(s.fields) == 0 {
+		return nil
+	}
+	key := rawKey
+	if strings.IndexByte(key, '\\') >= 0 {
+		b := append([]byte(nil), key...)
+		key = unescapeStringBestEffort(b2s(b))
+	}
+	for i := range s.fields {
+		f := &s.fields[i]
+		if f.key != key {
+			continue
+		}
+		if f.optional && strings.HasPrefix(rawValue, "null") {
+			return nil
+		}
+		return f.s
+	}
+	return nil
+}
+
+// checkRequiredFields checks whether o contains all the required s fields.
+func (s *Shape) checkRequiredFields(o *Object) error {
+	if s.kind != shapeObject {
+		return nil
+	}
+	for _, f := range s.fields {
+		if !f.optional && o.Get(f.key) == nil {
+			return fmt.Errorf("value doesn't match shape: missing required field %q", f.key)
+		}
+	}
+	return nil
+}
+
+// matchesType returns true if values of type t match s.
+//
+// Nested array items and object fields aren't checked.
+func (s *Shape) matchesType(t Type) bool {
+	if t == TypeNull && s.nullable {
+		return true
+	}
+	switch s.kind {
+	case shapeAny:
+		return true
+	case shapeNull:
+		return t == TypeNull
+	case shapeBool:
+		return t == TypeTrue || t == TypeFalse
+	case shapeNumber:
+		return t == TypeNumber
+	case shapeString:
+		return t == TypeString
+	case shapeArray:
+		return t == TypeArray
+	case shapeObject:
+		return t == TypeObject
+	default:
+		return false
+	}
+}
+
+// ShapeError describes a value mismatching the expected Shape.
+type ShapeError struct {
+	// Path is the keys path to the mismatching value.
+	Path []string
+
+	// Msg describes the mismatch.
+	Msg string
+}
+
+// Error implements error interface.
+func (e *ShapeError) Error() string {
+	return fmt.Sprintf("%q: %s", FormatPointer(e.Path...), e.Msg)
+}
+
+// ShapeErrors is returned by Shape.Validate and Value.Require.
+// It contains all the mismatches.
+type ShapeErrors []*ShapeError
+
+// Error implements error interface.
+func (es ShapeErrors) Error() string {
+	a := make([]string, len(es))
+	for i, e := range es {
+		a[i] = e.Error()
+	}
+	return strings.Join(a, "; ")
+}
+
+func (es *ShapeErrors) add(path []string, format string, args ...interface{}) {
+	*es = append(*es, &ShapeError{
+		Path: append([]string(nil), path...),
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Expectation is a fluent helper for validating v against a Shape.
+//
+// See Expect for details.
+type Expectation struct {
+	v *Value
+	s *Shape
+}
+
+// Expect returns an Expectation for v.
+//
+// Usage:
+//
+//	err := Expect(v).Object().Field("id", Number()).Field("tags", ArrayOf(String())).Validate()
+func Expect(v *Value) *Expectation {
+	return &Expectation{
+		v: v,
+		s: anyShape,
+	}
+}
+
+// Object expects e value to be an object.
+func (e *Expectation) Object() *Expectation {
+	if e.s.kind != shapeObject {
+		e.s = ObjectShape()
+	}
+	return e
+}
+
+// Field expects e value to be an object with the required field key
+// matching s.
+func (e *Expectation) Field(key string, s *Shape) *Expectation {
+	e.s = e.s.Field(key, s)
+	return e
+}
+
+// OptionalField expects e value to be an object with the optional field key
+// matching s.
+func (e *Expectation) OptionalField(key string, s *Shape) *Expectation {
+	e.s = e.s.OptionalField(key, s)
+	return e
+}
+
+// Shape returns the shape built for e.
+func (e *Expectation) Shape() *Shape {
+	return e.s
+}
+
+// Validate checks whether e value matches the expected shape.
+//
+// ShapeErrors with all the mismatches is returned on failure.
+func (e *Expectation) Validate() error {
+	return e.s.Validate(e.v)
+}
@@ -0,0 +1,188 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShapeValidate(t *testing.T) {
+	f := func(s string, shape *Shape, errExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		err := shape.Validate(v)
+		errStr := ""
+		if err != nil {
+			errStr = err.Error()
+		}
+		if errStr != errExpected {
+			t.Fatalf("unexpected error for %s\ngot\n%s\nwant\n%s", s, errStr, errExpected)
+		}
+	}
+
+	// Scalars
+	f(`1`, Number(), ``)
+	f(`"x"`, Number(), `"": expecting number; got string`)
+	f(`true`, Bool(), ``)
+	f(`false`, Bool(), ``)
+	f(`null`, Null(), ``)
+	f(`null`, String(), `"": expecting string; got null`)
+	f(`null`, String().Nullable(), ``)
+	f(`{"a":[1]}`, Any(), ``)
+	f(`"ab"`, String(), ``)
+
+	// Arrays
+	f(`[]`, Array(), ``)
+	f(`{}`, Array(), `"": expecting array; got object`)
+	f(`["a","b"]`, ArrayOf(String()), ``)
+	f(`["a",1,"b",null]`, ArrayOf(String()), `"/1": expecting string; got number; "/3": expecting string; got null`)
+
+	// Objects
+	user := ObjectShape().
+		Field("id", Number()).
+		Field("tags", ArrayOf(String())).
+		OptionalField("email", String()).
+		Field("address", ObjectShape().Field("city", String())).
+		Field("note", String().Nullable())
+	f(`{"id":1,"tags":["a"],"address":{"city":"x"},"note":null,"extra":true}`, user, ``)
+	f(`{"id":1,"tags":["a"],"email":"e","address":{"city":"x"},"note":"n"}`, user, ``)
+	f(`{"id":"1","tags":[1],"email":2,"address":{}}`, user,
+		`"/id": expecting number; got string; "/tags/0": expecting string; got number; "/email": expecting string; got number; "/address/city": missing required field; "/note": missing required field`)
+	f(`{"id":null,"tags":null,"email":null,"address":[],"note":null}`, user,
+		`"/id": required field is null; "/tags": required field is null; "/address": expecting object; got array`)
+	f(`[]`, user, `"": expecting object; got array`)
+	f(`{"a/b":{"c":1}}`, ObjectShape().Field("a/b", ObjectShape().Field("c", String())), `"/a~1b/c": expecting string; got number`)
+
+	// Null fields
+	f(`{"a":null}`, ObjectShape().Field("a", Null()), ``)
+	f(`{"a":null}`, ObjectShape().Field("a", Any()), ``)
+	f(`{}`, ObjectShape().Field("a", Any()), `"/a": missing required field`)
+
+	// Duplicate keys
+	f(`{"id":1,"id":"x"}`, ObjectShape().Field("id", Number()), `"/id": expecting number; got string`)
+	f(`{"id":null,"id":1}`, ObjectShape().Field("id", Number()), `"/id": required field is null`)
+	f(`{"id":1,"id":2}`, ObjectShape().Field("id", Number()), ``)
+
+	// Field converts non-object shapes to object shapes.
+	f(`{"a":1}`, Number().Field("a", Number()), ``)
+
+	// Shapes are immutable.
+	base := ObjectShape().Field("a", Number())
+	_ = base.Field("b", Number())
+	f(`{"a":1}`, base, ``)
+	_ = String().Nullable()
+	f(`null`, String(), `"": expecting string; got null`)
+
+	if err := Number().Validate(nil); err == nil {
+		t.Fatalf("expecting non-nil error for nil value")
+	}
+
+	// nil shapes must be rejected at construction time.
+	if !causesPanic(func() { ArrayOf(nil) }) {
+		t.Fatalf("expecting ArrayOf to panic on nil shape")
+	}
+	if !causesPanic(func() { ObjectShape().Field("a", nil) }) {
+		t.Fatalf("expecting Field to panic on nil shape")
+	}
+	if !causesPanic(func() { ObjectShape().OptionalField("a", nil) }) {
+		t.Fatalf("expecting OptionalField to panic on nil shape")
+	}
+	if !causesPanic(func() { Expect(MustParse(`{}`)).Field("a", nil) }) {
+		t.Fatalf("expecting Expectation.Field to panic on nil shape")
+	}
+}
+
+func TestExpect(t *testing.T) {
+	v := MustParse(`{"id":"x","tags":["a",2]}`)
+	err := Expect(v).Object().Field("id", Number()).Field("tags", ArrayOf(String())).OptionalField("n", Null()).Validate()
+	es, ok := err.(ShapeErrors)
+	if !ok {
+		t.Fatalf("unexpected error type %T: %v", err, err)
+	}
+	if len(es) != 2 {
+		t.Fatalf("unexpected number of errors; got %d; want 2: %s", len(es), es)
+	}
+	if p := FormatPointer(es[1].Path...); p != "/tags/1" {
+		t.Fatalf("unexpected path; got %q; want %q", p, "/tags/1")
+	}
+
+	if err := Expect(v).Object().Field("tags", Array()).Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := Expect(MustParse(`[]`)).Object().Validate(); err == nil {
+		t.Fatalf("expecting non-nil error for array")
+	}
+	if s := Expect(v).Field("id", String()).Shape(); s.Validate(v) != nil {
+		t.Fatalf("unexpected error: %s", s.Validate(v))
+	}
+}
+
+func TestParserSetShape(t *testing.T) {
+	user := ObjectShape().
+		Field("id", Number()).
+		Field("tags", ArrayOf(String())).
+		OptionalField("email", String()).
+		Field("note", String().Nullable())
+
+	var p Parser
+	p.SetShape(user)
+	f := func(s string, errExpected string) {
+		t.Helper()
+		v, err := p.Parse(s)
+		if errExpected == "" {
+			if err != nil {
+				t.Fatalf("unexpected error for %s: %s", s, err)
+			}
+			// Parse and Shape.Validate must agree.
+			if err := user.Validate(v); err != nil {
+				t.Fatalf("unexpected Validate error for %s: %s", s, err)
+			}
+			return
+		}
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+		if !strings.Contains(err.Error(), errExpected) {
+			t.Fatalf("unexpected error for %s; got %q; want error containing %q", s, err, errExpected)
+		}
+		if err := user.Validate(MustParse(s)); err == nil {
+			t.Fatalf("expecting non-nil Validate error for %s", s)
+		}
+	}
+
+	f(`{"id":1,"tags":["a"],"note":null}`, "")
+	f(`{"id":1,"tags":[],"email":null,"note":"x","extra":{"a":[1]}}`, "")
+	f(`{"id":1,"tags":[],"note":null}`, "")
+	f(`[]`, "expecting object; got array")
+	f(`{"id":"1","tags":[],"note":null}`, `key "id": value doesn't match shape: expecting number; got string`)
+	f(`{"id":null,"tags":[],"note":null}`, `expecting number; got null`)
+	f(`{"id":1,"tags":["a",2],"note":null}`, `expecting string; got number`)
+	f(`{"id":1,"tags":["a"],"email":true,"note":null}`, `expecting string; got true`)
+	f(`{"id":1,"note":null}`, `missing required field "tags"`)
+	f(`{}`, `missing required field "id"`)
+
+	// Every member with duplicate keys is checked.
+	f(`{"id":1,"id":"x","tags":[],"note":null}`, `expecting number; got string`)
+	f(`{"id":"x","id":1,"tags":[],"note":null}`, `expecting number; got string`)
+	f(`{"id":1,"tags":[],"tags":["a",1],"note":null}`, `expecting string; got number`)
+	f(`{"id":1,"id":2,"tags":[],"note":null,"note":"x"}`, "")
+
+	// The rest of JSON isn't parsed after the mismatch.
+	if _, err := p.Parse(`{"id":"x","tags":[garbage`); err == nil || !strings.Contains(err.Error(), "expecting number; got string") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Nested objects
+	p.SetShape(ArrayOf(ObjectShape().Field("n", Number().Nullable())))
+	if _, err := p.Parse(`[{"n":1},{"n":null},{"n":2,"m":"x"}]`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := p.Parse(`[{"n":1},{"m":2}]`); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	// Reset the shape
+	p.SetShape(nil)
+	if _, err := p.Parse(`[]`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,141 @@
+package fastjson
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// SortArray sorts the array v in place according to less.
+//
+// The sort is stable. v is left unchanged if it isn't an array.
+func (v *Value) SortArray(less func(a, b *Value) bool) {
+	if v == nil || v.t != TypeArray {
+		return
+	}
+	a := v.a
+	sort.SliceStable(a, func(i, j int) bool {
+		return less(a[i], a[j])
+	})
+}
+
+// SortArrayByPath sorts the array v in place by values at the given
+// keys path in the array items.
+//
+// Numbers are compared numerically and strings are compared lexicographically.
+// Values of distinct types are ordered as null < false < true < number < string
+// < array < object. Items without the given path are moved to the end.
+//
+// The sort is stable. v is left unchanged if it isn't an array.
+func (v *Value) SortArrayByPath(path ...string) {
+	v.SortArray(func(a, b *Value) bool {
+		return compareValues(a.Get(path...), b.Get(path...)) < 0
+	})
+}
+
+// Order is the sort order for SortByPath.
+type Order int
+
+const (
+	// Ascending sorts values from the smallest to the biggest.
+	Ascending Order = iota
+
+	// Descending sorts values from the biggest to the smallest.
+	Descending
+)
+
+// SortByPath sorts arr in place by values at the given path in arr items.
+//
+// Values are compared as in Value.SortArrayByPath. Items without the given
+// path are moved to the end regardless of order.
+//
+// The sort is stable.
+func SortByPath(arr []*Value, path []string, order Order) {
+	sort.SliceStable(arr, func(i, j int) bool {
+		a, b := arr[i].Get(path...), arr[j].Get(path...)
+		if order == Descending && a != nil && b != nil {
+			a, b = b, a
+		}
+		return compareValues(a, b) < 0
+	})
+}
+
+// compareValues returns -1 if a < b, 0 if a == b and 1 if a > b.
+//
+// See SortArrayByPath for the ordering rules. nil is bigger than any value.
+func compareValues(a, b *Value) int {
+	if a == nil || b == nil {
+		switch {
+		case a == b:
+			return 0
+		case a == nil:
+			return 1
+		default:
+			return -1
+		}
+	}
+	ra, rb := typeRank(a.Type()), typeRank(b.Type())
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	switch a.t {
+	case TypeNumber:
+		fa := fastfloat.ParseBestEffort(a.s)
+		fb := fastfloat.ParseBestEffort(b.s)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	case TypeString:
+		return strings.Compare(a.s, b.s)
+	case TypeArray:
+		for i := 0; i < len(a.a) && i < len(b.a); i++ {
+			if n := compareValues(a.a[i], b.a[i]); n != 0 {
+				return n
+			}
+		}
+		return compareInts(len(a.a), len(b.a))
+	case TypeObject:
+		return compareInts(a.o.Len(), b.o.Len())
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func typeRank(t Type) int {
+	switch t {
+	case TypeNull:
+		return 0
+	case TypeFalse:
+		return 1
+	case TypeTrue:
+		return 2
+	case TypeNumber:
+		return 3
+	case TypeString:
+		return 4
+	case TypeArray:
+		return 5
+	default:
+		return 6
+	}
+}
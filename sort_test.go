@@ -0,0 +1,80 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueSortArray(t *testing.T) {
+	v := MustParse(`[3,1,2,10,-5]`)
+	v.SortArray(func(a, b *Value) bool {
+		return a.GetInt() > b.GetInt()
+	})
+	if s := v.String(); s != `[10,3,2,1,-5]` {
+		t.Fatalf("unexpected sorted array; got %s; want %s", s, `[10,3,2,1,-5]`)
+	}
+
+	// Non-arrays must remain unchanged.
+	o := MustParse(`{"b":1,"a":2}`)
+	o.SortArray(func(a, b *Value) bool { return true })
+	if s := o.String(); s != `{"b":1,"a":2}` {
+		t.Fatalf("unexpected object; got %s", s)
+	}
+	var vNil *Value
+	vNil.SortArray(func(a, b *Value) bool { return true })
+}
+
+func TestValueSortArrayByPath(t *testing.T) {
+	f := func(s string, path []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.SortArrayByPath(path...)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s sorted by %q\ngot\n%s\nwant\n%s", s, path, result, resultExpected)
+		}
+	}
+
+	// Numbers
+	f(`[{"n":10},{"n":9},{"n":-1.5},{"n":1e2}]`, []string{"n"}, `[{"n":-1.5},{"n":9},{"n":10},{"n":1e2}]`)
+
+	// Strings
+	f(`[{"s":"b"},{"s":"a\n"},{"s":"a"}]`, []string{"s"}, `[{"s":"a"},{"s":"a\n"},{"s":"b"}]`)
+
+	// Nested path with missing items, which must go last in the original order.
+	f(`[{"id":1},{"a":{"b":2},"id":2},{"id":3},{"a":{"b":1},"id":4}]`, []string{"a", "b"}, `[{"a":{"b":1},"id":4},{"a":{"b":2},"id":2},{"id":1},{"id":3}]`)
+
+	// Mixed types
+	f(`["x",1,null,true,[],{},false]`, nil, `[null,false,true,1,"x",[],{}]`)
+
+	// Arrays are compared item by item.
+	f(`[[1,2],[1],[0,5]]`, nil, `[[0,5],[1],[1,2]]`)
+
+	// Stability
+	f(`[{"k":1,"i":0},{"k":0,"i":1},{"k":1,"i":2},{"k":0,"i":3}]`, []string{"k"}, `[{"k":0,"i":1},{"k":0,"i":3},{"k":1,"i":0},{"k":1,"i":2}]`)
+}
+
+func TestSortByPath(t *testing.T) {
+	f := func(s string, path []string, order Order, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		arr := v.GetArray()
+		SortByPath(arr, path, order)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s sorted by %q in order %d\ngot\n%s\nwant\n%s", s, path, order, result, resultExpected)
+		}
+	}
+
+	f(`[{"n":10},{"n":9},{"n":-1.5},{"n":1e2}]`, []string{"n"}, Ascending, `[{"n":-1.5},{"n":9},{"n":10},{"n":1e2}]`)
+	f(`[{"n":10},{"n":9},{"n":-1.5},{"n":1e2}]`, []string{"n"}, Descending, `[{"n":1e2},{"n":10},{"n":9},{"n":-1.5}]`)
+	f(`[{"a":{"s":"b"}},{"a":{"s":"a"}},{"a":{"s":"c"}}]`, []string{"a", "s"}, Descending, `[{"a":{"s":"c"}},{"a":{"s":"b"}},{"a":{"s":"a"}}]`)
+
+	// Missing items go to the end in both orders
+	f(`[{},{"n":1},{"x":1},{"n":2}]`, []string{"n"}, Ascending, `[{"n":1},{"n":2},{},{"x":1}]`)
+	f(`[{},{"n":1},{"x":1},{"n":2}]`, []string{"n"}, Descending, `[{"n":2},{"n":1},{},{"x":1}]`)
+
+	// Stability
+	f(`[{"n":1,"i":0},{"n":0},{"n":1,"i":1},{"n":1,"i":2}]`, []string{"n"}, Descending, `[{"n":1,"i":0},{"n":1,"i":1},{"n":1,"i":2},{"n":0}]`)
+
+	SortByPath(nil, []string{"n"}, Ascending)
+}
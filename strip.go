@@ -0,0 +1,65 @@
+package fastjson
+
+// StripNulls deletes object members with null values throughout v.
+//
+// Objects are traversed recursively at any depth, including objects
+// inside arrays. Array items are never deleted, since this would
+// shift indexes of the remaining items.
+func (v *Value) StripNulls() {
+	v.strip(false)
+}
+
+// StripEmpty deletes object members with null values, empty objects
+// and empty arrays throughout v.
+//
+// Members, which become empty after stripping their contents, are deleted
+// too. Array items are never deleted, since this would shift indexes
+// of the remaining items.
+func (v *Value) StripEmpty() {
+	v.strip(true)
+}
+
+func (v *Value) strip(stripEmpty bool) {
+	if v == nil {
+		return
+	}
+	switch v.Type() {
+	case TypeObject:
+		o := &v.o
+		o.unescapeKeys()
+		kvs := o.kvs[:0]
+		for _, kv := range o.kvs {
+			kv.v.strip(stripEmpty)
+			if isStrippable(kv.v, stripEmpty) {
+				continue
+			}
+			kvs = append(kvs, kv)
+		}
+		if len(kvs) < len(o.kvs) {
+			o.idx = nil
+		}
+		tail := o.kvs[len(kvs):]
+		for i := range tail {
+			// Release references to the deleted items.
+			tail[i] = kv{}
+		}
+		o.kvs = kvs
+	case TypeArray:
+		for _, item := range v.a {
+			item.strip(stripEmpty)
+		}
+	}
+}
+
+func isStrippable(v *Value, stripEmpty bool) bool {
+	switch v.Type() {
+	case TypeNull:
+		return true
+	case TypeObject:
+		return stripEmpty && v.o.Len() == 0
+	case TypeArray:
+		return stripEmpty && len(v.a) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,47 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueStripNulls(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.StripNulls()
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for StripNulls on %s\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":null,"b":1}`, `{"b":1}`)
+	f(`{"a":{"b":null,"c":{}},"d":[null,{"e":null}]}`, `{"a":{"c":{}},"d":[null,{}]}`)
+	f(`{"a":null}`, `{}`)
+	f(`[null]`, `[null]`)
+	f(`null`, `null`)
+
+	var vNil *Value
+	vNil.StripNulls()
+}
+
+func TestValueStripEmpty(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.StripEmpty()
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for StripEmpty on %s\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":null,"b":1,"c":{},"d":[],"e":""}`, `{"b":1,"e":""}`)
+	f(`{"a":{"b":null,"c":{"d":[]}},"e":1}`, `{"e":1}`)
+	f(`{"a":[{},[],null,{"b":null}],"c":[[]]}`, `{"a":[{},[],null,{}],"c":[[]]}`)
+	f(`{"a":0,"b":false}`, `{"a":0,"b":false}`)
+	f(`{}`, `{}`)
+
+	var vNil *Value
+	vNil.StripEmpty()
+}
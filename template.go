@@ -0,0 +1,165 @@
+package fastjson
+
+import (
+	"encoding/json"
+)
+
+// TemplateData returns v wrapped for use as data in text/template
+// and html/template.
+//
+// Values are converted lazily, so only the parts of v accessed by the template
+// are materialized:
+//
+//   - objects are wrapped into TemplateObject;
+//   - arrays are wrapped into TemplateArray;
+//   - strings are converted to string;
+//   - numbers are converted to json.Number, so they are printed as is;
+//   - true and false are converted to bool;
+//   - null is converted to nil.
+//
+// nil is returned if v is nil.
+func (v *Value) TemplateData() interface{} {
+	if v == nil {
+		return nil
+	}
+	switch v.Type() {
+	case TypeObject:
+		return TemplateObject{
+			v: v,
+		}
+	case TypeArray:
+		return TemplateArray{
+			v: v,
+		}
+	case TypeString:
+		return v.s
+	case TypeNumber:
+		return json.Number(v.s)
+	case TypeTrue:
+		return true
+	case TypeFalse:
+		return false
+	default:
+		return nil
+	}
+}
+
+// TemplateObject is a JSON object wrapped for use in templates.
+//
+// Templates cannot access object members via field syntax such as .user.name,
+// since it requires materializing maps for all the nested objects.
+// Use Get instead:
+//
+//	{{.Get "user" "name"}}
+//	{{range (.Get "items").Items}}{{.Get "id"}}{{end}}
+//	{{range $k := .Keys}}{{$k}}={{$.Get $k}}{{end}}
+//
+// TemplateObject is valid while the underlying Value is valid.
+// The underlying Value mustn't be modified while the template is executed.
+type TemplateObject struct {
+	v *Value
+}
+
+// Get returns the value at the given keys path converted by Value.TemplateData.
+//
+// Array indexes may be passed as decimal strings. nil is returned
+// for non-existing paths.
+func (to TemplateObject) Get(keys ...string) interface{} {
+	return to.v.Get(keys...).TemplateData()
+}
+
+// Keys returns object keys in the original order.
+func (to TemplateObject) Keys() []string {
+	o := &to.v.o
+	o.unescapeKeys()
+	keys := make([]string, len(o.kvs))
+	for i, kv := range o.kvs {
+		keys[i] = kv.k
+	}
+	return keys
+}
+
+// Len returns the number of object members.
+func (to TemplateObject) Len() int {
+	return to.v.o.Len()
+}
+
+// Map returns object members converted by Value.TemplateData.
+//
+// Only the top-level members are converted, so Map is cheap for objects
+// with huge nested values. This allows field syntax such as .Map.name.
+// Note that templates range over maps in sorted key order.
+func (to TemplateObject) Map() map[string]interface{} {
+	o := &to.v.o
+	o.unescapeKeys()
+	m := make(map[string]interface{}, len(o.kvs))
+	for _, kv := range o.kvs {
+		if _, ok := m[kv.k]; !ok {
+			m[kv.k] = kv.v.TemplateData()
+		}
+	}
+	return m
+}
+
+// String returns JSON representation of the object.
+//
+// Templates print TemplateObject via String.
+func (to TemplateObject) String() string {
+	return to.v.String()
+}
+
+// TemplateArray is a JSON array wrapped for use in templates.
+//
+// Templates cannot pass TemplateArray to range, index and len builtins.
+// Use Items, Index and Len instead:
+//
+//	{{range $i, $item := .Items}}{{$i}}={{$item}}{{end}}
+//	{{.Index 0}} {{.Len}}
+//
+// TemplateArray is valid while the underlying Value is valid.
+// The underlying Value mustn't be modified while the template is executed.
+type TemplateArray struct {
+	v *Value
+}
+
+// Get returns the value at the given keys path converted by Value.TemplateData.
+//
+// Array indexes must be passed as decimal strings. nil is returned
+// for non-existing paths.
+func (ta TemplateArray) Get(keys ...string) interface{} {
+	return ta.v.Get(keys...).TemplateData()
+}
+
+// Index returns the array item at idx converted by Value.TemplateData.
+//
+// nil is returned if idx is out of range.
+func (ta TemplateArray) Index(idx int) interface{} {
+	if idx < 0 || idx >= len(ta.v.a) {
+		return nil
+	}
+	return ta.v.a[idx].TemplateData()
+}
+
+// Len returns the number of array items.
+func (ta TemplateArray) Len() int {
+	return len(ta.v.a)
+}
+
+// Items returns array items converted by Value.TemplateData.
+//
+// Only the top-level items are converted, so Items is cheap for arrays
+// with huge nested values. This allows ranging over the items.
+func (ta TemplateArray) Items() []interface{} {
+	a := make([]interface{}, len(ta.v.a))
+	for i, vv := range ta.v.a {
+		a[i] = vv.TemplateData()
+	}
+	return a
+}
+
+// String returns JSON representation of the array.
+//
+// Templates print TemplateArray via String.
+func (ta TemplateArray) String() string {
+	return ta.v.String()
+}
@@ -0,0 +1,79 @@
+package fastjson
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"testing"
+	"text/template"
+)
+
+func TestValueTemplateData(t *testing.T) {
+	v := MustParse(`{
+		"user": {"name": "foo<b>", "id": 12345678901234567890, "admin": false},
+		"items": [{"id": 1, "tags": ["a", "b"]}, {"id": 2.5, "tags": []}],
+		"matrix": [[1, 2], [3]],
+		"n": null,
+		"a\nb": "x"
+	}`)
+
+	f := func(tpl, resultExpected string) {
+		t.Helper()
+		tt := template.Must(template.New("").Parse(tpl))
+		var bb bytes.Buffer
+		if err := tt.Execute(&bb, v.TemplateData()); err != nil {
+			t.Fatalf("cannot execute template %q: %s", tpl, err)
+		}
+		if result := bb.String(); result != resultExpected {
+			t.Fatalf("unexpected result for template %q\ngot\n%s\nwant\n%s", tpl, result, resultExpected)
+		}
+	}
+
+	f(`{{.Get "user" "name"}}`, `foo<b>`)
+	f(`{{.Get "user" "id"}}`, `12345678901234567890`)
+	f(`{{if .Get "user" "admin"}}admin{{else}}user{{end}}`, `user`)
+	f(`{{.Get "items" "1" "id"}}`, `2.5`)
+	f(`{{range (.Get "items").Items}}[{{.Get "id"}}:{{range $i, $t := (.Get "tags").Items}}{{$i}}{{$t}}{{end}}]{{end}}`, `[1:0a1b][2.5:]`)
+	f(`{{((.Get "matrix").Index 0).Index 1}} {{(.Get "matrix").Len}} {{(.Get "matrix").Index 5}}`, `2 2 <no value>`)
+	f(`{{(.Get "matrix").Get "1" "0"}} {{.Get "matrix"}} {{(.Get "matrix").Index 1}}`, `3 [[1,2],[3]] [3]`)
+	f(`{{with .Get "missing"}}x{{else}}missing{{end}} {{with .Get "n"}}x{{else}}null{{end}}`, `missing null`)
+	f(`{{.Map.user.Get "name"}} {{index .Map "a\nb"}}`, `foo<b> x`)
+	f(`{{range $k := .Keys}}{{$k}};{{end}} {{.Len}}`, "user;items;matrix;n;a\nb; 5")
+	f(`{{.Get "user"}}`, `{"name":"foo<b>","id":12345678901234567890,"admin":false}`)
+	f(`{{(.Get "items" "0" "id").Int64}}`, `1`)
+	f(`{{if eq (.Get "user" "name") "foo<b>"}}eq{{end}}`, `eq`)
+
+	// html/template must escape strings.
+	ht := htmltemplate.Must(htmltemplate.New("").Parse(`<p>{{.Get "user" "name"}}</p>`))
+	var bb bytes.Buffer
+	if err := ht.Execute(&bb, v.TemplateData()); err != nil {
+		t.Fatalf("cannot execute html template: %s", err)
+	}
+	if s := bb.String(); s != `<p>foo&lt;b&gt;</p>` {
+		t.Fatalf("unexpected html template result: %s", s)
+	}
+
+	// Scalars
+	var nilValue *Value
+	if d := nilValue.TemplateData(); d != nil {
+		t.Fatalf("unexpected template data for nil value: %v", d)
+	}
+	if d := MustParse(`true`).TemplateData(); d != true {
+		t.Fatalf("unexpected template data for true: %v", d)
+	}
+	if d := MustParse(`"x"`).TemplateData(); d != "x" {
+		t.Fatalf("unexpected template data for string: %v", d)
+	}
+
+	// Arrays are converted lazily.
+	ta, ok := MustParse(`[[1],{"a":2}]`).TemplateData().(TemplateArray)
+	if !ok {
+		t.Fatalf("unexpected template data type for array")
+	}
+	items := ta.Items()
+	if _, ok := items[0].(TemplateArray); !ok {
+		t.Fatalf("unexpected template data type for nested array: %T", items[0])
+	}
+	if _, ok := items[1].(TemplateObject); !ok {
+		t.Fatalf("unexpected template data type for nested object: %T", items[1])
+	}
+}
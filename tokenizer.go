@@ -0,0 +1,316 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TokenType is a type of Token returned by Tokenizer.
+type TokenType int
+
+const (
+	// TokenBeginObject is '{'.
+	TokenBeginObject TokenType = iota
+
+	// TokenEndObject is '}'.
+	TokenEndObject
+
+	// TokenBeginArray is '['.
+	TokenBeginArray
+
+	// TokenEndArray is ']'.
+	TokenEndArray
+
+	// TokenKey is object key.
+	TokenKey
+
+	// TokenString is string value.
+	TokenString
+
+	// TokenNumber is number value.
+	TokenNumber
+
+	// TokenTrue is true value.
+	TokenTrue
+
+	// TokenFalse is false value.
+	TokenFalse
+
+	// TokenNull is null value.
+	TokenNull
+)
+
+// String returns string representation of tt.
+func (tt TokenType) String() string {
+	switch tt {
+	case TokenBeginObject:
+		return "beginObject"
+	case TokenEndObject:
+		return "endObject"
+	case TokenBeginArray:
+		return "beginArray"
+	case TokenEndArray:
+		return "endArray"
+	case TokenKey:
+		return "key"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenTrue:
+		return "true"
+	case TokenFalse:
+		return "false"
+	case TokenNull:
+		return "null"
+	default:
+		panic(fmt.Errorf("BUG: unknown token type: %d", tt))
+	}
+}
+
+// Token is a JSON token returned by Tokenizer.
+type Token struct {
+	// Type is the token type.
+	Type TokenType
+
+	// Raw contains raw token bytes from the input.
+	//
+	// Strings and keys are quoted and escaped, so use AppendString
+	// for obtaining their contents.
+	//
+	// Raw must not be modified. It is valid until the next Tokenizer.Init call.
+	Raw []byte
+}
+
+// AppendString appends unescaped contents of string or key token t to dst
+// and returns the result.
+//
+// dst is returned unchanged for other token types.
+func (t Token) AppendString(dst []byte) []byte {
+	if t.Type != TokenString && t.Type != TokenKey {
+		return dst
+	}
+	s := b2s(t.Raw[1 : len(t.Raw)-1])
+	if strings.IndexByte(s, '\\') < 0 {
+		return append(dst, s...)
+	}
+	n := len(dst)
+	dst = append(dst, s...)
+	us := unescapeStringBestEffort(b2s(dst[n:]))
+	return dst[:n+len(us)]
+}
+
+// Tokenizer splits JSON into tokens.
+//
+// Tokenizer returns tokens without allocating Values, so it may be used
+// for implementing custom decoders on top of it. The input may contain
+// multiple JSON values, which may be delimited by whitespace.
+//
+// Tokenizer may be re-used for subsequent tokenizing.
+//
+// Tokenizer cannot be used from concurrent goroutines.
+type Tokenizer struct {
+	// s points to the unprocessed input.
+	s string
+
+	// stack contains '{' and '[' chars for the currently open objects and arrays.
+	stack []byte
+
+	// state is the tokenizer state.
+	state int
+
+	// err contains the last error.
+	err error
+}
+
+const (
+	// tokenizerValue means the next token must be a value.
+	tokenizerValue = iota
+
+	// tokenizerFirstValue means the next token must be a value or ']'.
+	tokenizerFirstValue
+
+	// tokenizerKey means the next token must be a key.
+	tokenizerKey
+
+	// tokenizerFirstKey means the next token must be a key or '}'.
+	tokenizerFirstKey
+
+	// tokenizerAfterValue means the next token must follow a value.
+	tokenizerAfterValue
+)
+
+// Init initializes t with the given s.
+//
+// s may contain multiple JSON values, which may be delimited by whitespace.
+func (t *Tokenizer) Init(s string) {
+	t.s = s
+	t.stack = t.stack[:0]
+	t.state = tokenizerValue
+	t.err = nil
+}
+
+// InitBytes initializes t with the given b.
+//
+// b may contain multiple JSON values, which may be delimited by whitespace.
+func (t *Tokenizer) InitBytes(b []byte) {
+	t.Init(b2s(b))
+}
+
+// Depth returns the number of currently open objects and arrays.
+func (t *Tokenizer) Depth() int {
+	return len(t.stack)
+}
+
+// Next returns the next token.
+//
+// io.EOF is returned at the end of the input. Other errors mean
+// the input is malformed. The same error is returned on subsequent calls.
+func (t *Tokenizer) Next() (Token, error) {
+	if t.err != nil {
+		return Token{}, t.err
+	}
+	tok, err := t.next()
+	if err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("cannot tokenize JSON: %s; unparsed tail: %q", err, startEndString(t.s))
+		}
+		t.err = err
+	}
+	return tok, err
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	for {
+		t.s = skipWS(t.s)
+		if len(t.s) == 0 {
+			if len(t.stack) == 0 && (t.state == tokenizerValue || t.state == tokenizerAfterValue) {
+				return Token{}, io.EOF
+			}
+			return Token{}, fmt.Errorf("unexpected end of JSON")
+		}
+		switch t.state {
+		case tokenizerValue, tokenizerFirstValue:
+			if t.state == tokenizerFirstValue && t.s[0] == ']' {
+				return t.closeContainer(TokenEndArray), nil
+			}
+			return t.nextValue()
+		case tokenizerKey, tokenizerFirstKey:
+			if t.state == tokenizerFirstKey && t.s[0] == '}' {
+				return t.closeContainer(TokenEndObject), nil
+			}
+			return t.nextKey()
+		case tokenizerAfterValue:
+			if len(t.stack) == 0 {
+				t.state = tokenizerValue
+				continue
+			}
+			top := t.stack[len(t.stack)-1]
+			switch {
+			case t.s[0] == ',':
+				t.s = t.s[1:]
+				if top == '{' {
+					t.state = tokenizerKey
+				} else {
+					t.state = tokenizerValue
+				}
+				continue
+			case t.s[0] == '}' && top == '{':
+				return t.closeContainer(TokenEndObject), nil
+			case t.s[0] == ']' && top == '[':
+				return t.closeContainer(TokenEndArray), nil
+			case top == '{':
+				return Token{}, fmt.Errorf("missing ',' after object value")
+			default:
+				return Token{}, fmt.Errorf("missing ',' after array value")
+			}
+		default:
+			panic(fmt.Errorf("BUG: unexpected tokenizer state: %d", t.state))
+		}
+	}
+}
+
+func (t *Tokenizer) closeContainer(tt TokenType) Token {
+	tok := t.token(tt, 1)
+	t.stack = t.stack[:len(t.stack)-1]
+	t.state = tokenizerAfterValue
+	return tok
+}
+
+func (t *Tokenizer) nextKey() (Token, error) {
+	s := t.s
+	if s[0] != '"' {
+		return Token{}, fmt.Errorf(`cannot find opening '"" for object key`)
+	}
+	_, tail, err := parseRawKey(s[1:])
+	if err != nil {
+		return Token{}, fmt.Errorf("cannot parse object key: %s", err)
+	}
+	tok := t.token(TokenKey, len(s)-len(tail))
+	tail = skipWS(tail)
+	if len(tail) == 0 || tail[0] != ':' {
+		return Token{}, fmt.Errorf("missing ':' after object key")
+	}
+	t.s = tail[1:]
+	t.state = tokenizerValue
+	return tok, nil
+}
+
+func (t *Tokenizer) nextValue() (Token, error) {
+	s := t.s
+	switch s[0] {
+	case '{', '[':
+		if len(t.stack) >= MaxDepth {
+			return Token{}, fmt.Errorf("too big depth for the nested JSON; it exceeds %d", MaxDepth)
+		}
+		t.stack = append(t.stack, s[0])
+		if s[0] == '{' {
+			t.state = tokenizerFirstKey
+			return t.token(TokenBeginObject, 1), nil
+		}
+		t.state = tokenizerFirstValue
+		return t.token(TokenBeginArray, 1), nil
+	case '"':
+		_, tail, err := parseRawString(s[1:])
+		if err != nil {
+			return Token{}, fmt.Errorf("cannot parse string: %s", err)
+		}
+		return t.valueToken(TokenString, len(s)-len(tail)), nil
+	case 't':
+		if !strings.HasPrefix(s, "true") {
+			return Token{}, fmt.Errorf("unexpected value found: %q", startEndString(s))
+		}
+		return t.valueToken(TokenTrue, len("true")), nil
+	case 'f':
+		if !strings.HasPrefix(s, "false") {
+			return Token{}, fmt.Errorf("unexpected value found: %q", startEndString(s))
+		}
+		return t.valueToken(TokenFalse, len("false")), nil
+	case 'n':
+		if strings.HasPrefix(s, "null") {
+			return t.valueToken(TokenNull, len("null")), nil
+		}
+	}
+	ns, _, err := parseRawNumber(s)
+	if err != nil {
+		return Token{}, fmt.Errorf("cannot parse number: %s", err)
+	}
+	return t.valueToken(TokenNumber, len(ns)), nil
+}
+
+func (t *Tokenizer) valueToken(tt TokenType, n int) Token {
+	tok := t.token(tt, n)
+	t.state = tokenizerAfterValue
+	return tok
+}
+
+// token returns token of type tt with the first n bytes of t.s and skips them.
+func (t *Tokenizer) token(tt TokenType, n int) Token {
+	tok := Token{
+		Type: tt,
+		Raw:  s2b(t.s[:n]),
+	}
+	t.s = t.s[n:]
+	return tok
+}
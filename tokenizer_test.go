@@ -0,0 +1,106 @@
+package fastjson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		var tk Tokenizer
+		tk.Init(s)
+		var bb bytes.Buffer
+		for {
+			tok, err := tk.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unexpected error when tokenizing %q: %s", s, err)
+			}
+			fmt.Fprintf(&bb, "%s:%s ", tok.Type, tok.Raw)
+		}
+		if tk.Depth() != 0 {
+			t.Fatalf("unexpected depth at the end of %q: %d", s, tk.Depth())
+		}
+		if result := bb.String(); result != resultExpected {
+			t.Fatalf("unexpected tokens for %q\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	f(``, ``)
+	f(`  `, ``)
+	f(`1`, `number:1 `)
+	f(` -1.5e3 "foo" true false null NaN`, `number:-1.5e3 string:"foo" true:true false:false null:null number:NaN `)
+	f(`[]`, `beginArray:[ endArray:] `)
+	f(`{}`, `beginObject:{ endObject:} `)
+	f(`[ 1 , [ ] , { } ]`, `beginArray:[ number:1 beginArray:[ endArray:] beginObject:{ endObject:} endArray:] `)
+	f(`{"a":{"b\"c" : [1,"x"]},"d":null} [2]`,
+		`beginObject:{ key:"a" beginObject:{ key:"b\"c" beginArray:[ number:1 string:"x" endArray:] endObject:} key:"d" null:null endObject:} beginArray:[ number:2 endArray:] `)
+}
+
+func TestTokenizerError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		var tk Tokenizer
+		tk.InitBytes([]byte(s))
+		for {
+			_, err := tk.Next()
+			if err == io.EOF {
+				t.Fatalf("expecting non-EOF error when tokenizing %q", s)
+			}
+			if err != nil {
+				// The error must be sticky.
+				if _, err1 := tk.Next(); err1 != err {
+					t.Fatalf("unexpected error on the subsequent call; got %v; want %v", err1, err)
+				}
+				return
+			}
+		}
+	}
+
+	f(`[`)
+	f(`{`)
+	f(`]`)
+	f(`}`)
+	f(`[1,]`)
+	f(`[1 2]`)
+	f(`[1}`)
+	f(`{"a":1]`)
+	f(`{"a" 1}`)
+	f(`{"a":}`)
+	f(`{"a":1,}`)
+	f(`{a:1}`)
+	f(`{"a`)
+	f(`"foo`)
+	f(`tru`)
+	f(`nul`)
+	f(`[,]`)
+	f(strings.Repeat("[", MaxDepth+1))
+}
+
+func TestTokenAppendString(t *testing.T) {
+	var tk Tokenizer
+	tk.Init(`{"a\nb":"cAd","e":1}`)
+	var dst []byte
+	for {
+		tok, err := tk.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		dst = tok.AppendString(dst)
+		dst = append(dst, '|')
+	}
+	result := string(dst)
+	resultExpected := "|a\nb|cAd|e|||"
+	if result != resultExpected {
+		t.Fatalf("unexpected result; got %q; want %q", result, resultExpected)
+	}
+}
@@ -0,0 +1,76 @@
+package fastjson
+
+import (
+	"fmt"
+)
+
+// Tracer records the outcome of Get calls performed via it.
+//
+// Tracer helps debugging missing fields in complex documents: after
+// an unsuccessful Get call LastMissAt returns the deepest existing prefix
+// of the keys path and the key, which couldn't be found.
+//
+// Tracer cannot be used from concurrent goroutines.
+type Tracer struct {
+	prefix []string
+	key    string
+	missed bool
+}
+
+// Get returns value by the given keys path in v like Value.Get does,
+// and records the location of the failure if the path doesn't exist.
+//
+// Successful calls reset the recorded failure.
+func (t *Tracer) Get(v *Value, keys ...string) *Value {
+	t.Reset()
+	if v == nil {
+		t.missed = true
+		if len(keys) > 0 {
+			t.key = keys[0]
+		}
+		return nil
+	}
+	for i, key := range keys {
+		vv := v.Get(key)
+		if vv == nil {
+			t.prefix = append(t.prefix[:0], keys[:i]...)
+			t.key = key
+			t.missed = true
+			return nil
+		}
+		v = vv
+	}
+	return v
+}
+
+// LastMissAt returns the location of the failure for the last Get call.
+//
+// prefix contains the deepest existing part of the keys path, while key
+// contains the first key, which couldn't be found at prefix.
+// ok is false if the last Get call succeeded.
+//
+// The returned prefix is valid until the next Get call.
+func (t *Tracer) LastMissAt() (prefix []string, key string, ok bool) {
+	if !t.missed {
+		return nil, "", false
+	}
+	return t.prefix, t.key, true
+}
+
+// Reset resets the recorded failure.
+func (t *Tracer) Reset() {
+	t.prefix = t.prefix[:0]
+	t.key = ""
+	t.missed = false
+}
+
+// String returns human-readable description of the last failure.
+func (t *Tracer) String() string {
+	if !t.missed {
+		return "no misses"
+	}
+	if len(t.prefix) == 0 {
+		return fmt.Sprintf("missing key %q at the root", t.key)
+	}
+	return fmt.Sprintf("missing key %q at %q", t.key, JoinPath(t.prefix...))
+}
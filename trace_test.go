@@ -0,0 +1,53 @@
+package fastjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	v := MustParse(`{"a":{"b":[{"c":1}]},"x":null}`)
+	var tr Tracer
+
+	if _, _, ok := tr.LastMissAt(); ok {
+		t.Fatalf("unexpected miss for zero Tracer")
+	}
+
+	f := func(keys []string, prefixExpected []string, keyExpected, strExpected string) {
+		t.Helper()
+		if vv := tr.Get(v, keys...); vv != nil {
+			t.Fatalf("unexpected non-nil value for %q: %s", keys, vv)
+		}
+		prefix, key, ok := tr.LastMissAt()
+		if !ok {
+			t.Fatalf("expecting miss for %q", keys)
+		}
+		if len(prefix) == 0 {
+			prefix = nil
+		}
+		if !reflect.DeepEqual(prefix, prefixExpected) {
+			t.Fatalf("unexpected prefix for %q; got %q; want %q", keys, prefix, prefixExpected)
+		}
+		if key != keyExpected {
+			t.Fatalf("unexpected key for %q; got %q; want %q", keys, key, keyExpected)
+		}
+		if s := tr.String(); s != strExpected {
+			t.Fatalf("unexpected string for %q; got %q; want %q", keys, s, strExpected)
+		}
+	}
+	f([]string{"missing"}, nil, "missing", `missing key "missing" at the root`)
+	f([]string{"a", "b", "0", "d"}, []string{"a", "b", "0"}, "d", `missing key "d" at "a.b.0"`)
+	f([]string{"a", "b", "1", "c"}, []string{"a", "b"}, "1", `missing key "1" at "a.b"`)
+	f([]string{"x", "y"}, []string{"x"}, "y", `missing key "y" at "x"`)
+
+	vv := tr.Get(v, "a", "b", "0", "c")
+	if vv == nil || vv.GetInt() != 1 {
+		t.Fatalf("unexpected value: %s", vv)
+	}
+	if _, _, ok := tr.LastMissAt(); ok {
+		t.Fatalf("unexpected miss after successful Get")
+	}
+	if s := tr.String(); s != "no misses" {
+		t.Fatalf("unexpected string; got %q; want %q", s, "no misses")
+	}
+}
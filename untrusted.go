@@ -0,0 +1,145 @@
+package fastjson
+
+import (
+	"fmt"
+	"io"
+)
+
+// ValidateLimits contains limits for ValidateWithLimits.
+//
+// Zero limit means there is no limit.
+type ValidateLimits struct {
+	// MaxSize is the maximum size of the validated JSON in bytes.
+	MaxSize int
+
+	// MaxDepth is the maximum nesting depth of objects and arrays.
+	//
+	// MaxDepth cannot exceed the global MaxDepth limit.
+	MaxDepth int
+
+	// MaxWidth is the maximum number of items in a single array
+	// or members in a single object.
+	MaxWidth int
+
+	// MaxStringLen is the maximum length in bytes of a single string,
+	// object key or number. Strings and keys are measured before unescaping.
+	MaxStringLen int
+}
+
+// untrustedLimits are the limits used by ValidateUntrusted.
+var untrustedLimits = ValidateLimits{
+	MaxSize:      1024 * 1024,
+	MaxDepth:     32,
+	MaxWidth:     10000,
+	MaxStringLen: 64 * 1024,
+}
+
+// ValidateUntrusted validates JSON s received from untrusted source.
+//
+// It applies strict grammar checks like Validate and conservative limits:
+// 1MiB total size, nesting depth of 32, 10000 items per array or object
+// and 64KiB per string, key or number. Use ValidateWithLimits for custom
+// limits.
+//
+// Call ValidateUntrusted before parsing untrusted JSON with Parser,
+// which doesn't perform all the checks for the sake of speed.
+func ValidateUntrusted(s string) error {
+	return ValidateWithLimits(s, &untrustedLimits)
+}
+
+// ValidateUntrustedBytes validates JSON b received from untrusted source.
+//
+// See ValidateUntrusted for details.
+func ValidateUntrustedBytes(b []byte) error {
+	return ValidateUntrusted(b2s(b))
+}
+
+// ValidateWithLimits validates JSON s and verifies it doesn't exceed
+// the given limits.
+//
+// *SyntaxError with the position of the first failure is returned
+// for invalid JSON or JSON exceeding the limits.
+func ValidateWithLimits(s string, limits *ValidateLimits) error {
+	if limits.MaxSize > 0 && len(s) > limits.MaxSize {
+		return newSyntaxError(s, s[limits.MaxSize:], fmt.Sprintf("too big JSON size: %d bytes; it exceeds %d bytes", len(s), limits.MaxSize))
+	}
+
+	type container struct {
+		isArray bool
+		width   int
+	}
+	var stack []container
+
+	var t Tokenizer
+	t.Init(s)
+	for {
+		start := tokenStart(t.s, t.state)
+		tok, err := t.next()
+		if err != nil {
+			if err == io.EOF {
+				return newSyntaxError(s, t.s, "cannot parse JSON: cannot parse empty string")
+			}
+			return newSyntaxError(s, t.s, fmt.Sprintf("cannot parse JSON: %s", err))
+		}
+		if err := validateToken(tok); err != nil {
+			return newSyntaxError(s, start, fmt.Sprintf("cannot parse JSON: %s", err))
+		}
+
+		var msg string
+		switch tok.Type {
+		case TokenString, TokenKey, TokenNumber:
+			n := len(tok.Raw)
+			if tok.Type != TokenNumber {
+				// Do not count quotes.
+				n -= 2
+			}
+			if limits.MaxStringLen > 0 && n > limits.MaxStringLen {
+				msg = fmt.Sprintf("too long %s: %d bytes; it exceeds %d bytes", tok.Type, n, limits.MaxStringLen)
+			}
+		}
+		switch tok.Type {
+		case TokenEndObject, TokenEndArray:
+			stack = stack[:len(stack)-1]
+		case TokenKey:
+			stack[len(stack)-1].width++
+		default:
+			if len(stack) > 0 && stack[len(stack)-1].isArray {
+				stack[len(stack)-1].width++
+			}
+		}
+		if n := len(stack); n > 0 && limits.MaxWidth > 0 && stack[n-1].width > limits.MaxWidth {
+			msg = fmt.Sprintf("too many items in a single object or array; it exceeds %d", limits.MaxWidth)
+		}
+		switch tok.Type {
+		case TokenBeginObject, TokenBeginArray:
+			stack = append(stack, container{
+				isArray: tok.Type == TokenBeginArray,
+			})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				msg = fmt.Sprintf("too big depth for the nested JSON; it exceeds %d", limits.MaxDepth)
+			}
+		}
+		if msg != "" {
+			return newSyntaxError(s, start, msg)
+		}
+		if t.Depth() == 0 {
+			break
+		}
+	}
+
+	tail := skipWS(t.s)
+	if len(tail) > 0 {
+		return newSyntaxError(s, tail, "unexpected tail")
+	}
+	return nil
+}
+
+// tokenStart returns the tail of s starting at the next token
+// for the given tokenizer state.
+func tokenStart(s string, state int) string {
+	s = skipWS(s)
+	if state == tokenizerAfterValue && len(s) > 0 && s[0] == ',' {
+		s = skipWS(s[1:])
+	}
+	return s
+}
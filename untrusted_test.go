@@ -0,0 +1,83 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWithLimits(t *testing.T) {
+	limits := &ValidateLimits{
+		MaxSize:      100,
+		MaxDepth:     3,
+		MaxWidth:     3,
+		MaxStringLen: 10,
+	}
+	f := func(s string, offsetExpected int, msgExpected string) {
+		t.Helper()
+		err := ValidateWithLimits(s, limits)
+		if msgExpected == "" {
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", s, err)
+			}
+			return
+		}
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Fatalf("expecting *SyntaxError for %q; got %T: %v", s, err, err)
+		}
+		if se.Offset != offsetExpected || !strings.Contains(se.Msg, msgExpected) {
+			t.Fatalf("unexpected error for %q; got offset=%d, msg=%q; want offset=%d, msg containing %q", s, se.Offset, se.Msg, offsetExpected, msgExpected)
+		}
+	}
+
+	f(`{"a":[1,2,{"b":null}],"c":"0123456789"}`, 0, "")
+	f(` [ [ [ ] ] ] `, 0, "")
+	f(`12345678`, 0, "")
+
+	// Limits
+	f(strings.Repeat(" ", 100)+"1", 100, "too big JSON size")
+	f(`[[[[]]]]`, 3, "too big depth")
+	f(`{"a":{"b":{"c":{}}}}`, 15, "too big depth")
+	f(`[1,2,3, 4]`, 8, "too many items")
+	f(`{"a":1,"b":2,"c":3, "d":4}`, 20, "too many items")
+	f(`[[1,2,3],[4,5,6],[7,8,9]]`, 0, "")
+	f(`[[],[],[],[]]`, 10, "too many items")
+	f(`["01234567890"]`, 1, "too long string")
+	f(`{"01234567890":1}`, 1, "too long key")
+	f(`[1, 0.1234567890]`, 4, "too long number")
+
+	// Grammar
+	f(``, 0, "cannot parse empty string")
+	f(`[1,]`, 3, "cannot parse number")
+	f(`["\x"]`, 1, "unknown escape sequence")
+	f(`[01]`, 1, "unexpected number starting from 0")
+	f(`{"a" 1}`, 4, "missing ':'")
+	f(`1 2`, 2, "unexpected tail")
+
+	// Results must match Validate for inputs within limits.
+	for _, s := range []string{`{"a":[1,2]}`, `[1,2`, `{"a":"\u12"}`, `tru`, `-`, `"a` + "\x01" + `"`, "{\n\"a\":\n[1e5, true, -0.5], \"b\": null}"} {
+		errExpected := Validate(s)
+		err := ValidateWithLimits(s, limits)
+		if (err == nil) != (errExpected == nil) {
+			t.Fatalf("unexpected error for %q; got %v; want %v", s, err, errExpected)
+		}
+	}
+}
+
+func TestValidateUntrusted(t *testing.T) {
+	if err := ValidateUntrustedBytes([]byte(`{"id":1,"tags":["a","b"]}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := ValidateUntrusted(strings.Repeat("[", 33) + strings.Repeat("]", 33)); err == nil {
+		t.Fatalf("expecting non-nil error for too deep JSON")
+	}
+	if err := ValidateUntrusted(`"` + strings.Repeat("x", 64*1024+1) + `"`); err == nil {
+		t.Fatalf("expecting non-nil error for too long string")
+	}
+	if err := ValidateUntrusted(`[` + strings.Repeat("1,", 10000) + `1]`); err == nil {
+		t.Fatalf("expecting non-nil error for too wide array")
+	}
+	if err := ValidateUntrusted(`"` + strings.Repeat("x", 1024*1024) + `"`); err == nil {
+		t.Fatalf("expecting non-nil error for too big JSON")
+	}
+}
@@ -1,6 +1,7 @@
 package fastjson
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -11,6 +12,7 @@ func (o *Object) Del(key string) {
 	if o == nil {
 		return
 	}
+	o.idx = nil
 
 	// 快速路径：键未转义且要删除的键不包含反斜杠，直接在 o.kvs 里查找目标字符串，找到就 append(o.kvs[:i], o.kvs[i+1:]...) 删除。
 	if !o.keysUnescaped && strings.IndexByte(key, '\\') < 0 {
@@ -73,6 +75,19 @@ func (o *Object) Set(key string, value *Value) {
 	// 确保键已转义，因为后续要做键的查找（匹配）
 	o.unescapeKeys()
 
+	if o.idx != nil {
+		// Fast path - use the index built by BuildIndex.
+		if i, ok := o.idx[key]; ok {
+			o.kvs[i].v = value
+			return
+		}
+		kv := o.getKV()
+		kv.k = key
+		kv.v = value
+		o.idx[key] = len(o.kvs) - 1
+		return
+	}
+
 	// Try substituting already existing entry with the given key.
 	// 先尝试更新已存在的键
 	for i := range o.kvs {
@@ -124,3 +139,336 @@ func (v *Value) SetArrayItem(idx int, value *Value) {
 	// 设置指定位置的值
 	v.a[idx] = value
 }
+
+// Append appends items to the end of the array v.
+//
+// nil items are appended as null. The items must be unchanged during v lifetime.
+func (v *Value) Append(items ...*Value) {
+	if v == nil || v.t != TypeArray {
+		return
+	}
+	for _, item := range items {
+		if item == nil {
+			item = valueNull
+		}
+		v.a = append(v.a, item)
+	}
+}
+
+// InsertAt inserts item into the array v at idx position.
+//
+// Items starting from idx are shifted to the right. The array is extended
+// with nulls if idx exceeds its length.
+//
+// The item must be unchanged during v lifetime.
+func (v *Value) InsertAt(idx int, item *Value) {
+	if v == nil || v.t != TypeArray || idx < 0 {
+		return
+	}
+	if item == nil {
+		item = valueNull
+	}
+	for idx > len(v.a) {
+		v.a = append(v.a, valueNull)
+	}
+	v.a = append(v.a, nil)
+	copy(v.a[idx+1:], v.a[idx:])
+	v.a[idx] = item
+}
+
+// DelRange deletes items in the range [from, to) from the array v.
+//
+// The range is clipped to the array bounds.
+func (v *Value) DelRange(from, to int) {
+	if v == nil || v.t != TypeArray {
+		return
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to > len(v.a) {
+		to = len(v.a)
+	}
+	if from >= to {
+		return
+	}
+	n := copy(v.a[from:], v.a[to:])
+	tail := v.a[from+n:]
+	for i := range tail {
+		// Release references to the deleted items.
+		tail[i] = nil
+	}
+	v.a = v.a[:from+n]
+}
+
+// FilterArray leaves only items for which keep returns true in the array v.
+//
+// The array is filtered in place without reallocating items slice.
+func (v *Value) FilterArray(keep func(item *Value) bool) {
+	if v == nil || v.t != TypeArray {
+		return
+	}
+	a := v.a[:0]
+	for _, item := range v.a {
+		if keep(item) {
+			a = append(a, item)
+		}
+	}
+	tail := v.a[len(a):]
+	for i := range tail {
+		// Release references to the deleted items.
+		tail[i] = nil
+	}
+	v.a = a
+}
+
+// MapArray replaces every item in the array v with the value returned by f.
+//
+// nil values returned by f are stored as null. The returned values
+// must be unchanged during v lifetime.
+func (v *Value) MapArray(f func(item *Value) *Value) {
+	if v == nil || v.t != TypeArray {
+		return
+	}
+	for i, item := range v.a {
+		item = f(item)
+		if item == nil {
+			item = valueNull
+		}
+		v.a[i] = item
+	}
+}
+
+// SetRaw sets (key, rawJSON) entry in the o.
+//
+// rawJSON must contain already serialized JSON. It is parsed at once,
+// so its contents are accessible and modifiable in the same way as
+// the contents of other values in o. Consequently MarshalTo doesn't emit
+// rawJSON verbatim: it emits the parsed contents in compact form without
+// the insignificant whitespace. An error is returned and o isn't modified
+// if rawJSON is invalid.
+//
+// rawJSON is copied, so it may be modified after returning.
+func (o *Object) SetRaw(key string, rawJSON []byte) error {
+	if o == nil {
+		return nil
+	}
+	v, err := parseRawJSON(rawJSON)
+	if err != nil {
+		return err
+	}
+	o.Set(key, v)
+	return nil
+}
+
+// SetRaw sets (key, rawJSON) entry in the array or object v.
+//
+// See Object.SetRaw for details.
+func (v *Value) SetRaw(key string, rawJSON []byte) error {
+	if v == nil {
+		return nil
+	}
+	vv, err := parseRawJSON(rawJSON)
+	if err != nil {
+		return err
+	}
+	v.Set(key, vv)
+	return nil
+}
+
+// SetRawArrayItem sets rawJSON in the array v at idx position.
+//
+// See Object.SetRaw for details.
+func (v *Value) SetRawArrayItem(idx int, rawJSON []byte) error {
+	if v == nil || v.t != TypeArray {
+		return nil
+	}
+	vv, err := parseRawJSON(rawJSON)
+	if err != nil {
+		return err
+	}
+	v.SetArrayItem(idx, vv)
+	return nil
+}
+
+// parseRawJSON parses rawJSON with a pooled Parser and returns a copy
+// of the parsed value, which doesn't refer to the Parser memory.
+func parseRawJSON(rawJSON []byte) (*Value, error) {
+	p := handyPool.Get()
+	defer handyPool.Put(p)
+	v, err := p.ParseBytes(rawJSON)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rawJSON: %s", err)
+	}
+	return v.CloneForGoroutine(), nil
+}
+
+// SetPath sets value at the path in v identified by the given keys.
+//
+// Missing intermediate objects are created along the path and arrays
+// are extended with nulls, mirroring the way Get walks the path.
+// Intermediate values other than objects and arrays are replaced
+// with new objects. Array indexes must be passed in keys as decimal numbers.
+// Nothing is set if v isn't an object or array, or if a non-numeric key
+// refers to an array item.
+//
+// The value must be unchanged during v lifetime.
+func (v *Value) SetPath(value *Value, keys ...string) {
+	if v == nil || len(keys) == 0 {
+		return
+	}
+	for _, key := range keys[:len(keys)-1] {
+		t := v.Type()
+		if t != TypeObject && t != TypeArray {
+			return
+		}
+		if t == TypeArray {
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 0 {
+				return
+			}
+		}
+		child := v.Get(key)
+		if child == nil || (child.Type() != TypeObject && child.Type() != TypeArray) {
+			child = &Value{
+				t: TypeObject,
+			}
+			v.Set(key, child)
+		}
+		v = child
+	}
+	v.Set(keys[len(keys)-1], value)
+}
+
+// DelPath deletes the entry at the path in v identified by the given keys.
+//
+// The path uses the same syntax as Get: array indexes must be passed
+// in keys as decimal numbers. Nothing is deleted if the path doesn't exist.
+func (v *Value) DelPath(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	v.Get(keys[:len(keys)-1]...).Del(keys[len(keys)-1])
+}
+
+// Rename renames oldKey to newKey in o, preserving the entry position.
+//
+// An already existing entry with newKey is deleted. Returns false
+// if o doesn't contain oldKey.
+func (o *Object) Rename(oldKey, newKey string) bool {
+	if o == nil {
+		return false
+	}
+	o.unescapeKeys()
+	n := -1
+	for i, kv := range o.kvs {
+		if kv.k == oldKey {
+			n = i
+			break
+		}
+	}
+	if n < 0 {
+		return false
+	}
+	if oldKey == newKey {
+		return true
+	}
+	o.idx = nil
+	o.kvs[n].k = newKey
+	kvs := o.kvs[:0]
+	for i, kv := range o.kvs {
+		if i != n && kv.k == newKey {
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	tail := o.kvs[len(kvs):]
+	for i := range tail {
+		// Release references to the deleted items.
+		tail[i] = kv{}
+	}
+	o.kvs = kvs
+	return true
+}
+
+// MoveKey moves the entry with the given key to pos position in o.
+//
+// Entries between the old and the new positions are shifted.
+// pos is clipped to the object bounds. Nothing is moved if o
+// doesn't contain the key.
+func (o *Object) MoveKey(key string, pos int) {
+	if o == nil {
+		return
+	}
+	o.unescapeKeys()
+	n := -1
+	for i, kv := range o.kvs {
+		if kv.k == key {
+			n = i
+			break
+		}
+	}
+	if n < 0 {
+		return
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(o.kvs) {
+		pos = len(o.kvs) - 1
+	}
+	if pos == n {
+		return
+	}
+	o.idx = nil
+	kv := o.kvs[n]
+	if pos < n {
+		copy(o.kvs[pos+1:n+1], o.kvs[pos:n])
+	} else {
+		copy(o.kvs[n:pos], o.kvs[n+1:pos+1])
+	}
+	o.kvs[pos] = kv
+}
+
+// Pick leaves only entries with the given keys in o.
+//
+// The order of the remaining entries is preserved.
+func (o *Object) Pick(keys ...string) {
+	o.filterKeys(keys, true)
+}
+
+// Omit deletes entries with the given keys from o.
+//
+// The order of the remaining entries is preserved.
+func (o *Object) Omit(keys ...string) {
+	o.filterKeys(keys, false)
+}
+
+func (o *Object) filterKeys(keys []string, keep bool) {
+	if o == nil {
+		return
+	}
+	o.unescapeKeys()
+	o.idx = nil
+	kvs := o.kvs[:0]
+	for _, kv := range o.kvs {
+		if containsString(keys, kv.k) == keep {
+			kvs = append(kvs, kv)
+		}
+	}
+	tail := o.kvs[len(kvs):]
+	for i := range tail {
+		// Release references to the deleted items.
+		tail[i] = kv{}
+	}
+	o.kvs = kvs
+}
+
+func containsString(a []string, s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package fastjson
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -101,3 +104,379 @@ func TestValueDelSet(t *testing.T) {
 	v.Set("x", MustParse(`[]`))
 	v.SetArrayItem(1, MustParse(`[]`))
 }
+
+func TestValueArrayMutation(t *testing.T) {
+	var a Arena
+	v := MustParse(`[1,2,3]`)
+
+	v.Append(a.NewNumberInt(4), nil, a.NewString("x"))
+	f := func(sExpected string) {
+		t.Helper()
+		s := v.String()
+		if s != sExpected {
+			t.Fatalf("unexpected array; got %s; want %s", s, sExpected)
+		}
+	}
+	f(`[1,2,3,4,null,"x"]`)
+
+	v.InsertAt(0, a.NewString("first"))
+	f(`["first",1,2,3,4,null,"x"]`)
+	v.InsertAt(3, a.NewTrue())
+	f(`["first",1,2,true,3,4,null,"x"]`)
+	v.InsertAt(8, a.NewFalse())
+	f(`["first",1,2,true,3,4,null,"x",false]`)
+	v.InsertAt(11, a.NewNumberInt(5))
+	f(`["first",1,2,true,3,4,null,"x",false,null,null,5]`)
+	v.InsertAt(-1, a.NewNumberInt(5))
+	f(`["first",1,2,true,3,4,null,"x",false,null,null,5]`)
+
+	v.DelRange(8, 11)
+	f(`["first",1,2,true,3,4,null,"x",5]`)
+	v.DelRange(-5, 1)
+	f(`[1,2,true,3,4,null,"x",5]`)
+	v.DelRange(6, 100)
+	f(`[1,2,true,3,4,null]`)
+	v.DelRange(3, 3)
+	f(`[1,2,true,3,4,null]`)
+	v.DelRange(4, 2)
+	f(`[1,2,true,3,4,null]`)
+	v.DelRange(0, 6)
+	f(`[]`)
+
+	// Non-array values must remain unchanged.
+	o := MustParse(`{"a":1}`)
+	o.Append(a.NewNull())
+	o.InsertAt(0, a.NewNull())
+	o.DelRange(0, 1)
+	if s := o.String(); s != `{"a":1}` {
+		t.Fatalf("unexpected object; got %s; want %s", s, `{"a":1}`)
+	}
+
+	var vNil *Value
+	vNil.Append(a.NewNull())
+	vNil.InsertAt(0, a.NewNull())
+	vNil.DelRange(0, 1)
+}
+
+func TestValueFilterMapArray(t *testing.T) {
+	var a Arena
+	v := MustParse(`[1,2,3,4,5,6]`)
+	items := v.GetArray()
+
+	v.FilterArray(func(item *Value) bool {
+		return item.GetInt()%2 == 0
+	})
+	if s := v.String(); s != `[2,4,6]` {
+		t.Fatalf("unexpected filtered array; got %s; want %s", s, `[2,4,6]`)
+	}
+	if &items[0] != &v.GetArray()[0] {
+		t.Fatalf("FilterArray mustn't reallocate items")
+	}
+
+	v.MapArray(func(item *Value) *Value {
+		n := item.GetInt()
+		if n == 4 {
+			return nil
+		}
+		return a.NewNumberInt(n * 10)
+	})
+	if s := v.String(); s != `[20,null,60]` {
+		t.Fatalf("unexpected mapped array; got %s; want %s", s, `[20,null,60]`)
+	}
+
+	v.FilterArray(func(item *Value) bool { return false })
+	if s := v.String(); s != `[]` {
+		t.Fatalf("unexpected filtered array; got %s; want %s", s, `[]`)
+	}
+
+	// Non-arrays must remain unchanged.
+	o := MustParse(`{"a":1}`)
+	o.FilterArray(func(item *Value) bool { return false })
+	o.MapArray(func(item *Value) *Value { return nil })
+	if s := o.String(); s != `{"a":1}` {
+		t.Fatalf("unexpected object; got %s", s)
+	}
+	var vNil *Value
+	vNil.FilterArray(func(item *Value) bool { return false })
+	vNil.MapArray(func(item *Value) *Value { return nil })
+}
+
+func TestSetRaw(t *testing.T) {
+	v := MustParse(`{"a":1,"arr":[]}`)
+	raw := []byte(`{"cached" : [1, 2], "password": "x\ty"}`)
+	if err := v.GetObject().SetRaw("fragment", raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := v.Get("arr").SetRawArrayItem(1, []byte(`"x"`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	raw[1] = 'X'
+
+	// Invalid rawJSON must be rejected.
+	if err := v.SetRaw("invalid", []byte(`{bad`)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if err := v.GetObject().SetRaw("invalid", []byte(`1 2`)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if err := v.Get("arr").SetRawArrayItem(0, []byte(``)); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+
+	s := v.String()
+	sExpected := `{"a":1,"arr":[null,"x"],"fragment":{"cached":[1,2],"password":"x\ty"}}`
+	if s != sExpected {
+		t.Fatalf("unexpected json\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+	mo := &MarshalOptions{}
+	if s := string(mo.MarshalTo(nil, v)); s != sExpected {
+		t.Fatalf("unexpected json from MarshalOptions\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+
+	// Raw fragments mustn't refer to the memory of the pooled parsers.
+	for i := 0; i < 10; i++ {
+		if _, err := Equivalent([]byte(`{"cached":[3,4],"password":"zzzz"}`), []byte(`[5,6,7,8,9,10,11,12]`)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if s := v.String(); s != sExpected {
+		t.Fatalf("unexpected json after reusing pooled parsers\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+
+	// The contents of raw fragments must be visible to all the traversals.
+	if n := v.GetInt("fragment", "cached", "1"); n != 2 {
+		t.Fatalf("unexpected value inside raw fragment; got %d; want %d", n, 2)
+	}
+	if sb := v.GetStringBytes("arr", "1"); string(sb) != "x" {
+		t.Fatalf("unexpected string in raw array item; got %q; want %q", sb, "x")
+	}
+	if vs := v.FindAll("password"); len(vs) != 1 {
+		t.Fatalf("unexpected number of values found in raw fragment; got %d; want 1", len(vs))
+	}
+	var paths []string
+	v.Walk(func(path []string, v *Value) bool {
+		paths = append(paths, strings.Join(path, "."))
+		return true
+	})
+	pathsExpected := ",a,arr,arr.0,arr.1,fragment,fragment.cached,fragment.cached.0,fragment.cached.1,fragment.password"
+	if s := strings.Join(paths, ","); s != pathsExpected {
+		t.Fatalf("unexpected walked paths\ngot\n%s\nwant\n%s", s, pathsExpected)
+	}
+	v.Get("fragment").Set("password", MustParse(`"***"`))
+	v.Get("fragment", "cached").SetArrayItem(0, MustParse(`3`))
+	sExpected = `{"a":1,"arr":[null,"x"],"fragment":{"cached":[3,2],"password":"***"}}`
+	if s := v.String(); s != sExpected {
+		t.Fatalf("unexpected json after modifying raw fragment\ngot\n%s\nwant\n%s", s, sExpected)
+	}
+
+	// Clones of raw fragments must be safe for concurrent reading.
+	v2 := MustParse(`{}`)
+	if err := v2.SetRaw("o", []byte(`{"k":"v\n","a":[1,{"b":"c"}]}`)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	vc := v2.CloneForGoroutine()
+	sExpected = `{"o":{"k":"v\n","a":[1,{"b":"c"}]}}`
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s := vc.Get("o", "a", "1").Type(); s != TypeObject {
+				errs <- fmt.Errorf("unexpected type; got %s; want %s", s, TypeObject)
+				return
+			}
+			if s := string(vc.GetStringBytes("o", "k")); s != "v\n" {
+				errs <- fmt.Errorf("unexpected string; got %q; want %q", s, "v\n")
+				return
+			}
+			if s := vc.String(); s != sExpected {
+				errs <- fmt.Errorf("unexpected json for clone\ngot\n%s\nwant\n%s", s, sExpected)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	var oNil *Object
+	if err := oNil.SetRaw("a", raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var vNil *Value
+	if err := vNil.SetRaw("a", raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := vNil.SetRawArrayItem(0, raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestValueSetPath(t *testing.T) {
+	f := func(s string, value *Value, keys []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.SetPath(value, keys...)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for SetPath(%q) on %s\ngot\n%s\nwant\n%s", keys, s, result, resultExpected)
+		}
+	}
+
+	one := MustParse(`1`)
+	f(`{}`, one, []string{"a"}, `{"a":1}`)
+	f(`{}`, one, []string{"a", "b", "c"}, `{"a":{"b":{"c":1}}}`)
+	f(`{"a":{"x":2}}`, one, []string{"a", "b"}, `{"a":{"x":2,"b":1}}`)
+	f(`{"a":"foo"}`, one, []string{"a", "b"}, `{"a":{"b":1}}`)
+	f(`{"a":null}`, one, []string{"a", "b"}, `{"a":{"b":1}}`)
+	f(`{"a":[]}`, one, []string{"a", "2"}, `{"a":[null,null,1]}`)
+	f(`{"a":[]}`, one, []string{"a", "1", "b"}, `{"a":[null,{"b":1}]}`)
+	f(`{"a":[{"b":2}]}`, one, []string{"a", "0", "c"}, `{"a":[{"b":2,"c":1}]}`)
+	f(`[]`, one, []string{"0", "a"}, `[{"a":1}]`)
+	f(`{}`, nil, []string{"a", "b"}, `{"a":{"b":null}}`)
+
+	// Invalid paths are ignored.
+	f(`{"a":[]}`, one, []string{"a", "x", "b"}, `{"a":[]}`)
+	f(`{"a":[]}`, one, []string{"a", "-1", "b"}, `{"a":[]}`)
+	f(`"foo"`, one, []string{"a", "b"}, `"foo"`)
+	f(`{}`, one, nil, `{}`)
+
+	var vNil *Value
+	vNil.SetPath(one, "a")
+}
+
+func TestValueDelPath(t *testing.T) {
+	f := func(s string, keys []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		v.DelPath(keys...)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for DelPath(%q) on %s\ngot\n%s\nwant\n%s", keys, s, result, resultExpected)
+		}
+	}
+
+	f(`{"a":1,"b":2}`, []string{"a"}, `{"b":2}`)
+	f(`{"a":{"b":{"c":1,"d":2}}}`, []string{"a", "b", "c"}, `{"a":{"b":{"d":2}}}`)
+	f(`{"a":[1,2,3]}`, []string{"a", "1"}, `{"a":[1,3]}`)
+	f(`{"a":[{"b":1,"c":2}]}`, []string{"a", "0", "b"}, `{"a":[{"c":2}]}`)
+	f(`[[1,2]]`, []string{"0", "0"}, `[[2]]`)
+
+	// Missing paths are ignored.
+	f(`{"a":1}`, []string{"x", "y"}, `{"a":1}`)
+	f(`{"a":1}`, []string{"a", "b"}, `{"a":1}`)
+	f(`{"a":[1]}`, []string{"a", "5"}, `{"a":[1]}`)
+	f(`{"a":1}`, nil, `{"a":1}`)
+
+	var vNil *Value
+	vNil.DelPath("a", "b")
+}
+
+func TestObjectRename(t *testing.T) {
+	f := func(s, oldKey, newKey string, okExpected bool, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		o := v.GetObject()
+		o.BuildIndex()
+		ok := o.Rename(oldKey, newKey)
+		if ok != okExpected {
+			t.Fatalf("unexpected result for Rename(%q, %q) on %s; got %v; want %v", oldKey, newKey, s, ok, okExpected)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected object after Rename(%q, %q) on %s\ngot\n%s\nwant\n%s", oldKey, newKey, s, result, resultExpected)
+		}
+		if v.Get(newKey) == nil && okExpected {
+			t.Fatalf("cannot find renamed key %q", newKey)
+		}
+	}
+
+	f(`{"a":1,"b":2,"c":3}`, "b", "x", true, `{"a":1,"x":2,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "b", "b", true, `{"a":1,"b":2,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "c", "a", true, `{"b":2,"a":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "a", "c", true, `{"c":1,"b":2}`)
+	f(`{"a\nb":1}`, "a\nb", "x", true, `{"x":1}`)
+	f(`{"a":1}`, "missing", "x", false, `{"a":1}`)
+
+	// The entry removed by Rename mustn't be referenced by o.
+	o := MustParse(`{"a":1,"b":2,"c":3}`).GetObject()
+	o.Rename("c", "a")
+	if tail := o.kvs[len(o.kvs):cap(o.kvs)]; len(tail) == 0 || tail[0] != (kv{}) {
+		t.Fatalf("the removed entry must be cleared; got %+v", tail)
+	}
+
+	var oNil *Object
+	if oNil.Rename("a", "b") {
+		t.Fatalf("expecting false for nil object")
+	}
+}
+
+func TestObjectMoveKey(t *testing.T) {
+	f := func(s, key string, pos int, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		o := v.GetObject()
+		o.BuildIndex()
+		o.MoveKey(key, pos)
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected object after MoveKey(%q, %d) on %s\ngot\n%s\nwant\n%s", key, pos, s, result, resultExpected)
+		}
+		if o.HasIndex() && result != s {
+			t.Fatalf("expecting dropped index after MoveKey")
+		}
+		o.Visit(func(k []byte, v *Value) {
+			if o.Get(string(k)) != v {
+				t.Fatalf("unexpected value for key %q after MoveKey", k)
+			}
+		})
+	}
+
+	f(`{"a":1,"b":2,"c":3}`, "c", 0, `{"c":3,"a":1,"b":2}`)
+	f(`{"a":1,"b":2,"c":3}`, "a", 2, `{"b":2,"c":3,"a":1}`)
+	f(`{"a":1,"b":2,"c":3}`, "a", 1, `{"b":2,"a":1,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "b", 1, `{"a":1,"b":2,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "b", -5, `{"b":2,"a":1,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, "b", 100, `{"a":1,"c":3,"b":2}`)
+	f(`{"a":1,"b":2,"c":3}`, "missing", 0, `{"a":1,"b":2,"c":3}`)
+
+	var oNil *Object
+	oNil.MoveKey("a", 0)
+}
+
+func TestObjectPickOmit(t *testing.T) {
+	f := func(s string, pick bool, keys []string, resultExpected string) {
+		t.Helper()
+		v := MustParse(s)
+		o := v.GetObject()
+		o.BuildIndex()
+		if pick {
+			o.Pick(keys...)
+		} else {
+			o.Omit(keys...)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for pick=%v, keys=%q on %s\ngot\n%s\nwant\n%s", pick, keys, s, result, resultExpected)
+		}
+		o.Visit(func(k []byte, v *Value) {
+			if o.Get(string(k)) != v {
+				t.Fatalf("unexpected value for key %q", k)
+			}
+		})
+	}
+
+	f(`{"a":1,"b":2,"c":3}`, true, []string{"c", "a"}, `{"a":1,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, true, []string{"x"}, `{}`)
+	f(`{"a":1,"b":2,"c":3}`, true, nil, `{}`)
+	f(`{"a\nb":1,"b":2}`, true, []string{"a\nb"}, `{"a\nb":1}`)
+	f(`{"a":1,"b":2,"c":3}`, false, []string{"c", "a"}, `{"b":2}`)
+	f(`{"a":1,"b":2,"c":3}`, false, []string{"x"}, `{"a":1,"b":2,"c":3}`)
+	f(`{"a":1,"b":2,"c":3}`, false, nil, `{"a":1,"b":2,"c":3}`)
+
+	var oNil *Object
+	oNil.Pick("a")
+	oNil.Omit("a")
+}
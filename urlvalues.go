@@ -0,0 +1,394 @@
+package fastjson
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToURLValues converts JSON object v to url.Values.
+//
+// Strings, numbers and booleans are converted to their textual representation.
+// Arrays of such values are converted to repeated keys. Null values are skipped.
+//
+// Nested objects and arrays are converted to keys in bracket notation,
+// e.g. {"a":{"b":[{"c":1}]}} is converted to a[b][0][c]=1. Empty objects
+// and arrays are skipped. See Arena.FromURLValues for the reverse conversion.
+//
+// An error is returned if v isn't an object.
+func (v *Value) ToURLValues() (url.Values, error) {
+	o, err := v.Object()
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert value to url.Values: %s", err)
+	}
+	q := make(url.Values)
+	o.unescapeKeys()
+	for _, kv := range o.kvs {
+		addURLValues(q, kv.k, kv.v)
+	}
+	return q, nil
+}
+
+func addURLValues(q url.Values, key string, v *Value) {
+	switch v.Type() {
+	case TypeObject:
+		o := &v.o
+		o.unescapeKeys()
+		for _, kv := range o.kvs {
+			addURLValues(q, key+"["+kv.k+"]", kv.v)
+		}
+	case TypeArray:
+		if isScalarArray(v.a) {
+			for _, item := range v.a {
+				addURLValues(q, key, item)
+			}
+			return
+		}
+		for i, item := range v.a {
+			addURLValues(q, key+"["+strconv.Itoa(i)+"]", item)
+		}
+	case TypeNull:
+	default:
+		b, _ := appendScalarString(nil, v)
+		q.Add(key, string(b))
+	}
+}
+
+func isScalarArray(a []*Value) bool {
+	for _, v := range a {
+		if t := v.Type(); t == TypeObject || t == TypeArray {
+			return false
+		}
+	}
+	return true
+}
+
+// ToHeader converts flat JSON object v to http.Header.
+//
+// Object keys are converted to canonical header keys.
+// See ToURLValues for details on values conversion.
+func (v *Value) ToHeader() (http.Header, error) {
+	h := make(http.Header)
+	if err := v.visitFlat(func(key, value string) { h.Add(key, value) }); err != nil {
+		return nil, fmt.Errorf("cannot convert value to http.Header: %s", err)
+	}
+	return h, nil
+}
+
+func (v *Value) visitFlat(f func(key, value string)) error {
+	o, err := v.Object()
+	if err != nil {
+		return err
+	}
+	var b []byte
+	o.unescapeKeys()
+	for _, kv := range o.kvs {
+		if kv.v.Type() != TypeArray {
+			b, err = appendScalarString(b[:0], kv.v)
+			if err != nil {
+				return fmt.Errorf("cannot convert value for key %q: %s", kv.k, err)
+			}
+			if kv.v.t != TypeNull {
+				f(kv.k, string(b))
+			}
+			continue
+		}
+		for i, item := range kv.v.a {
+			b, err = appendScalarString(b[:0], item)
+			if err != nil {
+				return fmt.Errorf("cannot convert array item #%d for key %q: %s", i, kv.k, err)
+			}
+			if item.t != TypeNull {
+				f(kv.k, string(b))
+			}
+		}
+	}
+	return nil
+}
+
+// appendScalarString appends textual representation of scalar v to dst.
+func appendScalarString(dst []byte, v *Value) ([]byte, error) {
+	switch v.Type() {
+	case TypeString, TypeNumber:
+		return append(dst, v.s...), nil
+	case TypeTrue:
+		return append(dst, "true"...), nil
+	case TypeFalse:
+		return append(dst, "false"...), nil
+	case TypeNull:
+		return dst, nil
+	default:
+		return dst, fmt.Errorf("unexpected value type: %s", v.Type())
+	}
+}
+
+// NewObjectFromURLValues returns new object value containing q.
+//
+// Keys with a single value are converted to strings, while keys
+// with multiple values are converted to arrays of strings.
+// Object keys are sorted.
+//
+// The returned object is valid until Reset is called on a.
+func (a *Arena) NewObjectFromURLValues(q url.Values) *Value {
+	return a.newObjectFromMultiMap(q)
+}
+
+// NewObjectFromHeader returns new object value containing h.
+//
+// See NewObjectFromURLValues for details.
+//
+// The returned object is valid until Reset is called on a.
+func (a *Arena) NewObjectFromHeader(h http.Header) *Value {
+	return a.newObjectFromMultiMap(h)
+}
+
+func (a *Arena) newObjectFromMultiMap(m map[string][]string) *Value {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	o := a.NewObject()
+	for _, k := range keys {
+		vs := m[k]
+		if len(vs) == 1 {
+			o.o.Set(k, a.NewString(vs[0]))
+			continue
+		}
+		arr := a.NewArray()
+		for _, s := range vs {
+			arr.a = append(arr.a, a.NewString(s))
+		}
+		o.o.Set(k, arr)
+	}
+	return o
+}
+
+// URLValuesOptions contains options for Arena.FromURLValues.
+type URLValuesOptions struct {
+	// MaxDepth is the maximum number of brackets in keys, which are converted
+	// to nested values. The rest of the key is used as is as the last key,
+	// e.g. a[b][c][d] is converted to {"a":{"b":{"[c][d]":...}}} for MaxDepth=1.
+	//
+	// 5 is used if MaxDepth is zero.
+	MaxDepth int
+
+	// MaxArrayIndex is the maximum array index in bracket keys.
+	// Bigger indexes are used as object keys, so keys like a[1000000]
+	// cannot allocate huge arrays.
+	//
+	// 20 is used if MaxArrayIndex is zero.
+	MaxArrayIndex int
+
+	// ParseTypes enables converting values, which look like JSON numbers,
+	// true, false and null, to the corresponding JSON values.
+	// All the values are converted to strings otherwise.
+	ParseTypes bool
+}
+
+// FromURLValues returns new object value containing q with keys
+// in bracket notation converted to nested values according to opts.
+//
+// The zero URLValuesOptions are used if opts is nil.
+//
+// For example, a[b][0][c]=1 is converted to {"a":{"b":[{"c":"1"}]}},
+// while a[]=x&a[]=y is converted to {"a":["x","y"]}. Array items missing
+// in q are set to null. Keys with multiple values are converted to arrays
+// of values. Malformed bracket keys such as a[b are used as is.
+//
+// Keys are processed in sorted order. Arrays are converted to objects
+// keyed by item indexes if they conflict with object keys, e.g. a[0]=x
+// and a[b]=y. Keys, which conflict with already converted values,
+// such as a[b] for a=x, are skipped.
+//
+// The returned object is valid until Reset is called on a.
+func (a *Arena) FromURLValues(q url.Values, opts *URLValuesOptions) *Value {
+	if opts == nil {
+		opts = &URLValuesOptions{}
+	}
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 5
+	}
+	maxArrayIndex := opts.MaxArrayIndex
+	if maxArrayIndex <= 0 {
+		maxArrayIndex = 20
+	}
+	uc := urlValuesConverter{
+		a:             a,
+		maxArrayIndex: maxArrayIndex,
+		parseTypes:    opts.ParseTypes,
+	}
+
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	o := a.NewObject()
+	for _, k := range keys {
+		vs := q[k]
+		if len(vs) == 0 {
+			continue
+		}
+		path := splitBracketKey(k, maxDepth)
+		if path[len(path)-1] == "" {
+			for _, s := range vs {
+				uc.set(o, path, uc.newValue(s))
+			}
+			continue
+		}
+		if len(vs) == 1 {
+			uc.set(o, path, uc.newValue(vs[0]))
+			continue
+		}
+		arr := a.NewArray()
+		for _, s := range vs {
+			arr.a = append(arr.a, uc.newValue(s))
+		}
+		uc.set(o, path, arr)
+	}
+	return o
+}
+
+type urlValuesConverter struct {
+	a             *Arena
+	maxArrayIndex int
+	parseTypes    bool
+}
+
+func (uc *urlValuesConverter) newValue(s string) *Value {
+	if uc.parseTypes {
+		switch s {
+		case "true":
+			return valueTrue
+		case "false":
+			return valueFalse
+		case "null":
+			return valueNull
+		}
+		if tail, err := validateNumber(s); err == nil && tail == "" {
+			return uc.a.NewNumberString(s)
+		}
+	}
+	return uc.a.NewString(s)
+}
+
+// set sets leaf at the given path in v, which must be an object or an array.
+func (uc *urlValuesConverter) set(v *Value, path []string, leaf *Value) {
+	k := path[0]
+	if v.t == TypeArray {
+		n, ok := uc.arrayIndex(k, len(v.a))
+		if !ok {
+			arrayToObject(v)
+		} else {
+			for len(v.a) <= n {
+				v.a = append(v.a, valueNull)
+			}
+			if len(path) == 1 {
+				if v.a[n].t == TypeNull {
+					v.a[n] = leaf
+				}
+				return
+			}
+			child := v.a[n]
+			if child.t == TypeNull {
+				child = uc.newContainer(path[1])
+				v.a[n] = child
+			}
+			if child.t == TypeObject || child.t == TypeArray {
+				uc.set(child, path[1:], leaf)
+			}
+			return
+		}
+	}
+
+	if k == "" {
+		k = strconv.Itoa(len(v.o.kvs))
+	}
+	child := v.o.Get(k)
+	if len(path) == 1 {
+		if child == nil {
+			v.o.Set(k, leaf)
+		}
+		return
+	}
+	if child == nil {
+		child = uc.newContainer(path[1])
+		v.o.Set(k, child)
+	}
+	if child.t == TypeObject || child.t == TypeArray {
+		uc.set(child, path[1:], leaf)
+	}
+}
+
+// arrayIndex returns array index for key k in the array with length n.
+func (uc *urlValuesConverter) arrayIndex(k string, n int) (int, bool) {
+	if k == "" {
+		return n, true
+	}
+	if len(k) > 1 && k[0] == '0' {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(k)
+	if err != nil || idx < 0 || idx > uc.maxArrayIndex {
+		return 0, false
+	}
+	return idx, true
+}
+
+func (uc *urlValuesConverter) newContainer(k string) *Value {
+	if _, ok := uc.arrayIndex(k, 0); ok {
+		return uc.a.NewArray()
+	}
+	return uc.a.NewObject()
+}
+
+// arrayToObject converts array v to object with item indexes as keys.
+//
+// Null items are skipped, since they are missing in the original url.Values.
+func arrayToObject(v *Value) {
+	items := v.a
+	v.t = TypeObject
+	v.a = nil
+	v.o.reset()
+	v.o.keysUnescaped = true
+	for i, item := range items {
+		if item.t != TypeNull {
+			v.o.Set(strconv.Itoa(i), item)
+		}
+	}
+}
+
+// splitBracketKey splits key k in bracket notation into path.
+//
+// The rest of k after maxDepth brackets is used as the last path item.
+// k is returned as is if it is malformed.
+func splitBracketKey(k string, maxDepth int) []string {
+	n := strings.IndexByte(k, '[')
+	if n <= 0 {
+		return []string{k}
+	}
+	path := []string{k[:n]}
+	s := k[n:]
+	for len(s) > 0 {
+		if len(path) > maxDepth {
+			return append(path, s)
+		}
+		if s[0] != '[' {
+			return []string{k}
+		}
+		n := strings.IndexByte(s, ']')
+		if n < 0 || strings.IndexByte(s[1:n], '[') >= 0 {
+			return []string{k}
+		}
+		path = append(path, s[1:n])
+		s = s[n+1:]
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package fastjson
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValueToURLValues(t *testing.T) {
+	v := MustParse(`{"a":"foo","b":123,"c":[1,"x",true],"d":null,"e":false,"f!":"y z"}`)
+	q, err := v.ToURLValues()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	qExpected := url.Values{
+		"a":  {"foo"},
+		"b":  {"123"},
+		"c":  {"1", "x", "true"},
+		"e":  {"false"},
+		"f!": {"y z"},
+	}
+	if !reflect.DeepEqual(q, qExpected) {
+		t.Fatalf("unexpected url.Values; got %v; want %v", q, qExpected)
+	}
+
+	h, err := MustParse(`{"content-type":"text/plain","x-foo":["a","b"]}`).ToHeader()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	hExpected := http.Header{
+		"Content-Type": {"text/plain"},
+		"X-Foo":        {"a", "b"},
+	}
+	if !reflect.DeepEqual(h, hExpected) {
+		t.Fatalf("unexpected http.Header; got %v; want %v", h, hExpected)
+	}
+
+	for _, s := range []string{`[]`, `"foo"`} {
+		if _, err := MustParse(s).ToURLValues(); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+	}
+	for _, s := range []string{`[]`, `"foo"`, `{"a":{}}`, `{"a":[[1]]}`} {
+		if _, err := MustParse(s).ToHeader(); err == nil {
+			t.Fatalf("expecting non-nil error for %s", s)
+		}
+	}
+}
+
+func TestValueToURLValuesNested(t *testing.T) {
+	f := func(s string, qExpected url.Values) {
+		t.Helper()
+		q, err := MustParse(s).ToURLValues()
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", s, err)
+		}
+		if !reflect.DeepEqual(q, qExpected) {
+			t.Fatalf("unexpected url.Values for %s; got %v; want %v", s, q, qExpected)
+		}
+	}
+
+	f(`{}`, url.Values{})
+	f(`{"a":{},"b":[],"c":{"d":null}}`, url.Values{})
+	f(`{"a":{"b":"x","c":[1,2]}}`, url.Values{
+		"a[b]": {"x"},
+		"a[c]": {"1", "2"},
+	})
+	f(`{"a":{"b":[{"c":1},{"d":true}]}}`, url.Values{
+		"a[b][0][c]": {"1"},
+		"a[b][1][d]": {"true"},
+	})
+	f(`{"a":[[1,2],"x",null]}`, url.Values{
+		"a[0]": {"1", "2"},
+		"a[1]": {"x"},
+	})
+	f(`{"a\u005bb":{"c\"":"d"}}`, url.Values{
+		"a[b[c\"]": {"d"},
+	})
+}
+
+func TestArenaFromURLValues(t *testing.T) {
+	f := func(qs string, opts *URLValuesOptions, resultExpected string) {
+		t.Helper()
+		q, err := url.ParseQuery(qs)
+		if err != nil {
+			t.Fatalf("cannot parse query %q: %s", qs, err)
+		}
+		var a Arena
+		result := a.FromURLValues(q, opts).String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q\ngot\n%s\nwant\n%s", qs, result, resultExpected)
+		}
+	}
+
+	f(``, nil, `{}`)
+	f(`a=1&b=x&b=y`, nil, `{"a":"1","b":["x","y"]}`)
+	f(`a[b]=1&a[c]=2`, nil, `{"a":{"b":"1","c":"2"}}`)
+	f(`a[]=x&a[]=y`, nil, `{"a":["x","y"]}`)
+	f(`a[]=x`, nil, `{"a":["x"]}`)
+	f(`a[0]=x&a[2]=z`, nil, `{"a":["x",null,"z"]}`)
+	f(`a[1][b]=y&a[0][b]=x&a[0][c]=z`, nil, `{"a":[{"b":"x","c":"z"},{"b":"y"}]}`)
+	f(`a[b][0][c]=1&a[b][0][d][]=2&a[b][0][d][]=3`, nil, `{"a":{"b":[{"c":"1","d":["2","3"]}]}}`)
+	f(`a[b]=1&a[b]=2`, nil, `{"a":{"b":["1","2"]}}`)
+	f(`a[0]=x&a[b]=y`, nil, `{"a":{"0":"x","b":"y"}}`)
+	f(`a[]=x&a[x]=y`, nil, `{"a":{"0":"x","x":"y"}}`)
+	f(`a=1&a[b]=2`, nil, `{"a":"1"}`)
+	f(`a[21]=x&a[01]=y`, nil, `{"a":{"01":"y","21":"x"}}`)
+	f(`a[100]=x`, &URLValuesOptions{MaxArrayIndex: 100}, `{"a":[`+strings.Repeat("null,", 100)+`"x"]}`)
+	f(`a[b][c][d][e][f][g]=1`, nil, `{"a":{"b":{"c":{"d":{"e":{"f":{"[g]":"1"}}}}}}}`)
+	f(`a[b][c][d]=1`, &URLValuesOptions{MaxDepth: 1}, `{"a":{"b":{"[c][d]":"1"}}}`)
+	f(`a[b=1&[c]=2&d]=3&e[f]g=4&h[i[j]]=5`, nil, `{"[c]":"2","a[b":"1","d]":"3","e[f]g":"4","h[i[j]]":"5"}`)
+	f(`a[]=1&a[]=true&b=null&c=1.5e3&d=x&e=01`, &URLValuesOptions{ParseTypes: true}, `{"a":[1,true],"b":null,"c":1.5e3,"d":"x","e":"01"}`)
+	f(`a="q"`, nil, `{"a":"\"q\""}`)
+
+	// Round trip
+	v := MustParse(`{"a":{"b":[{"c":"1"},{"d":["x","y"]}]},"e":"z"}`)
+	q, err := v.ToURLValues()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var a Arena
+	if result := a.FromURLValues(q, nil).String(); result != v.String() {
+		t.Fatalf("unexpected round trip result\ngot\n%s\nwant\n%s", result, v)
+	}
+}
+
+func TestArenaNewObjectFromURLValues(t *testing.T) {
+	var a Arena
+	q := url.Values{
+		"b": {"x", "y\"z"},
+		"a": {"1"},
+	}
+	v := a.NewObjectFromURLValues(q)
+	s := v.String()
+	sExpected := `{"a":"1","b":["x","y\"z"]}`
+	if s != sExpected {
+		t.Fatalf("unexpected object; got %s; want %s", s, sExpected)
+	}
+
+	// Round trip.
+	q1, err := v.ToURLValues()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(q1, q) {
+		t.Fatalf("unexpected url.Values; got %v; want %v", q1, q)
+	}
+
+	h := http.Header{"X-Id": {"42"}}
+	v = a.NewObjectFromHeader(h)
+	if s := v.String(); s != `{"X-Id":"42"}` {
+		t.Fatalf("unexpected object; got %s; want %s", s, `{"X-Id":"42"}`)
+	}
+}
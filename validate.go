@@ -2,30 +2,273 @@ package fastjson
 
 import (
 	"fmt"
+	"io"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Validate validates JSON s.
+//
+// *SyntaxError with the position of the first failure is returned
+// for invalid JSON.
 func Validate(s string) error {
+	src := s
 	s = skipWS(s)
 
 	tail, err := validateValue(s)
 	if err != nil {
-		return fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(tail))
+		return newSyntaxError(src, tail, fmt.Sprintf("cannot parse JSON: %s", err))
 	}
 	tail = skipWS(tail)
 	if len(tail) > 0 {
-		return fmt.Errorf("unexpected tail: %q", startEndString(tail))
+		return newSyntaxError(src, tail, "unexpected tail")
 	}
 	return nil
 }
 
+// SyntaxError is returned by Validate and ValidateBytes for invalid JSON.
+type SyntaxError struct {
+	// Msg describes the error.
+	Msg string
+
+	// Offset is the byte offset of the first failure in the validated JSON.
+	Offset int
+
+	// Line is the 1-based line number of the first failure.
+	Line int
+
+	// Column is the 1-based column of the first failure in bytes.
+	Column int
+
+	tail string
+}
+
+// Error implements error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d (offset %d); unparsed tail: %q", e.Msg, e.Line, e.Column, e.Offset, startEndString(e.tail))
+}
+
+// newSyntaxError returns SyntaxError for the given unparsed tail of src.
+func newSyntaxError(src, tail, msg string) *SyntaxError {
+	offset := len(src)
+	if strings.HasSuffix(src, tail) {
+		offset -= len(tail)
+	}
+	line := 1 + strings.Count(src[:offset], "\n")
+	column := offset - strings.LastIndexByte(src[:offset], '\n')
+	return &SyntaxError{
+		Msg:    msg,
+		Offset: offset,
+		Line:   line,
+		Column: column,
+		tail:   src[offset:],
+	}
+}
+
 // ValidateBytes validates JSON b.
 func ValidateBytes(b []byte) error {
 	return Validate(b2s(b))
 }
 
+// validateReaderBufSize is the size of the buffer used by ValidateReader.
+const validateReaderBufSize = 64 * 1024
+
+// ValidateReader validates JSON read from r.
+//
+// The data is validated in chunks via a fixed-size rolling buffer,
+// so arbitrarily large documents may be validated with small memory usage.
+// The buffer grows only if a single string or number doesn't fit it.
+func ValidateReader(r io.Reader) error {
+	vr := readerValidator{
+		r:   r,
+		buf: make([]byte, 0, validateReaderBufSize),
+	}
+	return vr.validate()
+}
+
+type readerValidator struct {
+	r   io.Reader
+	buf []byte
+	eof bool
+	t   Tokenizer
+}
+
+func (vr *readerValidator) validate() error {
+	t := &vr.t
+	t.Init("")
+	for {
+		s, state := t.s, t.state
+		tok, err := t.next()
+		if err != nil {
+			if err == io.EOF && vr.eof {
+				return fmt.Errorf("cannot parse JSON: cannot parse empty string")
+			}
+			errTail := t.s
+			t.s, t.state = s, state
+			if !vr.eof && mayBeTruncated(errTail) {
+				if err := vr.fill(); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(errTail))
+		}
+		if tok.Type == TokenNumber && len(t.s) == 0 && !vr.eof {
+			// The number may be truncated.
+			t.s, t.state = s, state
+			if err := vr.fill(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := validateToken(tok); err != nil {
+			return fmt.Errorf("cannot parse JSON: %s; unparsed tail: %q", err, startEndString(b2s(tok.Raw)+t.s))
+		}
+		if t.Depth() == 0 {
+			break
+		}
+	}
+
+	// Verify there is only whitespace after the value.
+	for {
+		t.s = skipWS(t.s)
+		if len(t.s) > 0 {
+			return fmt.Errorf("unexpected tail: %q", startEndString(t.s))
+		}
+		if vr.eof {
+			return nil
+		}
+		if err := vr.fill(); err != nil {
+			return err
+		}
+	}
+}
+
+// fill moves the unprocessed data to the start of vr.buf and reads
+// more data into it.
+func (vr *readerValidator) fill() error {
+	n := copy(vr.buf[:cap(vr.buf)], vr.t.s)
+	vr.buf = vr.buf[:n]
+	if n == cap(vr.buf) {
+		// The unprocessed token doesn't fit the buffer.
+		b := make([]byte, n, 2*n)
+		copy(b, vr.buf)
+		vr.buf = b
+	}
+	emptyReads := 0
+	for len(vr.buf) < cap(vr.buf) {
+		m, err := vr.r.Read(vr.buf[len(vr.buf):cap(vr.buf)])
+		vr.buf = vr.buf[:len(vr.buf)+m]
+		if err == io.EOF {
+			vr.eof = true
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read JSON: %s", err)
+		}
+		if m == 0 {
+			emptyReads++
+			if emptyReads >= maxEmptyReads {
+				return fmt.Errorf("cannot read JSON: %s", io.ErrNoProgress)
+			}
+		}
+	}
+	vr.t.s = b2s(vr.buf)
+	return nil
+}
+
+// mayBeTruncated returns true if the tokenizer error at s may be caused
+// by the end of the buffer, so it may go away after reading more data.
+func mayBeTruncated(s string) bool {
+	s = skipWS(s)
+	if len(s) == 0 {
+		return true
+	}
+	switch s[0] {
+	case '"':
+		_, _, err := parseRawString(s[1:])
+		return err != nil
+	case 't':
+		return len(s) < len("true") && strings.HasPrefix("true", s)
+	case 'f':
+		return len(s) < len("false") && strings.HasPrefix("false", s)
+	case 'n':
+		return len(s) < len("null") && strings.HasPrefix("null", s)
+	default:
+		return false
+	}
+}
+
+// validateToken validates strings, keys and numbers returned by Tokenizer.
+func validateToken(tok Token) error {
+	switch tok.Type {
+	case TokenString, TokenKey:
+		sv, _, err := validateString(b2s(tok.Raw[1:]))
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %s", tok.Type, err)
+		}
+		for i := 0; i < len(sv); i++ {
+			if sv[i] < 0x20 {
+				return fmt.Errorf("%s cannot contain control char 0x%02X", tok.Type, sv[i])
+			}
+		}
+	case TokenNumber:
+		tail, err := validateNumber(b2s(tok.Raw))
+		if err == nil && len(tail) > 0 {
+			err = fmt.Errorf("unexpected tail: %q", tail)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot parse number: %s", err)
+		}
+	}
+	return nil
+}
+
+// ValidateBatch validates JSON docs concurrently using the given number
+// of worker goroutines.
+//
+// The i-th item in the returned slice contains validation error for docs[i]
+// or nil if docs[i] contains valid JSON.
+//
+// GOMAXPROCS workers are used if workers <= 0.
+func ValidateBatch(docs [][]byte, workers int) []error {
+	errs := make([]error, len(docs))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+	if workers <= 1 {
+		for i, doc := range docs {
+			errs[i] = ValidateBytes(doc)
+		}
+		return errs
+	}
+
+	// Workers grab docs one by one, so slow docs don't stall other workers.
+	var next uint64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n := atomic.AddUint64(&next, 1) - 1
+				if n >= uint64(len(docs)) {
+					return
+				}
+				errs[n] = ValidateBytes(docs[n])
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
 func validateValue(s string) (string, error) {
 	if len(s) == 0 {
 		return s, fmt.Errorf("cannot parse empty string")
@@ -2,6 +2,7 @@ package fastjson
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -157,5 +158,195 @@ func TestValidate(t *testing.T) {
 		if got != exp {
 			t.Errorf("#%d: %q got valid? %v, exp? %v", i, in, got, exp)
 		}
+		errs := ValidateAllBytes(in)
+		if exp && len(errs) == 1 && strings.Contains(errs[0].Msg, "too big depth") {
+			// ValidateAll limits the nesting depth unlike Validate.
+			continue
+		}
+		if gotAll := len(errs) == 0; gotAll != exp {
+			t.Errorf("#%d: %q ValidateAll got valid? %v, exp? %v", i, in, gotAll, exp)
+		}
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	f := func(s string, offsetExpected, lineExpected, columnExpected int) {
+		t.Helper()
+		err := ValidateBytes([]byte(s))
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Fatalf("expecting *SyntaxError for %q; got %T: %v", s, err, err)
+		}
+		if se.Offset != offsetExpected || se.Line != lineExpected || se.Column != columnExpected {
+			t.Fatalf("unexpected position for %q; got offset=%d, line=%d, column=%d; want offset=%d, line=%d, column=%d",
+				s, se.Offset, se.Line, se.Column, offsetExpected, lineExpected, columnExpected)
+		}
+		if !strings.Contains(se.Error(), se.Msg) {
+			t.Fatalf("error message %q must contain %q", se.Error(), se.Msg)
+		}
+	}
+
+	f(``, 0, 1, 1)
+	f(`  `, 2, 1, 3)
+	f(`[1,2,]`, 5, 1, 6)
+	f(`{"a":1} x`, 8, 1, 9)
+	f(`{"a" 1}`, 5, 1, 6)
+	f("{\n  \"a\": 1,\n  \"b\": tru\n}", 19, 3, 8)
+	f("[1,\r\n2e]", 7, 2, 3)
+}
+
+func TestValidateBatch(t *testing.T) {
+	var docs [][]byte
+	for i := 0; i < 100; i++ {
+		if i%3 == 0 {
+			docs = append(docs, []byte(`{"foo": bar}`))
+		} else {
+			docs = append(docs, []byte(`{"foo": [1, "bar"]}`))
+		}
+	}
+	for _, workers := range []int{-1, 0, 1, 4, 1000} {
+		errs := ValidateBatch(docs, workers)
+		if len(errs) != len(docs) {
+			t.Fatalf("unexpected number of errors; got %d; want %d", len(errs), len(docs))
+		}
+		for i, err := range errs {
+			if i%3 == 0 && err == nil {
+				t.Fatalf("expecting non-nil error for doc #%d with workers=%d", i, workers)
+			}
+			if i%3 != 0 && err != nil {
+				t.Fatalf("unexpected error for doc #%d with workers=%d: %s", i, workers, err)
+			}
+		}
+	}
+
+	if errs := ValidateBatch(nil, 4); len(errs) != 0 {
+		t.Fatalf("unexpected errors for empty batch: %v", errs)
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		errExpected := Validate(s)
+		if err := ValidateReader(strings.NewReader(s)); (err == nil) != (errExpected == nil) {
+			t.Fatalf("unexpected error for %q; got %v; want %v", s, err, errExpected)
+		}
+		for _, bufSize := range []int{1, 2, 3, 5, 16} {
+			for _, chunkSize := range []int{1, 3, 1000} {
+				vr := readerValidator{
+					r: &chunkedReader{
+						s:         s,
+						chunkSize: chunkSize,
+					},
+					buf: make([]byte, 0, bufSize),
+				}
+				if err := vr.validate(); (err == nil) != (errExpected == nil) {
+					t.Fatalf("unexpected error for %q, bufSize=%d, chunkSize=%d; got %v; want %v", s, bufSize, chunkSize, err, errExpected)
+				}
+			}
+		}
 	}
+
+	// valid
+	f(`1`)
+	f(` -12.345e-67 `)
+	f(`"foo"`)
+	f(`"ሴ\n\"x\""`)
+	f(`true`)
+	f(`false`)
+	f(`null`)
+	f(`[]`)
+	f(`{}`)
+	f(` [ 1 , "a" , null , true , false , [ ] , { } ] `)
+	f(`{"a":{"b\"c":[1,2,{"d":"e"}]},"f":123456789012345678901234567890}`)
+
+	// invalid
+	f(``)
+	f(`   `)
+	f(`1 1`)
+	f(`[] {}`)
+	f(`tru`)
+	f(`nul`)
+	f(`truee`)
+	f(`NaN`)
+	f(`-`)
+	f(`01`)
+	f(`1.`)
+	f(`1e`)
+	f(`1.5.3`)
+	f(`"foo`)
+	f(`"\z"`)
+	f(`"\u12"`)
+	f("\"f\x00o\"")
+	f(`[`)
+	f(`[1,]`)
+	f(`[1 2]`)
+	f(`{"a"}`)
+	f(`{"a":1,}`)
+	f(`{"a\x":1}`)
+	f("{\"a\nb\":1}")
+	f(`{a:1}`)
+	f(`{"a":1]`)
+	f(`]`)
+
+	// big documents
+	f("[" + strings.Repeat(`{"a":"xxxx","b":[12.34e5,null]},`, 5000) + "1]")
+	f("[" + strings.Repeat(`{"a":"xxxx","b":[12.34e5,null]},`, 5000) + "]")
+	f(`"` + strings.Repeat("x", 100000) + `"`)
+}
+
+func TestValidateReaderError(t *testing.T) {
+	if err := ValidateReader(&failingReader{}); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	f := func(s string, errsExpected []string) {
+		t.Helper()
+		errs := ValidateAll(s)
+		var a []string
+		for _, err := range errs {
+			a = append(a, fmt.Sprintf("%d:%d %s", err.Line, err.Column, err.Msg))
+		}
+		if strings.Join(a, "\n") != strings.Join(errsExpected, "\n") {
+			t.Fatalf("unexpected errors for %q\ngot\n%s\nwant\n%s", s, strings.Join(a, "\n"), strings.Join(errsExpected, "\n"))
+		}
+	}
+
+	f(`{"a":[1,"x",true,null,{}]}`, nil)
+	f(`{"a": "x\qy", "b": 01, "c": tru, "d": "\uzz", "e": 1.5}`, []string{
+		`1:9 cannot parse JSON: string contains unknown escape sequence \q`,
+		`1:20 cannot parse JSON: cannot parse number "01": unexpected number starting from 0`,
+		`1:29 cannot parse JSON: unexpected value found: "tru"`,
+		`1:40 cannot parse JSON: string contains invalid escape sequence \uzz`,
+	})
+	f("[\"a\tb\",\n \"\\x\\y\", -, 1e]", []string{
+		`1:4 cannot parse JSON: string cannot contain control char 0x09`,
+		`2:3 cannot parse JSON: string contains unknown escape sequence \x`,
+		`2:5 cannot parse JSON: string contains unknown escape sequence \y`,
+		`2:10 cannot parse JSON: cannot parse number "-": missing number after minus`,
+		`2:13 cannot parse JSON: cannot parse number "1e": missing exponent part`,
+	})
+	f("{\"k\x01\": 1}", []string{
+		`1:4 cannot parse JSON: object key cannot contain control char 0x01`,
+	})
+
+	// Structural errors stop validation.
+	f(`[1x, "\q" 2, "\z"]`, []string{
+		`1:2 cannot parse JSON: cannot parse number "1x": unexpected char "x"`,
+		`1:7 cannot parse JSON: string contains unknown escape sequence \q`,
+		`1:11 cannot parse JSON: cannot parse array: missing ',' after array value`,
+	})
+	f(`{"a" 1}`, []string{`1:6 cannot parse JSON: cannot parse object: missing ':' after object key`})
+	f(`{"a":1} x`, []string{`1:9 unexpected tail`})
+	f(``, []string{`1:1 cannot parse JSON: cannot parse empty string`})
+	f(`[1,]`, []string{`1:4 cannot parse JSON: unexpected value found: "]"`})
+	f(`"abc`, []string{`1:2 cannot parse JSON: cannot parse string: missing closing '"'`})
+
+	// Too deep nesting stops validation.
+	nested := strings.Repeat(`{"a":[`, MaxDepth/2) + "1" + strings.Repeat("]}", MaxDepth/2)
+	f(nested, nil)
+	f("["+nested+"]", []string{fmt.Sprintf("1:%d cannot parse JSON: too big depth for the nested JSON; it exceeds %d", 3*MaxDepth+1, MaxDepth)})
+	f(strings.Repeat("[", 1e6), []string{fmt.Sprintf("1:%d cannot parse JSON: too big depth for the nested JSON; it exceeds %d", MaxDepth+1, MaxDepth)})
 }
@@ -0,0 +1,236 @@
+package fastjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ValidateAll validates JSON s and returns all the found errors.
+//
+// Unlike Validate, ValidateAll continues after recoverable errors
+// such as invalid escape sequences, control chars in strings, malformed
+// numbers and unknown literals, so all of them are reported at once.
+// Validation stops at the first structural error such as missing ','
+// or unclosed object, since the rest of s cannot be interpreted reliably.
+// Values nested deeper than MaxDepth are reported as structural errors.
+//
+// Errors are returned in the order of their offsets. nil is returned
+// for valid JSON.
+func ValidateAll(s string) []*SyntaxError {
+	mv := multiValidator{
+		src: s,
+	}
+	s = skipWS(s)
+	tail, ok := mv.validateValue(s)
+	if ok {
+		tail = skipWS(tail)
+		if len(tail) > 0 {
+			mv.addError(tail, "unexpected tail")
+		}
+	}
+	return mv.errs
+}
+
+// ValidateAllBytes validates JSON b and returns all the found errors.
+//
+// See ValidateAll for details.
+func ValidateAllBytes(b []byte) []*SyntaxError {
+	return ValidateAll(b2s(b))
+}
+
+// multiValidator validates JSON and collects errors.
+//
+// Its methods return false on unrecoverable errors.
+type multiValidator struct {
+	src  string
+	errs []*SyntaxError
+
+	// depth is the nesting depth of the currently validated value.
+	depth int
+}
+
+func (mv *multiValidator) addError(tail, msg string) {
+	mv.errs = append(mv.errs, newSyntaxError(mv.src, tail, msg))
+}
+
+func (mv *multiValidator) validateValue(s string) (string, bool) {
+	if len(s) == 0 {
+		mv.addError(s, "cannot parse JSON: cannot parse empty string")
+		return s, false
+	}
+	switch s[0] {
+	case '{', '[':
+		mv.depth++
+		if mv.depth > MaxDepth {
+			mv.addError(s, fmt.Sprintf("cannot parse JSON: too big depth for the nested JSON; it exceeds %d", MaxDepth))
+			return s, false
+		}
+		var tail string
+		var ok bool
+		if s[0] == '{' {
+			tail, ok = mv.validateObject(s[1:])
+		} else {
+			tail, ok = mv.validateArray(s[1:])
+		}
+		mv.depth--
+		return tail, ok
+	case '"':
+		return mv.validateString(s[1:], "string")
+	}
+	n := 0
+	for n < len(s) && !isValueDelimiter(s[n]) {
+		n++
+	}
+	if n == 0 {
+		mv.addError(s, fmt.Sprintf("cannot parse JSON: unexpected value found: %q", startEndString(s)))
+		return s, false
+	}
+	value := s[:n]
+	switch value {
+	case "true", "false", "null":
+		return s[n:], true
+	}
+	if c := value[0]; c != '-' && (c < '0' || c > '9') {
+		// Recover at the next delimiter.
+		mv.addError(s, fmt.Sprintf("cannot parse JSON: unexpected value found: %q", value))
+		return s[n:], true
+	}
+	tail, err := validateNumber(value)
+	if err == nil && len(tail) > 0 {
+		err = fmt.Errorf("unexpected char %q", tail[:1])
+	}
+	if err != nil {
+		mv.addError(s, fmt.Sprintf("cannot parse JSON: cannot parse number %q: %s", value, err))
+	}
+	return s[n:], true
+}
+
+// isValueDelimiter returns true if c may follow a JSON value.
+func isValueDelimiter(c byte) bool {
+	switch c {
+	case ',', ']', '}', ':', ' ', '\t', '\n', '\r', '"', '[', '{':
+		return true
+	default:
+		return false
+	}
+}
+
+// validateString validates the string following the opening quote.
+func (mv *multiValidator) validateString(s, what string) (string, bool) {
+	rs, tail, err := parseRawString(s)
+	if err != nil {
+		mv.addError(s, fmt.Sprintf("cannot parse JSON: cannot parse %s: %s", what, err))
+		return tail, false
+	}
+	for i := 0; i < len(rs); i++ {
+		c := rs[i]
+		if c < 0x20 {
+			mv.addError(s[i:], fmt.Sprintf("cannot parse JSON: %s cannot contain control char 0x%02X", what, c))
+			continue
+		}
+		if c != '\\' {
+			continue
+		}
+		if i+1 >= len(rs) {
+			// parseRawString never returns strings with trailing backslash.
+			break
+		}
+		switch ch := rs[i+1]; ch {
+		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			i++
+		case 'u':
+			xs := rs[i+2:]
+			if len(xs) > 4 {
+				xs = xs[:4]
+			}
+			if _, err := strconv.ParseUint(xs, 16, 16); len(xs) < 4 || err != nil {
+				mv.addError(s[i:], fmt.Sprintf(`cannot parse JSON: %s contains invalid escape sequence \u%s`, what, xs))
+			}
+			i++
+		default:
+			mv.addError(s[i:], fmt.Sprintf(`cannot parse JSON: %s contains unknown escape sequence \%c`, what, ch))
+			i++
+		}
+	}
+	return tail, true
+}
+
+func (mv *multiValidator) validateArray(s string) (string, bool) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		mv.addError(s, "cannot parse JSON: cannot parse array: missing ']'")
+		return s, false
+	}
+	if s[0] == ']' {
+		return s[1:], true
+	}
+	for {
+		var ok bool
+		s = skipWS(s)
+		s, ok = mv.validateValue(s)
+		if !ok {
+			return s, false
+		}
+		s = skipWS(s)
+		if len(s) == 0 {
+			mv.addError(s, "cannot parse JSON: cannot parse array: unexpected end of array")
+			return s, false
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == ']' {
+			return s[1:], true
+		}
+		mv.addError(s, "cannot parse JSON: cannot parse array: missing ',' after array value")
+		return s, false
+	}
+}
+
+func (mv *multiValidator) validateObject(s string) (string, bool) {
+	s = skipWS(s)
+	if len(s) == 0 {
+		mv.addError(s, "cannot parse JSON: cannot parse object: missing '}'")
+		return s, false
+	}
+	if s[0] == '}' {
+		return s[1:], true
+	}
+	for {
+		var ok bool
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != '"' {
+			mv.addError(s, `cannot parse JSON: cannot parse object: cannot find opening '"' for object key`)
+			return s, false
+		}
+		s, ok = mv.validateString(s[1:], "object key")
+		if !ok {
+			return s, false
+		}
+		s = skipWS(s)
+		if len(s) == 0 || s[0] != ':' {
+			mv.addError(s, "cannot parse JSON: cannot parse object: missing ':' after object key")
+			return s, false
+		}
+		s = skipWS(s[1:])
+		s, ok = mv.validateValue(s)
+		if !ok {
+			return s, false
+		}
+		s = skipWS(s)
+		if len(s) == 0 {
+			mv.addError(s, "cannot parse JSON: cannot parse object: unexpected end of object")
+			return s, false
+		}
+		if s[0] == ',' {
+			s = s[1:]
+			continue
+		}
+		if s[0] == '}' {
+			return s[1:], true
+		}
+		mv.addError(s, "cannot parse JSON: cannot parse object: missing ',' after object value")
+		return s, false
+	}
+}
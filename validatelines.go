@@ -0,0 +1,83 @@
+package fastjson
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// ValidateLines validates JSON lines ( http://jsonlines.org/ ) read from r.
+//
+// Every non-empty line must contain a single valid JSON value.
+// Empty and whitespace-only lines are skipped.
+//
+// The number of valid records is returned. *RecordError describing
+// the first bad record is returned on validation failure.
+func ValidateLines(r io.Reader) (int, error) {
+	br := bufio.NewReaderSize(r, validateReaderBufSize)
+	records := 0
+	lineNum := 0
+	offset := int64(0)
+	var buf []byte
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			// The line doesn't fit br buffer - accumulate it in buf.
+			buf = append(buf[:0], line...)
+			for err == bufio.ErrBufferFull {
+				line, err = br.ReadSlice('\n')
+				buf = append(buf, line...)
+			}
+			line = buf
+		}
+		if err != nil && err != io.EOF {
+			return records, fmt.Errorf("cannot read JSON lines: %s", err)
+		}
+		if len(line) == 0 && err == io.EOF {
+			return records, nil
+		}
+
+		lineNum++
+		s := b2s(line)
+		if len(skipWS(s)) > 0 {
+			records++
+			if verr := Validate(s); verr != nil {
+				re := &RecordError{
+					Record: records,
+					Line:   lineNum,
+					Offset: offset,
+					Err:    verr,
+				}
+				if se, ok := verr.(*SyntaxError); ok {
+					re.Offset += int64(se.Offset)
+				}
+				return records - 1, re
+			}
+		}
+		offset += int64(len(line))
+		if err == io.EOF {
+			return records, nil
+		}
+	}
+}
+
+// RecordError is returned by ValidateLines for invalid record.
+type RecordError struct {
+	// Record is the 1-based number of the invalid record.
+	// Empty lines aren't counted.
+	Record int
+
+	// Line is the 1-based line number of the invalid record.
+	Line int
+
+	// Offset is the byte offset of the failure in the stream.
+	Offset int64
+
+	// Err is the validation error for the record.
+	Err error
+}
+
+// Error implements error interface.
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("invalid record #%d at line %d (offset %d): %s", e.Record, e.Line, e.Offset, e.Err)
+}
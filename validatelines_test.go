@@ -0,0 +1,66 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateLines(t *testing.T) {
+	f := func(s string, recordsExpected int) {
+		t.Helper()
+		records, err := ValidateLines(strings.NewReader(s))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if records != recordsExpected {
+			t.Fatalf("unexpected number of records; got %d; want %d", records, recordsExpected)
+		}
+
+		// Verify reading one byte at a time.
+		records, err = ValidateLines(&chunkedReader{s: s, chunkSize: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if records != recordsExpected {
+			t.Fatalf("unexpected number of records; got %d; want %d", records, recordsExpected)
+		}
+	}
+
+	f(``, 0)
+	f("\n\n \n", 0)
+	f(`{"a":1}`, 1)
+	f("{\"a\":1}\n[2]\n", 2)
+	f("{\"a\":1}\r\n\r\n\"x\"\r\n3", 3)
+	f(`"`+strings.Repeat("x", 3*validateReaderBufSize)+`"`+"\n1\n", 2)
+}
+
+func TestValidateLinesError(t *testing.T) {
+	f := func(s string, recordsExpected, recordExpected, lineExpected int, offsetExpected int64) {
+		t.Helper()
+		records, err := ValidateLines(strings.NewReader(s))
+		re, ok := err.(*RecordError)
+		if !ok {
+			t.Fatalf("expecting *RecordError; got %T: %v", err, err)
+		}
+		if records != recordsExpected {
+			t.Fatalf("unexpected number of records; got %d; want %d", records, recordsExpected)
+		}
+		if re.Record != recordExpected || re.Line != lineExpected || re.Offset != offsetExpected {
+			t.Fatalf("unexpected error position; got record=%d, line=%d, offset=%d; want record=%d, line=%d, offset=%d",
+				re.Record, re.Line, re.Offset, recordExpected, lineExpected, offsetExpected)
+		}
+		if _, ok := re.Err.(*SyntaxError); !ok {
+			t.Fatalf("expecting *SyntaxError; got %T: %v", re.Err, re.Err)
+		}
+	}
+
+	f(`{"a":`, 0, 1, 1, 5)
+	f("1\n\n[2,]\n3", 1, 2, 3, 6)
+	f("1\n2 3\n", 1, 2, 2, 4)
+	f(`"`+strings.Repeat("x", 2*validateReaderBufSize)+"\"\n{", 1, 2, 2, int64(2*validateReaderBufSize+4))
+
+	// Read error
+	if _, err := ValidateLines(&failingReader{}); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
@@ -0,0 +1,118 @@
+package fastjson
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ValidUTF8 returns true if all the strings and object keys in v
+// are valid UTF-8 after unescaping.
+//
+// Parser doesn't verify UTF-8 for the sake of speed, so strings may contain
+// invalid byte sequences from the input. Escape sequences for lone UTF-16
+// surrogates such as "\ud800" cannot be represented in UTF-8 either.
+// Such strings are rejected by many systems. Unescaping keeps lone
+// surrogate escapes as is, so the result doesn't depend on whether
+// the strings and keys have been already accessed.
+//
+// v isn't modified.
+func (v *Value) ValidUTF8() bool {
+	if v == nil {
+		return true
+	}
+	switch v.t {
+	case typeRawString:
+		return validUTF8String(v.s, true)
+	case TypeString:
+		return !v.loneSurrogates && validUTF8String(v.s, false)
+	case TypeObject:
+		return v.o.ValidUTF8()
+	case TypeArray:
+		for _, vv := range v.a {
+			if !vv.ValidUTF8() {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// ValidUTF8 returns true if all the keys and strings in o are valid UTF-8
+// after unescaping.
+//
+// See Value.ValidUTF8 for details.
+func (o *Object) ValidUTF8() bool {
+	if o == nil {
+		return true
+	}
+	if o.keysLoneSurrogates {
+		return false
+	}
+	for _, kv := range o.kvs {
+		if !validUTF8String(kv.k, !o.keysUnescaped) {
+			return false
+		}
+		if !kv.v.ValidUTF8() {
+			return false
+		}
+	}
+	return true
+}
+
+// validUTF8String returns true if s is valid UTF-8.
+//
+// If escaped is set, then s is a raw JSON string and its \u escape
+// sequences must not encode lone surrogates.
+func validUTF8String(s string, escaped bool) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	if !escaped {
+		return true
+	}
+	for {
+		n := strings.IndexByte(s, '\\')
+		if n < 0 {
+			return true
+		}
+		s = s[n+1:]
+		if len(s) == 0 {
+			return true
+		}
+		if s[0] != 'u' {
+			// Skip the escaped char, so "\\u" isn't treated as \u escape.
+			s = s[1:]
+			continue
+		}
+		r, ok := parseUTF16Escape(s)
+		s = s[1:]
+		if !ok || !utf16.IsSurrogate(r) {
+			continue
+		}
+		// The surrogate must be a high surrogate followed by a low surrogate.
+		if r >= 0xdc00 || len(s) < 5 || s[4] != '\\' {
+			return false
+		}
+		r1, ok := parseUTF16Escape(s[5:])
+		if !ok || utf16.DecodeRune(r, r1) == utf8.RuneError {
+			return false
+		}
+		s = s[4+6:]
+	}
+}
+
+// parseUTF16Escape parses "uXXXX" at the start of s.
+func parseUTF16Escape(s string) (rune, bool) {
+	if len(s) < 5 || s[0] != 'u' {
+		return 0, false
+	}
+	x, err := strconv.ParseUint(s[1:5], 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return rune(x), true
+}
@@ -0,0 +1,85 @@
+package fastjson
+
+import (
+	"testing"
+)
+
+func TestValueValidUTF8(t *testing.T) {
+	f := func(s string, resultExpected bool) {
+		t.Helper()
+		v := MustParse(s)
+		if result := v.ValidUTF8(); result != resultExpected {
+			t.Fatalf("unexpected ValidUTF8 result for %q; got %v; want %v", s, result, resultExpected)
+		}
+
+		// The result mustn't change after unescaping strings and keys.
+		vc := v.CloneForGoroutine()
+		v.Walk(func(path []string, v *Value) bool {
+			v.Type()
+			return true
+		})
+		if result := v.ValidUTF8(); result != resultExpected {
+			t.Fatalf("unexpected ValidUTF8 result after access for %q; got %v; want %v", s, result, resultExpected)
+		}
+		if result := vc.ValidUTF8(); result != resultExpected {
+			t.Fatalf("unexpected ValidUTF8 result for the clone of %q; got %v; want %v", s, result, resultExpected)
+		}
+	}
+
+	f(`null`, true)
+	f(`[1,true,{"a":null}]`, true)
+	f(`"привет"`, true)
+	f(`"п\n\\u"`, true)
+	f(`"😀"`, true)
+	f(`"x\\ud800"`, true)
+	f(`{"😀":"é"}`, true)
+
+	// Invalid bytes
+	f("\"a\xffb\"", false)
+	f("[\"ok\",\"\xc3\"]", false)
+	f("{\"\xff\":1}", false)
+	f("{\"a\":{\"b\":\"\xed\xa0\x80\"}}", false)
+
+	// Lone surrogates
+	f(`"\ud800"`, false)
+	f(`"\udc00\ud800"`, false)
+	f(`"\ud83dx"`, false)
+	f(`"\ud83dA"`, false)
+	f(`"\ud83d\ud83d"`, false)
+	f(`{"\udfff":1}`, false)
+	f(`[{"a":["\ud800"]}]`, false)
+
+	var vNil *Value
+	if !vNil.ValidUTF8() {
+		t.Fatalf("nil value must be valid")
+	}
+}
+
+func TestObjectValidUTF8(t *testing.T) {
+	v := MustParse(`{"aé":"b"}`)
+	o := v.GetObject()
+	if !o.ValidUTF8() {
+		t.Fatalf("unexpected invalid object")
+	}
+	// Unescape keys and verify again.
+	o.Get("x")
+	if !o.ValidUTF8() {
+		t.Fatalf("unexpected invalid object after keys unescaping")
+	}
+
+	v = MustParse(`{"a":"\ud800"}`)
+	if v.GetObject().ValidUTF8() {
+		t.Fatalf("unexpected valid object")
+	}
+	if sb := v.GetStringBytes("a"); string(sb) != `\ud800` {
+		t.Fatalf("unexpected string; got %q; want %q", sb, `\ud800`)
+	}
+	if v.GetObject().ValidUTF8() {
+		t.Fatalf("unexpected valid object after string unescaping")
+	}
+
+	var oNil *Object
+	if !oNil.ValidUTF8() {
+		t.Fatalf("nil object must be valid")
+	}
+}
@@ -0,0 +1,81 @@
+package fastjson
+
+import (
+	"strconv"
+)
+
+// Walk calls f for v and all the values nested in v in depth-first order.
+//
+// path contains keys path from v to the visited value. Array indexes
+// are represented as decimal numbers in path, so the visited value
+// may be obtained via v.Get(path...). The root v is visited with empty path.
+//
+// Children of the visited value are skipped if f returns false.
+//
+// f cannot hold path after returning.
+func (v *Value) Walk(f func(path []string, v *Value) bool) {
+	if v == nil {
+		return
+	}
+	path := make([]string, 0, 8)
+	v.walk(path, f)
+}
+
+func (v *Value) walk(path []string, f func(path []string, v *Value) bool) {
+	if !f(path, v) {
+		return
+	}
+	switch v.t {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for _, kv := range v.o.kvs {
+			kv.v.walk(append(path, kv.k), f)
+		}
+	case TypeArray:
+		for i, vv := range v.a {
+			vv.walk(append(path, strconv.Itoa(i)), f)
+		}
+	}
+}
+
+// Transform replaces every value nested in v with the value returned by f.
+//
+// f is called in depth-first order after the children of the value
+// have been transformed, so f sees already transformed children.
+// path contains keys path from v to the value in the format used by Walk.
+// v itself isn't passed to f, since it cannot be replaced in place.
+// nil values returned by f are stored as null.
+//
+// f cannot hold path after returning. The values returned by f must be
+// unchanged during v lifetime.
+func (v *Value) Transform(f func(path []string, v *Value) *Value) {
+	if v == nil {
+		return
+	}
+	path := make([]string, 0, 8)
+	v.transform(path, f)
+}
+
+func (v *Value) transform(path []string, f func(path []string, v *Value) *Value) {
+	switch v.Type() {
+	case TypeObject:
+		v.o.unescapeKeys()
+		for i := range v.o.kvs {
+			kv := &v.o.kvs[i]
+			kv.v = transformValue(append(path, kv.k), kv.v, f)
+		}
+	case TypeArray:
+		for i, vv := range v.a {
+			v.a[i] = transformValue(append(path, strconv.Itoa(i)), vv, f)
+		}
+	}
+}
+
+func transformValue(path []string, v *Value, f func(path []string, v *Value) *Value) *Value {
+	v.transform(path, f)
+	v = f(path, v)
+	if v == nil {
+		v = valueNull
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package fastjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValueWalk(t *testing.T) {
+	v := MustParse(`{"a":{"b":[1,{"c":"x"}]},"skip":{"d":2},"e":null}`)
+
+	var lines []string
+	v.Walk(func(path []string, vv *Value) bool {
+		lines = append(lines, fmt.Sprintf("%s=%s", strings.Join(path, "/"), vv.Type()))
+		if got := v.Get(path...); got != vv {
+			t.Fatalf("v.Get(%q) doesn't match the visited value", path)
+		}
+		return len(path) == 0 || path[0] != "skip"
+	})
+	result := strings.Join(lines, ",")
+	resultExpected := "=object,a=object,a/b=array,a/b/0=number,a/b/1=object,a/b/1/c=string,skip=object,e=null"
+	if result != resultExpected {
+		t.Fatalf("unexpected walk\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+
+	var vNil *Value
+	vNil.Walk(func(path []string, v *Value) bool {
+		t.Fatalf("unexpected call for nil value")
+		return true
+	})
+}
+
+func TestValueTransform(t *testing.T) {
+	v := MustParse(`{"a":{"b":["  x  ",{"c":" y"}]},"n":1,"drop":{"d":2}}`)
+
+	var paths []string
+	var a Arena
+	v.Transform(func(path []string, vv *Value) *Value {
+		paths = append(paths, strings.Join(path, "/"))
+		switch {
+		case path[len(path)-1] == "drop":
+			return nil
+		case vv.Type() == TypeString:
+			return a.NewString(strings.TrimSpace(string(vv.GetStringBytes())))
+		case vv.Type() == TypeNumber:
+			return a.NewNumberInt(vv.GetInt() * 10)
+		case vv.Type() == TypeObject && len(path) == 1 && path[0] == "a":
+			// Children must be already transformed.
+			if s := vv.String(); s != `{"b":["x",{"c":"y"}]}` {
+				t.Fatalf("unexpected children of %q: %s", path, s)
+			}
+		}
+		return vv
+	})
+	result := v.String()
+	resultExpected := `{"a":{"b":["x",{"c":"y"}]},"n":10,"drop":null}`
+	if result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+	pathsResult := strings.Join(paths, ",")
+	pathsExpected := "a/b/0,a/b/1/c,a/b/1,a/b,a,n,drop/d,drop"
+	if pathsResult != pathsExpected {
+		t.Fatalf("unexpected paths\ngot\n%s\nwant\n%s", pathsResult, pathsExpected)
+	}
+
+	var vNil *Value
+	vNil.Transform(func(path []string, v *Value) *Value {
+		t.Fatalf("unexpected call for nil value")
+		return v
+	})
+}
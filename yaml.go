@@ -0,0 +1,911 @@
+package fastjson
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseYAML parses b containing a single YAML document.
+//
+// The function is slower than the Parser.ParseYAML for re-used Parser.
+func ParseYAML(b []byte) (*Value, error) {
+	var p Parser
+	return p.ParseYAML(b)
+}
+
+// ParseYAML parses b containing a single YAML document.
+//
+// The following YAML subset is supported:
+//
+//   - block mappings and sequences, including sequences nested at the same
+//     indentation as the parent mapping key;
+//   - flow mappings and sequences such as {a: 1, b: [2, 3]};
+//   - plain, single-quoted and double-quoted scalars;
+//   - literal (|) and folded (>) block scalars with chomping indicators;
+//   - comments and the document markers --- and ...
+//
+// Plain scalars are resolved according to the YAML 1.2 core schema:
+// null, ~ and empty values become null, true and false become booleans,
+// decimal, octal (0o) and hexadecimal (0x) integers and floats become
+// numbers, while the rest of scalars become strings. Mapping keys are
+// always strings.
+//
+// Anchors, aliases, tags, complex keys, directives, multiple documents,
+// .inf and .nan result in error, since they have no JSON counterpart
+// or are rarely used in configs.
+//
+// The returned value is valid until the next call to Parse*.
+func (p *Parser) ParseYAML(b []byte) (*Value, error) {
+	p.b = append(p.b[:0], b...)
+	p.c.reset()
+
+	s := b2s(p.b)
+	s = strings.TrimPrefix(s, "\ufeff")
+	yp := yamlParser{
+		c: &p.c,
+	}
+	if err := yp.init(s); err != nil {
+		return nil, fmt.Errorf("cannot parse YAML at line %d: %s", yp.n+1, err)
+	}
+	v, err := yp.parseBlock(-1, false)
+	if err == nil {
+		if _, _, ok, errPeek := yp.peek(); errPeek != nil {
+			err = errPeek
+		} else if ok {
+			err = fmt.Errorf("unexpected content; check the indentation")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse YAML at line %d: %s", yp.n+1, err)
+	}
+	return v, nil
+}
+
+type yamlParser struct {
+	lines []string
+
+	// n is the index of the current line in lines.
+	n int
+
+	// depth is the nesting depth of the currently parsed block node.
+	depth int
+
+	c *cache
+}
+
+// init splits s into lines and strips document markers.
+func (p *yamlParser) init(s string) error {
+	p.lines = strings.Split(s, "\n")
+	for i, line := range p.lines {
+		p.lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	hasContent := false
+	for i, line := range p.lines {
+		p.n = i
+		switch {
+		case line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t"):
+			if hasContent {
+				return fmt.Errorf("multiple documents aren't supported")
+			}
+			p.lines[i] = strings.TrimLeft(line[len("---"):], " \t")
+			hasContent = !isYAMLBlank(p.lines[i])
+		case line == "..." || strings.HasPrefix(line, "... "):
+			for j := i + 1; j < len(p.lines); j++ {
+				if !isYAMLBlank(p.lines[j]) {
+					p.n = j
+					return fmt.Errorf("multiple documents aren't supported")
+				}
+			}
+			p.lines = p.lines[:i]
+			p.n = 0
+			return nil
+		case strings.HasPrefix(line, "%") && !hasContent:
+			return fmt.Errorf("directives aren't supported")
+		default:
+			if !isYAMLBlank(line) {
+				hasContent = true
+			}
+		}
+	}
+	p.n = 0
+	return nil
+}
+
+// isYAMLBlank returns true if line contains only whitespace and comments.
+func isYAMLBlank(line string) bool {
+	line = strings.TrimLeft(line, " \t")
+	return line == "" || line[0] == '#'
+}
+
+// peek returns the indentation and the contents of the next non-blank line
+// without consuming it.
+func (p *yamlParser) peek() (int, string, bool, error) {
+	for p.n < len(p.lines) {
+		line := p.lines[p.n]
+		if isYAMLBlank(line) {
+			p.n++
+			continue
+		}
+		text := strings.TrimLeft(line, " ")
+		indent := len(line) - len(text)
+		if text[0] == '\t' {
+			return 0, "", false, fmt.Errorf("tabs cannot be used for indentation")
+		}
+		return indent, strings.TrimRight(text, " \t"), true, nil
+	}
+	return 0, "", false, nil
+}
+
+func isYAMLSeqEntry(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ") || strings.HasPrefix(text, "-\t")
+}
+
+// parseBlock parses block node, which must be indented more than parentIndent.
+//
+// Sequences at parentIndent are allowed if sameIndentSeq is set,
+// since they may be used as mapping values.
+func (p *yamlParser) parseBlock(parentIndent int, sameIndentSeq bool) (*Value, error) {
+	p.depth++
+	defer func() {
+		p.depth--
+	}()
+	if p.depth > MaxDepth {
+		return nil, fmt.Errorf("too big depth for the nested YAML; it exceeds %d", MaxDepth)
+	}
+	indent, text, ok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || indent < parentIndent {
+		return valueNull, nil
+	}
+	if indent == parentIndent {
+		if !sameIndentSeq || !isYAMLSeqEntry(text) {
+			return valueNull, nil
+		}
+	}
+	if isYAMLSeqEntry(text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok, err := splitYAMLMappingKey(text); err != nil {
+		return nil, err
+	} else if ok {
+		return p.parseMapping(indent)
+	}
+	p.n++
+	return p.parseInlineValue(text, parentIndent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (*Value, error) {
+	a := p.c.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	for {
+		n, text, ok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || n < indent {
+			return a, nil
+		}
+		if n > indent {
+			return nil, fmt.Errorf("unexpected indentation for sequence item; it must be %d", indent)
+		}
+		if !isYAMLSeqEntry(text) {
+			return a, nil
+		}
+		rest := strings.TrimLeft(text[1:], " \t")
+		var v *Value
+		if rest == "" || rest[0] == '#' {
+			p.n++
+			v, err = p.parseBlock(indent, false)
+		} else {
+			// Replace "- " with spaces, so compact nested nodes such as
+			// "- a: 1" are parsed as blocks with the corresponding indentation.
+			p.lines[p.n] = strings.Repeat(" ", indent+len(text)-len(rest)) + rest
+			v, err = p.parseBlock(indent, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+		a.a = append(a.a, v)
+	}
+}
+
+func (p *yamlParser) parseMapping(indent int) (*Value, error) {
+	o := p.c.getValue()
+	o.t = TypeObject
+	o.o.reset()
+	o.o.keysUnescaped = true
+	for {
+		n, text, ok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || n < indent {
+			return o, nil
+		}
+		if n > indent {
+			return nil, fmt.Errorf("unexpected indentation for mapping key; it must be %d", indent)
+		}
+		if isYAMLSeqEntry(text) {
+			return nil, fmt.Errorf("unexpected sequence item inside mapping")
+		}
+		k, rest, ok, err := splitYAMLMappingKey(text)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("missing ':' after mapping key")
+		}
+		if o.o.Get(k) != nil {
+			return nil, fmt.Errorf("duplicate mapping key %q", k)
+		}
+		p.n++
+		var v *Value
+		if rest == "" || rest[0] == '#' {
+			v, err = p.parseBlock(indent, true)
+		} else {
+			v, err = p.parseInlineValue(rest, indent)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse value for key %q: %s", k, err)
+		}
+		kv := o.o.getKV()
+		kv.k = k
+		kv.v = v
+	}
+}
+
+// splitYAMLMappingKey splits text into mapping key and the rest after ':'.
+//
+// ok is false if text doesn't start with a mapping key.
+func splitYAMLMappingKey(text string) (string, string, bool, error) {
+	if strings.HasPrefix(text, "? ") || text == "?" {
+		return "", "", false, fmt.Errorf("complex mapping keys aren't supported")
+	}
+	var k, tail string
+	switch text[0] {
+	case '"', '\'':
+		var err error
+		k, tail, err = parseYAMLQuoted(text)
+		if err != nil {
+			// The text may be a multi-line quoted scalar.
+			return "", "", false, nil
+		}
+	case '[', '{', '#', '|', '>', '&', '*', '!':
+		return "", "", false, nil
+	default:
+		n := strings.Index(text, ": ")
+		if m := strings.Index(text, ":\t"); m >= 0 && (n < 0 || m < n) {
+			n = m
+		}
+		if n < 0 {
+			if !strings.HasSuffix(text, ":") {
+				return "", "", false, nil
+			}
+			n = len(text) - 1
+		}
+		if c := yamlCommentStart(text); c >= 0 && c < n {
+			return "", "", false, nil
+		}
+		k = strings.TrimRight(text[:n], " \t")
+		tail = text[n:]
+	}
+	tail = strings.TrimLeft(tail, " \t")
+	if !strings.HasPrefix(tail, ":") {
+		return "", "", false, nil
+	}
+	tail = tail[1:]
+	if tail != "" && tail[0] != ' ' && tail[0] != '\t' {
+		return "", "", false, nil
+	}
+	return k, strings.TrimLeft(tail, " \t"), true, nil
+}
+
+// yamlCommentStart returns the index of the comment start in plain text or -1.
+func yamlCommentStart(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseInlineValue parses value starting at s on the already consumed line.
+//
+// Continuation lines must be indented more than parentIndent.
+func (p *yamlParser) parseInlineValue(s string, parentIndent int) (*Value, error) {
+	switch s[0] {
+	case '&', '*':
+		return nil, fmt.Errorf("anchors and aliases aren't supported")
+	case '!':
+		return nil, fmt.Errorf("tags aren't supported")
+	case '|', '>':
+		return p.parseBlockScalar(s, parentIndent)
+	case '[', '{':
+		return p.parseFlow(s, parentIndent)
+	case '"', '\'':
+		return p.parseQuoted(s, parentIndent)
+	}
+
+	// Plain scalar, which may span multiple lines.
+	if n := yamlCommentStart(s); n >= 0 {
+		s = strings.TrimRight(s[:n], " \t")
+	}
+	for {
+		n, text, ok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || n <= parentIndent {
+			break
+		}
+		if isYAMLSeqEntry(text) {
+			break
+		}
+		if _, _, ok, _ := splitYAMLMappingKey(text); ok {
+			break
+		}
+		if n := yamlCommentStart(text); n >= 0 {
+			text = strings.TrimRight(text[:n], " \t")
+		}
+		s += " " + text
+		p.n++
+	}
+	return p.newScalar(s)
+}
+
+var errYAMLUnterminatedQuote = errors.New("missing closing quote")
+
+func (p *yamlParser) parseQuoted(s string, parentIndent int) (*Value, error) {
+	for {
+		str, tail, err := parseYAMLQuoted(s)
+		if err == errYAMLUnterminatedQuote && p.n < len(p.lines) {
+			// Fold the next line into the multi-line quoted scalar.
+			line := strings.Trim(p.lines[p.n], " \t")
+			if line == "" {
+				s += "\n"
+			} else if strings.HasSuffix(s, "\n") {
+				s += line
+			} else {
+				s += " " + line
+			}
+			p.n++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tail = strings.TrimLeft(tail, " \t"); tail != "" && tail[0] != '#' {
+			return nil, fmt.Errorf("unexpected tail after quoted scalar: %q", tail)
+		}
+		v := p.c.getValue()
+		v.t = TypeString
+		v.s = str
+		return v, nil
+	}
+}
+
+// parseYAMLQuoted parses single-quoted or double-quoted scalar at s.
+func parseYAMLQuoted(s string) (string, string, error) {
+	if s[0] == '\'' {
+		var b []byte
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				b = append(b, s[1:i+1]...)
+				s = s[i+1:]
+				i = 0
+				continue
+			}
+			if b == nil {
+				return s[1:i], s[i+1:], nil
+			}
+			b = append(b, s[1:i]...)
+			return string(b), s[i+1:], nil
+		}
+		return "", s, errYAMLUnterminatedQuote
+	}
+
+	n := strings.IndexAny(s[1:], "\"\\")
+	if n < 0 {
+		return "", s, errYAMLUnterminatedQuote
+	}
+	if s[n+1] == '"' {
+		return s[1 : n+1], s[n+2:], nil
+	}
+	b := []byte(s[1 : n+1])
+	s = s[n+1:]
+	for len(s) > 0 {
+		ch := s[0]
+		if ch == '"' {
+			return string(b), s[1:], nil
+		}
+		if ch != '\\' {
+			b = append(b, ch)
+			s = s[1:]
+			continue
+		}
+		if len(s) < 2 {
+			break
+		}
+		esc := s[1]
+		s = s[2:]
+		switch esc {
+		case '0':
+			b = append(b, 0)
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 't', '\t':
+			b = append(b, '\t')
+		case 'n':
+			b = append(b, '\n')
+		case 'v':
+			b = append(b, '\v')
+		case 'f':
+			b = append(b, '\f')
+		case 'r':
+			b = append(b, '\r')
+		case 'e':
+			b = append(b, 0x1b)
+		case ' ', '"', '/', '\\':
+			b = append(b, esc)
+		case 'N':
+			b = append(b, "\u0085"...)
+		case '_':
+			b = append(b, "\u00a0"...)
+		case 'L':
+			b = append(b, "\u2028"...)
+		case 'P':
+			b = append(b, "\u2029"...)
+		case 'x', 'u', 'U':
+			size := 2
+			if esc == 'u' {
+				size = 4
+			} else if esc == 'U' {
+				size = 8
+			}
+			if len(s) < size {
+				return "", s, fmt.Errorf("too short escape sequence \\%c", esc)
+			}
+			r, err := strconv.ParseUint(s[:size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", s, fmt.Errorf("invalid escape sequence \\%c%s", esc, s[:size])
+			}
+			b = append(b, string(rune(r))...)
+			s = s[size:]
+		default:
+			return "", s, fmt.Errorf("unknown escape sequence \\%c", esc)
+		}
+	}
+	return "", s, errYAMLUnterminatedQuote
+}
+
+// parseBlockScalar parses literal or folded block scalar with header s.
+func (p *yamlParser) parseBlockScalar(s string, parentIndent int) (*Value, error) {
+	folded := s[0] == '>'
+	chomp := byte(0)
+	indent := 0
+	header := s[1:]
+	for len(header) > 0 && header[0] != ' ' && header[0] != '\t' {
+		switch ch := header[0]; {
+		case (ch == '-' || ch == '+') && chomp == 0:
+			chomp = ch
+		case ch >= '1' && ch <= '9' && indent == 0:
+			indent = parentIndent + 1 + int(ch-'1')
+			if parentIndent < 0 {
+				indent = int(ch - '0')
+			}
+		default:
+			return nil, fmt.Errorf("invalid block scalar header %q", s)
+		}
+		header = header[1:]
+	}
+	if !isYAMLBlank(header) {
+		return nil, fmt.Errorf("unexpected tail after block scalar header %q", s)
+	}
+
+	var lines []string
+	for ; p.n < len(p.lines); p.n++ {
+		line := p.lines[p.n]
+		if strings.TrimLeft(line, " ") == "" {
+			lines = append(lines, "")
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			if n <= parentIndent {
+				break
+			}
+			indent = n
+		}
+		if n < indent {
+			if n > parentIndent {
+				return nil, fmt.Errorf("unexpected indentation in block scalar; it must be at least %d", indent)
+			}
+			break
+		}
+		lines = append(lines, line[indent:])
+	}
+
+	// Trailing empty lines belong to the block scalar only with keep chomping.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	if chomp != '+' {
+		// Return the trailing empty lines, since they may be blank lines
+		// before the next node.
+		p.n -= trailing
+		trailing = 0
+	}
+
+	var b []byte
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case !folded:
+				b = append(b, '\n')
+			case line == "":
+				if prev != "" && isYAMLMoreIndented(prev) {
+					b = append(b, '\n')
+				}
+				b = append(b, '\n')
+			case prev == "":
+			case isYAMLMoreIndented(prev) || isYAMLMoreIndented(line):
+				b = append(b, '\n')
+			default:
+				b = append(b, ' ')
+			}
+		}
+		b = append(b, line...)
+	}
+	if len(lines) > 0 && chomp != '-' {
+		b = append(b, '\n')
+	}
+	if chomp == '+' {
+		b = append(b, strings.Repeat("\n", trailing)...)
+	}
+
+	v := p.c.getValue()
+	v.t = TypeString
+	v.s = b2s(b)
+	return v, nil
+}
+
+func isYAMLMoreIndented(line string) bool {
+	return line[0] == ' ' || line[0] == '\t'
+}
+
+// parseFlow parses flow collection starting at s, which may span multiple lines.
+func (p *yamlParser) parseFlow(s string, parentIndent int) (*Value, error) {
+	s = stripYAMLFlowComment(s)
+	for !isYAMLFlowBalanced(s) && p.n < len(p.lines) {
+		s += "\n" + stripYAMLFlowComment(p.lines[p.n])
+		p.n++
+	}
+	v, tail, err := p.parseFlowValue(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	if tail = strings.TrimLeft(tail, " \t\n"); tail != "" {
+		return nil, fmt.Errorf("unexpected tail after flow collection: %q", tail)
+	}
+	return v, nil
+}
+
+// stripYAMLFlowComment strips the comment from the line in flow context.
+func stripYAMLFlowComment(s string) string {
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote == '"' && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// isYAMLFlowBalanced returns true if all the brackets in s are closed.
+func isYAMLFlowBalanced(s string) bool {
+	depth := 0
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote == '"' && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[' || ch == '{':
+			depth++
+		case ch == ']' || ch == '}':
+			depth--
+		}
+	}
+	return depth <= 0 && quote == 0
+}
+
+func (p *yamlParser) parseFlowValue(s string, depth int) (*Value, string, error) {
+	depth++
+	if depth > MaxDepth {
+		return nil, s, fmt.Errorf("too big depth for the nested YAML; it exceeds %d", MaxDepth)
+	}
+	s = strings.TrimLeft(s, " \t\n")
+	if len(s) == 0 {
+		return nil, s, fmt.Errorf("unexpected end of flow collection")
+	}
+	switch s[0] {
+	case '[':
+		return p.parseFlowSequence(s[1:], depth)
+	case '{':
+		return p.parseFlowMapping(s[1:], depth)
+	case '"', '\'':
+		str, tail, err := parseYAMLQuoted(s)
+		if err != nil {
+			return nil, s, err
+		}
+		v := p.c.getValue()
+		v.t = TypeString
+		v.s = str
+		return v, tail, nil
+	case '&', '*':
+		return nil, s, fmt.Errorf("anchors and aliases aren't supported")
+	case '!':
+		return nil, s, fmt.Errorf("tags aren't supported")
+	}
+	str, tail := readYAMLFlowPlain(s)
+	v, err := p.newScalar(str)
+	if err != nil {
+		return nil, s, err
+	}
+	return v, tail, nil
+}
+
+// readYAMLFlowPlain reads plain scalar in flow context from s.
+func readYAMLFlowPlain(s string) (string, string) {
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		if ch == ',' || ch == ']' || ch == '}' || ch == '\n' {
+			break
+		}
+		if ch == ':' && (i+1 == len(s) || strings.IndexByte(" \t\n,]}", s[i+1]) >= 0) {
+			break
+		}
+		i++
+	}
+	return strings.TrimRight(s[:i], " \t"), s[i:]
+}
+
+func (p *yamlParser) parseFlowSequence(s string, depth int) (*Value, string, error) {
+	a := p.c.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	for {
+		s = strings.TrimLeft(s, " \t\n")
+		if strings.HasPrefix(s, "]") {
+			return a, s[1:], nil
+		}
+		v, tail, err := p.parseFlowValue(s, depth)
+		if err != nil {
+			return nil, tail, err
+		}
+		a.a = append(a.a, v)
+		s = strings.TrimLeft(tail, " \t\n")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+			continue
+		}
+		if !strings.HasPrefix(s, "]") {
+			return nil, s, fmt.Errorf("missing ',' or ']' in flow sequence")
+		}
+	}
+}
+
+func (p *yamlParser) parseFlowMapping(s string, depth int) (*Value, string, error) {
+	o := p.c.getValue()
+	o.t = TypeObject
+	o.o.reset()
+	o.o.keysUnescaped = true
+	for {
+		s = strings.TrimLeft(s, " \t\n")
+		if strings.HasPrefix(s, "}") {
+			return o, s[1:], nil
+		}
+		if len(s) == 0 {
+			return nil, s, fmt.Errorf("missing '}' in flow mapping")
+		}
+		var k string
+		switch s[0] {
+		case '"', '\'':
+			var err error
+			k, s, err = parseYAMLQuoted(s)
+			if err != nil {
+				return nil, s, err
+			}
+		case '[', '{', '?':
+			return nil, s, fmt.Errorf("complex mapping keys aren't supported")
+		default:
+			k, s = readYAMLFlowPlain(s)
+		}
+		if o.o.Get(k) != nil {
+			return nil, s, fmt.Errorf("duplicate mapping key %q", k)
+		}
+		v := valueNull
+		s = strings.TrimLeft(s, " \t\n")
+		if strings.HasPrefix(s, ":") {
+			var err error
+			v, s, err = p.parseFlowMappingValue(s[1:], depth)
+			if err != nil {
+				return nil, s, fmt.Errorf("cannot parse value for key %q: %s", k, err)
+			}
+		}
+		kv := o.o.getKV()
+		kv.k = k
+		kv.v = v
+		s = strings.TrimLeft(s, " \t\n")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+			continue
+		}
+		if !strings.HasPrefix(s, "}") {
+			return nil, s, fmt.Errorf("missing ',' or '}' in flow mapping")
+		}
+	}
+}
+
+func (p *yamlParser) parseFlowMappingValue(s string, depth int) (*Value, string, error) {
+	t := strings.TrimLeft(s, " \t\n")
+	if strings.HasPrefix(t, ",") || strings.HasPrefix(t, "}") {
+		return valueNull, t, nil
+	}
+	return p.parseFlowValue(s, depth)
+}
+
+// newScalar returns plain scalar s resolved according to the YAML 1.2 core schema.
+func (p *yamlParser) newScalar(s string) (*Value, error) {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return valueNull, nil
+	case "true", "True", "TRUE":
+		return valueTrue, nil
+	case "false", "False", "FALSE":
+		return valueFalse, nil
+	}
+	if n, ok := normalizeYAMLNumber(s); ok {
+		v := p.c.getValue()
+		v.t = TypeNumber
+		v.s = n
+		return v, nil
+	}
+	switch strings.TrimLeft(s, "+-") {
+	case ".inf", ".Inf", ".INF", ".nan", ".NaN", ".NAN":
+		return nil, fmt.Errorf("%s cannot be represented in JSON", s)
+	}
+	v := p.c.getValue()
+	v.t = TypeString
+	v.s = s
+	return v, nil
+}
+
+// normalizeYAMLNumber returns JSON number for YAML integer or float s.
+func normalizeYAMLNumber(s string) (string, bool) {
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'o') {
+		base := 16
+		if s[1] == 'o' {
+			base = 8
+		}
+		if n, err := strconv.ParseUint(s[2:], base, 64); err == nil {
+			return strconv.FormatUint(n, 10), true
+		}
+		var bi big.Int
+		if !isYAMLDigits(s[2:], base) {
+			return "", false
+		}
+		bi.SetString(s[2:], base)
+		return bi.String(), true
+	}
+
+	t := strings.TrimPrefix(s, "+")
+	neg := strings.HasPrefix(t, "-")
+	digits := strings.TrimPrefix(t, "-")
+	if isYAMLDigits(digits, 10) {
+		// Strip leading zeros, which aren't allowed in JSON.
+		digits = strings.TrimLeft(digits, "0")
+		if digits == "" {
+			digits = "0"
+		}
+		if neg {
+			return "-" + digits, true
+		}
+		return digits, true
+	}
+	if !isYAMLFloat(digits) {
+		return "", false
+	}
+	if tail, err := validateNumber(t); err == nil && tail == "" {
+		return t, true
+	}
+	f, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return "", false
+	}
+	return string(appendShortestFloat64(nil, f)), true
+}
+
+func isYAMLDigits(s string, base int) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= '0' && ch <= '7':
+		case ch >= '8' && ch <= '9' && base >= 10:
+		case (ch >= 'a' && ch <= 'f' || ch >= 'A' && ch <= 'F') && base == 16:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isYAMLFloat returns true if s matches (\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?
+func isYAMLFloat(s string) bool {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	intDigits := i
+	fracDigits := 0
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+			fracDigits++
+		}
+	}
+	if intDigits == 0 && fracDigits == 0 {
+		return false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		expStart := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == expStart {
+			return false
+		}
+	}
+	return i == len(s)
+}
@@ -0,0 +1,148 @@
+package fastjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		var p Parser
+		v, err := p.ParseYAML([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", s, err)
+		}
+		result := v.String()
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+
+	// Scalars
+	f(``, `null`)
+	f(`# comment only`, `null`)
+	f(`foo`, `"foo"`)
+	f(`foo bar # comment`, `"foo bar"`)
+	f(`foo#bar`, `"foo#bar"`)
+	f(`~`, `null`)
+	f(`Null`, `null`)
+	f(`true`, `true`)
+	f(`FALSE`, `false`)
+	f(`yes`, `"yes"`)
+	f(`123`, `123`)
+	f(`-0012`, `-12`)
+	f(`+7`, `7`)
+	f(`0o17`, `15`)
+	f(`0x1F`, `31`)
+	f(`0xffffffffffffffffff`, `4722366482869645213695`)
+	f(`0x`, `"0x"`)
+	f(`1.5`, `1.5`)
+	f(`-1.5e3`, `-1.5e3`)
+	f(`.5`, `0.5`)
+	f(`1.`, `1`)
+	f(`+1.5E-2`, `1.5E-2`)
+	f(`1e`, `"1e"`)
+	f(`1_000`, `"1_000"`)
+	f(`0.1.2`, `"0.1.2"`)
+	f(`'it''s'`, `"it's"`)
+	f(`''`, `""`)
+	f(`"a\tb\u00e9\x41\U0001F600\"\\\/"`, `"a\tbéA😀\"\\/"`)
+	f(`"123"`, `"123"`)
+	f(`"multi
+  line
+
+  string"`, `"multi line\nstring"`)
+	f("plain\n  continued\n  # comment\n  line", `"plain continued line"`)
+
+	// Block mappings
+	f(`a: 1`, `{"a":1}`)
+	f("a: 1\nb: two\nc:\nd: ~\n", `{"a":1,"b":"two","c":null,"d":null}`)
+	f("# header\n---\nname: foo # trailing\n\nnested:\n  x: 1\n  deeper:\n    y: true\nafter: 2\n...\n", `{"name":"foo","nested":{"x":1,"deeper":{"y":true}},"after":2}`)
+	f(`"quoted key": 1`, `{"quoted key":1}`)
+	f(`'a: b': c`, `{"a: b":"c"}`)
+	f(`url: http://example.com:8080/path`, `{"url":"http://example.com:8080/path"}`)
+	f(`a:b: c`, `{"a:b":"c"}`)
+	f(`1: one`, `{"1":"one"}`)
+	f("a:\n  b:\n    c:\n", `{"a":{"b":{"c":null}}}`)
+	f("key:   \t\n  value", `{"key":"value"}`)
+	f("a: 1\r\nb: 2\r\n", `{"a":1,"b":2}`)
+	f("\ufeffa: 1", `{"a":1}`)
+	f("--- a: 1", `{"a":1}`)
+
+	// Block sequences
+	f("- 1\n- two\n-\n- - x\n  - y\n", `[1,"two",null,["x","y"]]`)
+	f("items:\n- a\n- b\nnext: 1", `{"items":["a","b"],"next":1}`)
+	f("items:\n  - a\n  -   b\n", `{"items":["a","b"]}`)
+	f("- name: a\n  id: 1\n- name: b\n  tags: [x, y]\n-\n  name: c\n", `[{"name":"a","id":1},{"name":"b","tags":["x","y"]},{"name":"c"}]`)
+	f("- - - deep\n", `[[["deep"]]]`)
+	f("matrix:\n  - [1, 2]\n  - {a: 1}\n", `{"matrix":[[1,2],{"a":1}]}`)
+
+	// Flow collections
+	f(`[]`, `[]`)
+	f(`{}`, `{}`)
+	f(`[1, "two", 'three', four, null, true, 1.5]`, `[1,"two","three","four",null,true,1.5]`)
+	f(`[a, b,]`, `["a","b"]`)
+	f(`{a: 1, "b": [x, {c: d}], e, f:}`, `{"a":1,"b":["x",{"c":"d"}],"e":null,"f":null}`)
+	f(`{"a":1,"b":"x"}`, `{"a":1,"b":"x"}`)
+	f("list: [\n  1, # one\n  2\n]\nafter: x", `{"list":[1,2],"after":"x"}`)
+	f(`[http://x.y/z, "a, b"]`, `["http://x.y/z","a, b"]`)
+
+	// Block scalars
+	f("text: |\n  line 1\n  line 2\n\n  line 4\nnext: 1", `{"text":"line 1\nline 2\n\nline 4\n","next":1}`)
+	f("text: |-\n  a\n  b\n\n", `{"text":"a\nb"}`)
+	f("text: |+\n  a\n\n\nnext: 1", `{"text":"a\n\n\n","next":1}`)
+	f("text: >\n  folded\n  text\n\n  para\n    more\n  end\n", `{"text":"folded text\npara\n  more\nend\n"}`)
+	f("text: >-\n  a\n  b\n", `{"text":"a b"}`)
+	f("text: |2\n    indented\n  x\n", `{"text":"  indented\nx\n"}`)
+	f("- |\n  a # not a comment\n- b\n", `["a # not a comment\n","b"]`)
+	f("text: |\nnext: 1", `{"text":"","next":1}`)
+	f("|\n a\n b", `"a\nb\n"`)
+}
+
+func TestParseYAMLError(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		v, err := ParseYAML([]byte(s))
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %q; got %s", s, v)
+		}
+	}
+
+	f("a: 1\n---\nb: 2")
+	f("a: 1\n...\nb: 2")
+	f("%YAML 1.2\n---\na: 1")
+	f("a: 1\na: 2")
+	f(`{a: 1, a: 2}`)
+	f("a: 1\n  b: 2")
+	f("a:\n  - 1\n   - 2")
+	f("a: 1\n- 2")
+	f("- 1\nb: 2")
+	f("a:\n\tb: 1")
+	f("a: &anchor 1")
+	f("a: *alias")
+	f("a: !!str 1")
+	f("? complex\n: key")
+	f(`a: .inf`)
+	f(`-.Inf`)
+	f(`.nan`)
+	f(`"unterminated`)
+	f(`'unterminated`)
+	f(`"bad \q escape"`)
+	f(`"bad \u12"`)
+	f(`"a" b`)
+	f(`[1, 2`)
+	f(`[1: 2]`)
+	f(`{a: 1]`)
+	f(`{[a]: 1}`)
+	f(`[1] x`)
+	f("text: |x\n  a")
+	f("text: |2\n   a\n  b\n a")
+	f(strings.Repeat("[", MaxDepth+1) + strings.Repeat("]", MaxDepth+1))
+
+	var b strings.Builder
+	for i := 0; i <= MaxDepth; i++ {
+		b.WriteString(strings.Repeat(" ", i) + "a:\n")
+	}
+	f(b.String())
+}